@@ -1,6 +1,7 @@
 package bindata
 
 import (
+	"bytes"
 	"embed"
 )
 
@@ -22,3 +23,14 @@ func MustAsset(name string) []byte {
 
 	return data
 }
+
+// namespacePlaceholder stands for the namespace the operator and Kueue are installed
+// into. Assets use it for their own namespace and for references to objects in it, such
+// as service account subjects and webhook services.
+const namespacePlaceholder = "${NAMESPACE}"
+
+// MustNamespacedAsset reads the named file like MustAsset and replaces the namespace
+// placeholder with namespace.
+func MustNamespacedAsset(name, namespace string) []byte {
+	return bytes.ReplaceAll(MustAsset(name), []byte(namespacePlaceholder), []byte(namespace))
+}
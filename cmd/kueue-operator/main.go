@@ -6,7 +6,9 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/openshift/kueue-operator/pkg/cmd/diagnose"
 	"github.com/openshift/kueue-operator/pkg/cmd/operator"
+	"github.com/openshift/kueue-operator/pkg/cmd/render"
 )
 
 func main() {
@@ -34,5 +36,7 @@ func NewKueueOperatorCommand() *cobra.Command {
 	}
 
 	cmd.AddCommand(operator.NewOperator())
+	cmd.AddCommand(diagnose.NewDiagnose())
+	cmd.AddCommand(render.NewRender())
 	return cmd
 }
@@ -0,0 +1,46 @@
+package admissioncheck
+
+import (
+	"context"
+	"fmt"
+
+	kueuev1beta1 "sigs.k8s.io/kueue/apis/kueue/v1beta1"
+)
+
+const (
+	// ApprovalPluginName is the compiled-in plugin that waits for a workload to be
+	// approved or rejected through an annotation, e.g. by a budget owner.
+	ApprovalPluginName = "Approval"
+
+	// ApprovalAnnotationParameter names the annotation the Approval plugin reads.
+	ApprovalAnnotationParameter = "annotation"
+	// DefaultApprovalAnnotation is read when the annotation parameter is not set.
+	DefaultApprovalAnnotation = "kueue.openshift.io/approved"
+)
+
+func init() {
+	Register(ApprovalPluginName, func(parameters map[string]string) (Plugin, error) {
+		annotation := parameters[ApprovalAnnotationParameter]
+		if len(annotation) == 0 {
+			annotation = DefaultApprovalAnnotation
+		}
+		return &approvalPlugin{annotation: annotation}, nil
+	})
+}
+
+// approvalPlugin admits workloads annotated with "true" and rejects those annotated
+// with "false".
+type approvalPlugin struct {
+	annotation string
+}
+
+func (p *approvalPlugin) Check(_ context.Context, workload *kueuev1beta1.Workload) (Result, error) {
+	switch workload.Annotations[p.annotation] {
+	case "true":
+		return Result{State: kueuev1beta1.CheckStateReady, Message: "Approved"}, nil
+	case "false":
+		return Result{State: kueuev1beta1.CheckStateRejected, Message: "Rejected"}, nil
+	default:
+		return Result{State: kueuev1beta1.CheckStatePending, Message: fmt.Sprintf("Waiting for the %s annotation to be set to true or false", p.annotation)}, nil
+	}
+}
@@ -0,0 +1,88 @@
+package admissioncheck
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	kueuev1beta1 "sigs.k8s.io/kueue/apis/kueue/v1beta1"
+)
+
+// DefaultHTTPTimeout bounds a callout when the Kueue CR does not set a timeout.
+const DefaultHTTPTimeout = 10 * time.Second
+
+// CheckRequest is the body POSTed to an HTTP plugin.
+type CheckRequest struct {
+	// AdmissionCheck is the name of the check to decide.
+	AdmissionCheck string `json:"admissionCheck"`
+	// Parameters are the parameters of the check in the Kueue CR.
+	Parameters map[string]string `json:"parameters,omitempty"`
+	// Workload is the workload to decide on.
+	Workload *kueuev1beta1.Workload `json:"workload"`
+}
+
+// httpPlugin calls out to a service that answers a CheckRequest with a Result.
+type httpPlugin struct {
+	admissionCheck string
+	url            string
+	parameters     map[string]string
+	client         *http.Client
+}
+
+// NewHTTP returns a plugin that POSTs a CheckRequest to rawURL and expects a Result
+// as JSON in a 200 response.
+func NewHTTP(admissionCheck, rawURL string, timeout time.Duration, parameters map[string]string) (Plugin, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL %q: %w", rawURL, err)
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return nil, fmt.Errorf("invalid URL %q: the scheme must be http or https", rawURL)
+	}
+	if timeout <= 0 {
+		timeout = DefaultHTTPTimeout
+	}
+	return &httpPlugin{
+		admissionCheck: admissionCheck,
+		url:            rawURL,
+		parameters:     parameters,
+		client:         &http.Client{Timeout: timeout},
+	}, nil
+}
+
+func (p *httpPlugin) Check(ctx context.Context, workload *kueuev1beta1.Workload) (Result, error) {
+	body, err := json.Marshal(CheckRequest{AdmissionCheck: p.admissionCheck, Parameters: p.parameters, Workload: workload})
+	if err != nil {
+		return Result{}, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return Result{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return Result{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return Result{}, fmt.Errorf("%s answered %s: %s", p.url, resp.Status, bytes.TrimSpace(message))
+	}
+
+	result := Result{}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&result); err != nil {
+		return Result{}, fmt.Errorf("invalid answer from %s: %w", p.url, err)
+	}
+	switch result.State {
+	case kueuev1beta1.CheckStateReady, kueuev1beta1.CheckStatePending, kueuev1beta1.CheckStateRetry, kueuev1beta1.CheckStateRejected:
+		return result, nil
+	default:
+		return Result{}, fmt.Errorf("invalid answer from %s: unknown state %q", p.url, result.State)
+	}
+}
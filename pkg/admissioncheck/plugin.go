@@ -0,0 +1,91 @@
+// Package admissioncheck hosts the plugins that decide the admission checks the
+// operator runs for Kueue. Plugins are either compiled into the operator and
+// registered by name, or an HTTP service the operator calls out to.
+package admissioncheck
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kueuev1beta1 "sigs.k8s.io/kueue/apis/kueue/v1beta1"
+)
+
+// ControllerName is the controllerName of the AdmissionChecks decided by plugins.
+const ControllerName = "kueue.openshift.io/admission-check-plugin"
+
+// Result is the decision of a plugin for a workload.
+type Result struct {
+	// State is Ready to admit the workload, Pending to decide later, Retry to release
+	// its quota and check again, or Rejected to deactivate it.
+	State kueuev1beta1.CheckState `json:"state"`
+	// Message explains the state to the owner of the workload.
+	Message string `json:"message,omitempty"`
+}
+
+// Plugin decides an admission check.
+type Plugin interface {
+	// Check is called while the workload has quota reserved and the check is Pending.
+	// Errors leave the check Pending and the workload is checked again later.
+	Check(ctx context.Context, workload *kueuev1beta1.Workload) (Result, error)
+}
+
+// Factory builds a plugin from the parameters of its entry in the Kueue CR.
+type Factory func(parameters map[string]string) (Plugin, error)
+
+var (
+	registryLock sync.RWMutex
+	registry     = map[string]Factory{}
+)
+
+// Register makes a compiled-in plugin available under name. It is meant to be called
+// from init functions and panics when the name is taken.
+func Register(name string, factory Factory) {
+	registryLock.Lock()
+	defer registryLock.Unlock()
+	if _, ok := registry[name]; ok {
+		panic(fmt.Sprintf("admission check plugin %q is already registered", name))
+	}
+	registry[name] = factory
+}
+
+// New builds the registered plugin of the given name.
+func New(name string, parameters map[string]string) (Plugin, error) {
+	registryLock.RLock()
+	factory, ok := registry[name]
+	registryLock.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown admission check plugin %q, registered plugins are %v", name, Registered())
+	}
+	return factory(parameters)
+}
+
+// Registered returns the sorted names of the registered plugins.
+func Registered() []string {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// BuildAdmissionCheck returns an AdmissionCheck decided by a plugin of the operator.
+func BuildAdmissionCheck(name string) *kueuev1beta1.AdmissionCheck {
+	return &kueuev1beta1.AdmissionCheck{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: kueuev1beta1.GroupVersion.String(),
+			Kind:       "AdmissionCheck",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Spec: kueuev1beta1.AdmissionCheckSpec{
+			ControllerName: ControllerName,
+		},
+	}
+}
@@ -0,0 +1,84 @@
+package admissioncheck
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kueuev1beta1 "sigs.k8s.io/kueue/apis/kueue/v1beta1"
+)
+
+func TestApprovalPlugin(t *testing.T) {
+	plugin, err := New(ApprovalPluginName, map[string]string{ApprovalAnnotationParameter: "example.com/approved"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for value, want := range map[string]kueuev1beta1.CheckState{
+		"true":  kueuev1beta1.CheckStateReady,
+		"false": kueuev1beta1.CheckStateRejected,
+		"":      kueuev1beta1.CheckStatePending,
+		"maybe": kueuev1beta1.CheckStatePending,
+	} {
+		workload := &kueuev1beta1.Workload{ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{"example.com/approved": value},
+		}}
+		result, err := plugin.Check(context.Background(), workload)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.State != want {
+			t.Errorf("Expected %s for %q, got %s", want, value, result.State)
+		}
+	}
+}
+
+func TestNewUnknownPlugin(t *testing.T) {
+	if _, err := New("Unknown", nil); err == nil || !strings.Contains(err.Error(), ApprovalPluginName) {
+		t.Errorf("Expected an error listing the registered plugins, got %v", err)
+	}
+}
+
+func TestHTTPPlugin(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request := CheckRequest{}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		switch request.Workload.Name {
+		case "licensed":
+			_ = json.NewEncoder(w).Encode(Result{State: kueuev1beta1.CheckStateReady, Message: request.Parameters["seat"]})
+		case "invalid":
+			_ = json.NewEncoder(w).Encode(Result{State: "Maybe"})
+		default:
+			http.Error(w, "no license", http.StatusForbidden)
+		}
+	}))
+	defer server.Close()
+
+	plugin, err := NewHTTP("license", server.URL, 0, map[string]string{"seat": "gpu"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := plugin.Check(context.Background(), &kueuev1beta1.Workload{ObjectMeta: metav1.ObjectMeta{Name: "licensed"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.State != kueuev1beta1.CheckStateReady || result.Message != "gpu" {
+		t.Errorf("Unexpected result %+v", result)
+	}
+	if _, err := plugin.Check(context.Background(), &kueuev1beta1.Workload{ObjectMeta: metav1.ObjectMeta{Name: "invalid"}}); err == nil || !strings.Contains(err.Error(), "unknown state") {
+		t.Errorf("Expected an error about the unknown state, got %v", err)
+	}
+	if _, err := plugin.Check(context.Background(), &kueuev1beta1.Workload{ObjectMeta: metav1.ObjectMeta{Name: "other"}}); err == nil || !strings.Contains(err.Error(), "403") {
+		t.Errorf("Expected an error about the status code, got %v", err)
+	}
+
+	if _, err := NewHTTP("license", "unix:///run/check.sock", 0, nil); err == nil {
+		t.Error("Expected an error for a non-HTTP URL")
+	}
+}
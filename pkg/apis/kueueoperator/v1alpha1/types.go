@@ -1,7 +1,9 @@
 package v1alpha1
 
 import (
+	configv1 "github.com/openshift/api/config/v1"
 	operatorv1 "github.com/openshift/api/operator/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	configapi "sigs.k8s.io/kueue/apis/config/v1beta1"
 )
@@ -31,6 +33,99 @@ type KueueOperandSpec struct {
 	Config KueueConfiguration `json:"config"`
 	// Image
 	Image string `json:"image"`
+	// Defaults configures a ResourceFlavor, a ClusterQueue and LocalQueues that the
+	// operator creates so that jobs can be submitted right after install.
+	// The objects are created when missing and never updated: changes made to them
+	// afterwards are kept, and so are objects created from earlier defaults. The
+	// DefaultsOutdated condition lists the latter, deleting them has them recreated
+	// from the current defaults.
+	// +optional
+	Defaults *KueueDefaults `json:"defaults,omitempty"`
+	// LocalQueueProvisioning creates a LocalQueue in every namespace that carries a
+	// label naming a ClusterQueue, and deletes it again when the label is removed.
+	// +optional
+	LocalQueueProvisioning *LocalQueueProvisioning `json:"localQueueProvisioning,omitempty"`
+	// FlavorDiscovery maintains a ResourceFlavor for every group of nodes that share
+	// the same values of a set of node labels, such as instance types or GPU models.
+	// +optional
+	FlavorDiscovery *FlavorDiscovery `json:"flavorDiscovery,omitempty"`
+	// QuotaManagement compares the nominal quotas of the ClusterQueues with the
+	// allocatable capacity of the nodes of each ResourceFlavor and recommends quotas
+	// that fit the capacity.
+	// +optional
+	QuotaManagement *QuotaManagement `json:"quotaManagement,omitempty"`
+	// QueueAccess grants groups the batch admin or batch user role of Kueue in
+	// namespaces, so that teams can manage and use their queues without cluster-admin.
+	// The operator owns the RoleBindings it creates and deletes them when an entry is
+	// removed.
+	// +listType=map
+	// +listMapKey=group
+	// +listMapKey=role
+	// +optional
+	QueueAccess []QueueAccess `json:"queueAccess,omitempty"`
+	// MultiKueue sets this cluster up as a MultiKueue manager that dispatches workloads
+	// to worker clusters, or as a worker that a manager dispatches to.
+	// +optional
+	MultiKueue *MultiKueue `json:"multiKueue,omitempty"`
+	// ProvisioningRequest toggles admission checks backed by cluster autoscaler
+	// ProvisioningRequests. When not set, Kueue keeps its defaults.
+	// +optional
+	ProvisioningRequest *ProvisioningRequest `json:"provisioningRequest,omitempty"`
+	// TopologyAwareScheduling enables the TopologyAwareScheduling feature gate of Kueue,
+	// creates Topology objects from node labels and links ResourceFlavors to them.
+	// +optional
+	TopologyAwareScheduling *TopologyAwareScheduling `json:"topologyAwareScheduling,omitempty"`
+	// WorkloadPriorities is the catalogue of WorkloadPriorityClasses of the cluster.
+	// WorkloadPriorityClasses created for it and no longer listed are deleted.
+	// +optional
+	WorkloadPriorities *WorkloadPriorities `json:"workloadPriorities,omitempty"`
+	// AdmissionChecks are AdmissionChecks decided by plugins the operator runs, such as
+	// a budget approval or a license server. The operator creates the AdmissionChecks,
+	// ClusterQueues list them in spec.admissionChecks.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	AdmissionChecks []AdmissionCheckPlugin `json:"admissionChecks,omitempty"`
+	// AutomaticRollback watches the kueue Deployment after spec.image or the
+	// configuration changed, and rolls back to the last image and configuration
+	// revision that were healthy when the Deployment does not become available in
+	// time. A rollback sets spec.image, and spec.config.rollbackTo when the
+	// configuration changed. The operator unsets spec.config.rollbackTo again once
+	// spec.config is changed, status.rollout.pinnedConfig reports it until then.
+	// +optional
+	AutomaticRollback *AutomaticRollback `json:"automaticRollback,omitempty"`
+	// Canary runs a second Kueue manager with another image that serves the webhooks of
+	// some namespaces, so that a new Kueue version can be tried there first. Both
+	// managers share the configuration and the leader election lease, so the scheduler
+	// and the controllers only run in the manager that holds the lease, which is the
+	// stable one while it is up. Set action to promote or abort it.
+	// +optional
+	Canary *Canary `json:"canary,omitempty"`
+}
+
+// KueueDefaults describes the queueing objects the operator bootstraps.
+type KueueDefaults struct {
+	// ResourceFlavorName is the name of the default ResourceFlavor.
+	// +kubebuilder:default=default-flavor
+	// +optional
+	ResourceFlavorName string `json:"resourceFlavorName,omitempty"`
+	// ClusterQueueName is the name of the default ClusterQueue.
+	// Its nominal quota is the allocatable capacity of the schedulable nodes.
+	// +kubebuilder:default=cluster-queue
+	// +optional
+	ClusterQueueName string `json:"clusterQueueName,omitempty"`
+	// LocalQueueName is the name of the LocalQueue created in every selected namespace.
+	// +kubebuilder:default=default
+	// +optional
+	LocalQueueName string `json:"localQueueName,omitempty"`
+	// NamespaceSelector selects the namespaces that get a LocalQueue and that may use
+	// the ClusterQueue. No LocalQueues are created when it is not set.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+	// CoveredResources are the resources the ClusterQueue sets a quota for.
+	// +kubebuilder:default={cpu,memory}
+	// +optional
+	CoveredResources []corev1.ResourceName `json:"coveredResources,omitempty"`
 }
 
 type KueueConfiguration struct {
@@ -47,11 +142,546 @@ type KueueConfiguration struct {
 	// Supports https://github.com/kubernetes-sigs/kueue/blob/release-0.10/keps/2937-resource-transformer/README.md
 	// +optional
 	Resources *configapi.Resources `json:"resources,omitempty"`
+	// revisionHistoryLimit is the number of rendered configurations kept as
+	// revisions to roll back to. The revision in use is always kept.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=50
+	// +kubebuilder:default=5
+	// +optional
+	RevisionHistoryLimit *int32 `json:"revisionHistoryLimit,omitempty"`
+	// rollbackTo restores the configuration of a revision listed in
+	// status.configRevisions instead of rendering this configuration.
+	// Unset it to apply this configuration again. When spec.automaticRollback set it,
+	// the operator unsets it once this configuration is changed.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	RollbackTo *int64 `json:"rollbackTo,omitempty"`
+	// changePolicy is what happens to the running Kueue manager when the content of
+	// its configuration or of its webhook certificate changes. Restart rolls the
+	// kueue Deployment out. OnNextRestart only updates the ConfigMap, the manager
+	// reads it the next time its pods restart. Kueue has no hot reload of its
+	// configuration.
+	// +kubebuilder:default=Restart
+	// +optional
+	ChangePolicy ConfigChangePolicy `json:"changePolicy,omitempty"`
 }
 
+// ConfigChangePolicy is what happens to the Kueue manager when its configuration changes.
+// +kubebuilder:validation:Enum=Restart;OnNextRestart
+type ConfigChangePolicy string
+
+const (
+	// ConfigChangePolicyRestart restarts the Kueue manager on every change.
+	ConfigChangePolicyRestart ConfigChangePolicy = "Restart"
+	// ConfigChangePolicyOnNextRestart leaves the Kueue manager running until its pods
+	// restart for another reason.
+	ConfigChangePolicyOnNextRestart ConfigChangePolicy = "OnNextRestart"
+)
+
 // KueueStatus defines the observed state of Kueue
 type KueueStatus struct {
 	operatorv1.OperatorStatus `json:",inline"`
+	// relatedObjects is a list of objects that are "interesting" or related to this operator.
+	// It lists every object the operator manages for Kueue so that must-gather
+	// and inventory tooling can discover them.
+	// +optional
+	RelatedObjects []configv1.ObjectReference `json:"relatedObjects,omitempty"`
+	// versions are the versions of the operator and of the Kueue operand it manages.
+	// +optional
+	Versions []configv1.OperandVersion `json:"versions,omitempty"`
+	// resources reports the outcome of each step of the last reconcile.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Resources []ResourceStatus `json:"resources,omitempty"`
+	// localQueues lists the LocalQueues provisioned for labelled namespaces.
+	// +listType=map
+	// +listMapKey=namespace
+	// +listMapKey=name
+	// +optional
+	LocalQueues []LocalQueueMapping `json:"localQueues,omitempty"`
+	// discoveredFlavors lists the ResourceFlavors derived from node labels.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	DiscoveredFlavors []DiscoveredFlavor `json:"discoveredFlavors,omitempty"`
+	// flavorCapacities compares the allocatable capacity of the nodes of each
+	// ResourceFlavor with the nominal quotas of the ClusterQueues using it.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	FlavorCapacities []FlavorCapacity `json:"flavorCapacities,omitempty"`
+	// quotaRecommendations are nominal quotas that share the capacity of each
+	// ResourceFlavor between the ClusterQueues in proportion to their current quotas.
+	// Managed ClusterQueues share the capacity the other ClusterQueues leave.
+	// +listType=map
+	// +listMapKey=clusterQueue
+	// +listMapKey=flavor
+	// +optional
+	QuotaRecommendations []QuotaRecommendation `json:"quotaRecommendations,omitempty"`
+	// queueAccess lists the namespaces each group was granted access to.
+	// +listType=map
+	// +listMapKey=group
+	// +listMapKey=role
+	// +optional
+	QueueAccess []QueueAccessStatus `json:"queueAccess,omitempty"`
+	// multiKueueWorkers reports the worker clusters of a MultiKueue manager.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	MultiKueueWorkers []MultiKueueWorkerStatus `json:"multiKueueWorkers,omitempty"`
+	// topologies reports the Topology objects created for topologyAwareScheduling.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Topologies []TopologyStatus `json:"topologies,omitempty"`
+	// workloadPriorityClasses lists the WorkloadPriorityClasses of the catalogue.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	WorkloadPriorityClasses []WorkloadPriorityClassStatus `json:"workloadPriorityClasses,omitempty"`
+	// configRevisions lists the rendered configurations kept to roll back to,
+	// oldest first.
+	// +listType=map
+	// +listMapKey=revision
+	// +optional
+	ConfigRevisions []ConfigRevision `json:"configRevisions,omitempty"`
+	// rollout reports the last change of the image or configuration watched for
+	// spec.automaticRollback.
+	// +optional
+	Rollout *RolloutStatus `json:"rollout,omitempty"`
+	// canary compares the canary Kueue manager with the stable one, and keeps the
+	// outcome of the last canary. The comparison only covers the replicas and the
+	// container restarts of the two Deployments, not admission or webhook metrics.
+	// +optional
+	Canary *CanaryStatus `json:"canary,omitempty"`
+	// admissionChecks reports the AdmissionChecks decided by plugins.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	AdmissionChecks []AdmissionCheckPluginStatus `json:"admissionChecks,omitempty"`
+}
+
+// ResourceState is the outcome of a reconcile step.
+type ResourceState string
+
+const (
+	// ResourceStateApplied means every object of the step was applied.
+	ResourceStateApplied ResourceState = "Applied"
+	// ResourceStateFailed means applying an object of the step failed.
+	ResourceStateFailed ResourceState = "Failed"
+	// ResourceStateSkipped means the step did not run because a step it depends on did not succeed.
+	ResourceStateSkipped ResourceState = "Skipped"
+)
+
+// ResourceStatus reports the outcome of one step of the reconcile pipeline.
+type ResourceStatus struct {
+	// name of the reconcile step.
+	// +required
+	Name string `json:"name"`
+	// state of the step, one of Applied, Failed or Skipped.
+	// +kubebuilder:validation:Enum=Applied;Failed;Skipped
+	// +required
+	State ResourceState `json:"state"`
+	// message explains why the step failed or was skipped.
+	// +optional
+	Message string `json:"message,omitempty"`
+	// lastTransitionTime is the last time the state of the step changed.
+	// +optional
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+}
+
+// LocalQueueProvisioning configures the LocalQueues created for labelled namespaces.
+type LocalQueueProvisioning struct {
+	// LabelKey is the namespace label whose value is the name of the ClusterQueue
+	// the LocalQueue of the namespace points at.
+	// +kubebuilder:default="kueue.openshift.io/queue"
+	// +optional
+	LabelKey string `json:"labelKey,omitempty"`
+	// LocalQueueName is the name of the LocalQueue created in labelled namespaces.
+	// It defaults to a name other than the LocalQueue of spec.defaults, so that both
+	// can be used together. A namespace that already has a LocalQueue of this name not
+	// provisioned by the operator keeps it and is reported by the
+	// LocalQueueNameConflict condition.
+	// +kubebuilder:default=namespace-queue
+	// +optional
+	LocalQueueName string `json:"localQueueName,omitempty"`
+}
+
+// LocalQueueMapping reports a LocalQueue provisioned for a labelled namespace.
+type LocalQueueMapping struct {
+	// namespace of the LocalQueue.
+	// +required
+	Namespace string `json:"namespace"`
+	// name of the LocalQueue.
+	// +required
+	Name string `json:"name"`
+	// clusterQueue the LocalQueue points at.
+	// +required
+	ClusterQueue string `json:"clusterQueue"`
+}
+
+// FlavorDiscovery configures the ResourceFlavors derived from node labels.
+type FlavorDiscovery struct {
+	// NodeLabels are the label keys that tell node pools apart. Nodes with the same
+	// values for these labels share a ResourceFlavor whose nodeLabels are those values.
+	// Labels a node does not have are left out, nodes that have none are ignored.
+	// +kubebuilder:default={"node.kubernetes.io/instance-type","nvidia.com/gpu.product"}
+	// +listType=set
+	// +optional
+	NodeLabels []string `json:"nodeLabels,omitempty"`
+	// NodeSelector restricts discovery to the selected nodes. All nodes are
+	// considered when it is not set.
+	// +optional
+	NodeSelector *metav1.LabelSelector `json:"nodeSelector,omitempty"`
+}
+
+// DiscoveredFlavor reports a ResourceFlavor derived from node labels.
+type DiscoveredFlavor struct {
+	// name of the ResourceFlavor.
+	// +required
+	Name string `json:"name"`
+	// nodeLabels of the ResourceFlavor.
+	// +optional
+	NodeLabels map[string]string `json:"nodeLabels,omitempty"`
+	// nodeTaints of the ResourceFlavor, the NoSchedule and NoExecute taints every
+	// node of the flavor has.
+	// +optional
+	NodeTaints []corev1.Taint `json:"nodeTaints,omitempty"`
+	// nodes is the number of nodes of the flavor.
+	// +required
+	Nodes int32 `json:"nodes"`
+}
+
+// QuotaManagement configures the quota recommendations.
+type QuotaManagement struct {
+	// AutoScale sets the nominal quotas of the ClusterQueues annotated with
+	// kueue.openshift.io/quota-management=managed to the recommendations.
+	// Other ClusterQueues are never changed.
+	// +optional
+	AutoScale bool `json:"autoScale,omitempty"`
+}
+
+// FlavorCapacity reports the capacity of a ResourceFlavor.
+type FlavorCapacity struct {
+	// name of the ResourceFlavor.
+	// +required
+	Name string `json:"name"`
+	// nodes is the number of schedulable nodes that match the nodeLabels of the flavor.
+	// +required
+	Nodes int32 `json:"nodes"`
+	// allocatable is the allocatable capacity of those nodes.
+	// +optional
+	Allocatable corev1.ResourceList `json:"allocatable,omitempty"`
+	// nominalQuota is the sum of the nominal quotas of the ClusterQueues for the flavor.
+	// +optional
+	NominalQuota corev1.ResourceList `json:"nominalQuota,omitempty"`
+}
+
+// QuotaRecommendation is the recommended nominal quota of a ClusterQueue for a flavor.
+type QuotaRecommendation struct {
+	// clusterQueue the recommendation is for.
+	// +required
+	ClusterQueue string `json:"clusterQueue"`
+	// flavor the recommendation is for.
+	// +required
+	Flavor string `json:"flavor"`
+	// nominalQuota recommended for the resources of the flavor that nodes report.
+	// +optional
+	NominalQuota corev1.ResourceList `json:"nominalQuota,omitempty"`
+	// managed is true when the ClusterQueue is annotated for automatic scaling.
+	// +optional
+	Managed bool `json:"managed,omitempty"`
+}
+
+// QueueAccessRole is the Kueue role granted to a group.
+// +kubebuilder:validation:Enum=Admin;User
+type QueueAccessRole string
+
+const (
+	// QueueAccessRoleAdmin binds the kueue-batch-admin-role ClusterRole, which allows
+	// managing the LocalQueues of the namespace and the workloads submitted to them.
+	QueueAccessRoleAdmin QueueAccessRole = "Admin"
+	// QueueAccessRoleUser binds the kueue-batch-user-role ClusterRole, which allows
+	// submitting jobs to the LocalQueues of the namespace.
+	QueueAccessRoleUser QueueAccessRole = "User"
+)
+
+// QueueAccess grants a group a Kueue role in a set of namespaces.
+type QueueAccess struct {
+	// Group is the name of the group, usually an OpenShift Group.
+	// +required
+	Group string `json:"group"`
+	// Role granted to the group.
+	// +kubebuilder:default=User
+	// +required
+	Role QueueAccessRole `json:"role"`
+	// Namespaces the role is granted in.
+	// +listType=set
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+	// NamespaceSelector selects further namespaces the role is granted in, for
+	// example the namespaces labelled with a ClusterQueue for LocalQueue provisioning.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+}
+
+// QueueAccessStatus reports the RoleBindings of a group.
+type QueueAccessStatus struct {
+	// group the RoleBindings are for.
+	// +required
+	Group string `json:"group"`
+	// role bound to the group.
+	// +required
+	Role QueueAccessRole `json:"role"`
+	// namespaces the group has a RoleBinding in.
+	// +listType=set
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+	// message reports problems such as a missing group or namespace.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// MultiKueueRole is the role of the cluster in a MultiKueue setup.
+// +kubebuilder:validation:Enum=Manager;Worker
+type MultiKueueRole string
+
+const (
+	// MultiKueueRoleManager dispatches workloads to the worker clusters.
+	MultiKueueRoleManager MultiKueueRole = "Manager"
+	// MultiKueueRoleWorker runs the workloads a manager dispatches to it.
+	MultiKueueRoleWorker MultiKueueRole = "Worker"
+)
+
+// MultiKueue configures MultiKueue.
+// +kubebuilder:validation:XValidation:rule="self.role == 'Manager' || !has(self.workers) || size(self.workers) == 0",message="workers can only be set for the Manager role"
+type MultiKueue struct {
+	// Role of the cluster.
+	// A Manager gets a MultiKueueCluster for every worker, a MultiKueueConfig and an
+	// AdmissionCheck, both named multikueue, that ClusterQueues reference to dispatch
+	// their workloads.
+	// A Worker gets a ServiceAccount, with the permissions a manager needs, and a token
+	// Secret to build the kubeconfig of the worker from.
+	// +required
+	Role MultiKueueRole `json:"role"`
+	// GCInterval is the interval between two garbage collections of the workloads a
+	// manager created on its workers. Defaults to 1 minute, 0 disables the collection.
+	// +optional
+	GCInterval *metav1.Duration `json:"gcInterval,omitempty"`
+	// Origin is the label value a manager uses to recognise the workloads it created
+	// on its workers. Managers that share workers need different origins.
+	// +optional
+	Origin *string `json:"origin,omitempty"`
+	// WorkerLostTimeout is how long a manager keeps the admission check of a workload
+	// Ready after losing the connection to the worker running it. Defaults to 15 minutes.
+	// +optional
+	WorkerLostTimeout *metav1.Duration `json:"workerLostTimeout,omitempty"`
+	// Workers are the worker clusters of a manager.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Workers []MultiKueueWorker `json:"workers,omitempty"`
+}
+
+// MultiKueueWorker is a worker cluster of a MultiKueue manager.
+type MultiKueueWorker struct {
+	// Name of the MultiKueueCluster of the worker.
+	// +required
+	Name string `json:"name"`
+	// KubeconfigSecret is the name of a Secret in the operator namespace that holds
+	// the kubeconfig of the worker in its kubeconfig key.
+	// +required
+	KubeconfigSecret string `json:"kubeconfigSecret"`
+}
+
+// MultiKueueWorkerState is the state of a MultiKueue worker.
+type MultiKueueWorkerState string
+
+const (
+	// MultiKueueWorkerConnected means the manager is connected to the worker.
+	MultiKueueWorkerConnected MultiKueueWorkerState = "Connected"
+	// MultiKueueWorkerDisconnected means the manager cannot reach the worker.
+	MultiKueueWorkerDisconnected MultiKueueWorkerState = "Disconnected"
+	// MultiKueueWorkerPending means Kueue has not reported on the worker yet.
+	MultiKueueWorkerPending MultiKueueWorkerState = "Pending"
+	// MultiKueueWorkerInvalidKubeconfig means the kubeconfig Secret is missing or unusable.
+	MultiKueueWorkerInvalidKubeconfig MultiKueueWorkerState = "InvalidKubeconfig"
+)
+
+// MultiKueueWorkerStatus reports the connectivity of a MultiKueue worker.
+type MultiKueueWorkerStatus struct {
+	// name of the worker.
+	// +required
+	Name string `json:"name"`
+	// state of the worker, one of Connected, Disconnected, Pending or InvalidKubeconfig.
+	// +kubebuilder:validation:Enum=Connected;Disconnected;Pending;InvalidKubeconfig
+	// +required
+	State MultiKueueWorkerState `json:"state"`
+	// message explains the state.
+	// +optional
+	Message string `json:"message,omitempty"`
+	// lastTransitionTime is the last time the state changed.
+	// +optional
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+}
+
+// ProvisioningRequest configures the ProvisioningRequest admission check of Kueue.
+type ProvisioningRequest struct {
+	// Enabled turns on the ProvisioningACC feature gate of Kueue and grants Kueue access
+	// to autoscaling.x-k8s.io ProvisioningRequests. When false, both are turned off.
+	// Readiness, which requires the ProvisioningRequest CRD of the cluster autoscaler,
+	// is reported in the ProvisioningRequestReady condition.
+	// +required
+	Enabled bool `json:"enabled"`
+}
+
+// TopologyAwareScheduling configures topology-aware scheduling.
+type TopologyAwareScheduling struct {
+	// Topologies are the Topology objects the operator maintains. Topologies removed
+	// from the list are deleted.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Topologies []TopologyDefinition `json:"topologies,omitempty"`
+}
+
+// TopologyDefinition describes a Topology and the ResourceFlavors that use it.
+type TopologyDefinition struct {
+	// Name of the Topology.
+	// +required
+	Name string `json:"name"`
+	// Levels are the node labels of the levels of the topology, from the broadest,
+	// such as the region, to the narrowest, such as the rack or the host.
+	// The levels of a Topology cannot change, the Topology is recreated instead.
+	// +kubebuilder:default={"topology.kubernetes.io/region","topology.kubernetes.io/zone","kubernetes.io/hostname"}
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=8
+	// +kubebuilder:validation:XValidation:rule="size(self.filter(l, l == 'kubernetes.io/hostname')) == 0 || self[size(self) - 1] == 'kubernetes.io/hostname'",message="the kubernetes.io/hostname label can only be used at the lowest level"
+	// +listType=set
+	// +optional
+	Levels []string `json:"levels,omitempty"`
+	// ResourceFlavors are the names of the ResourceFlavors that use the topology.
+	// The spec of a ResourceFlavor cannot change once it uses a topology, so the
+	// flavors must have their nodeLabels set and are not unlinked afterwards.
+	// +listType=set
+	// +optional
+	ResourceFlavors []string `json:"resourceFlavors,omitempty"`
+}
+
+// TopologyStatus reports a Topology created for topologyAwareScheduling.
+type TopologyStatus struct {
+	// name of the Topology.
+	// +required
+	Name string `json:"name"`
+	// nodes is the number of nodes that have the labels of every level.
+	// +required
+	Nodes int32 `json:"nodes"`
+	// resourceFlavors are the ResourceFlavors that use the Topology.
+	// +optional
+	ResourceFlavors []string `json:"resourceFlavors,omitempty"`
+	// message reports the ResourceFlavors that could not be linked.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// WorkloadPriorities describes the WorkloadPriorityClasses the operator maintains.
+type WorkloadPriorities struct {
+	// Classes are the priority tiers of the catalogue.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Classes []WorkloadPriorityTier `json:"classes,omitempty"`
+	// MirrorPriorityClasses adds a WorkloadPriorityClass with the same name, value and
+	// description for every PriorityClass, except the system- ones. Classes take
+	// precedence over mirrored PriorityClasses of the same name.
+	// +optional
+	MirrorPriorityClasses bool `json:"mirrorPriorityClasses,omitempty"`
+}
+
+// WorkloadPriorityTier is a WorkloadPriorityClass of the catalogue.
+type WorkloadPriorityTier struct {
+	// Name of the WorkloadPriorityClass.
+	// +required
+	Name string `json:"name"`
+	// Value is the priority of the workloads of the class.
+	// +required
+	Value int32 `json:"value"`
+	// Description tells when to use the class.
+	// +optional
+	Description string `json:"description,omitempty"`
+}
+
+// WorkloadPriorityClassSource tells where a WorkloadPriorityClass of the catalogue comes from.
+type WorkloadPriorityClassSource string
+
+const (
+	// WorkloadPriorityClassSourceCatalogue is a class listed in spec.workloadPriorities.classes.
+	WorkloadPriorityClassSourceCatalogue WorkloadPriorityClassSource = "Catalogue"
+	// WorkloadPriorityClassSourcePriorityClass is a mirrored PriorityClass.
+	WorkloadPriorityClassSourcePriorityClass WorkloadPriorityClassSource = "PriorityClass"
+)
+
+// WorkloadPriorityClassStatus reports a WorkloadPriorityClass of the catalogue.
+type WorkloadPriorityClassStatus struct {
+	// name of the WorkloadPriorityClass.
+	// +required
+	Name string `json:"name"`
+	// value of the WorkloadPriorityClass.
+	// +required
+	Value int32 `json:"value"`
+	// source of the class, Catalogue or PriorityClass.
+	// +required
+	Source WorkloadPriorityClassSource `json:"source"`
+	// message reports why the class could not be reconciled.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// AdmissionCheckPlugin is an AdmissionCheck and the plugin that decides it.
+// +kubebuilder:validation:XValidation:rule="has(self.plugin) != has(self.http)",message="exactly one of plugin and http must be set"
+type AdmissionCheckPlugin struct {
+	// Name of the AdmissionCheck.
+	// +required
+	Name string `json:"name"`
+	// Plugin is the name of a plugin compiled into the operator. The Approval plugin
+	// waits for the workload to be annotated with kueue.openshift.io/approved, or the
+	// annotation of its annotation parameter, set to true or false.
+	// +optional
+	Plugin string `json:"plugin,omitempty"`
+	// HTTP calls out to a service that decides the check.
+	// +optional
+	HTTP *AdmissionCheckHTTPCallout `json:"http,omitempty"`
+	// Parameters are passed to the plugin.
+	// +optional
+	Parameters map[string]string `json:"parameters,omitempty"`
+}
+
+// AdmissionCheckHTTPCallout describes a service that decides an admission check. The
+// operator POSTs the workload and the parameters as JSON and expects a 200 response
+// with {"state": "Ready|Pending|Retry|Rejected", "message": "..."}.
+type AdmissionCheckHTTPCallout struct {
+	// URL of the service, usually of a Service in the cluster.
+	// +kubebuilder:validation:Pattern=`^https?://`
+	// +required
+	URL string `json:"url"`
+	// Timeout of a call. Defaults to 10 seconds.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// AdmissionCheckPluginStatus reports an AdmissionCheck decided by a plugin.
+type AdmissionCheckPluginStatus struct {
+	// name of the AdmissionCheck.
+	// +required
+	Name string `json:"name"`
+	// active tells whether the plugin of the check runs. Kueue does not admit the
+	// workloads of ClusterQueues that use an inactive check.
+	// +required
+	Active bool `json:"active"`
+	// message reports why the plugin does not run.
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -62,3 +692,188 @@ type KueueList struct {
 	metav1.ListMeta `json:"metadata,omitempty"`
 	Items           []Kueue `json:"items"`
 }
+
+// ConfigRevision is a rendered Kueue configuration kept in a ConfigMap named
+// kueue-manager-config-<revision>.
+type ConfigRevision struct {
+	// revision numbers the configurations in the order they were applied.
+	// +required
+	Revision int64 `json:"revision"`
+	// hash is the content hash of the configuration.
+	// +required
+	Hash string `json:"hash"`
+	// generation of the Kueue the configuration was rendered from.
+	// +optional
+	Generation int64 `json:"generation,omitempty"`
+	// createdAt is when the revision was recorded.
+	// +optional
+	CreatedAt metav1.Time `json:"createdAt,omitempty"`
+	// current is true for the revision the Kueue manager is configured with.
+	// +optional
+	Current bool `json:"current,omitempty"`
+}
+
+// AutomaticRollback configures the automatic rollback of unhealthy changes.
+type AutomaticRollback struct {
+	// Window is how long the kueue Deployment has to become available after a change.
+	// +kubebuilder:default="5m"
+	// +optional
+	Window metav1.Duration `json:"window,omitempty"`
+}
+
+// RolloutRevision is an image and configuration revision of the Kueue manager.
+type RolloutRevision struct {
+	// image of the Kueue manager.
+	// +required
+	Image string `json:"image"`
+	// configRevision is the revision in status.configRevisions.
+	// +required
+	ConfigRevision int64 `json:"configRevision"`
+}
+
+// PinnedConfig is a configuration revision that a rollback set in
+// spec.config.rollbackTo. The operator unsets spec.config.rollbackTo once spec.config
+// renders another configuration than the one that was rolled back, and leaves a
+// spec.config.rollbackTo that was changed or unset since alone.
+type PinnedConfig struct {
+	// revision is the configuration revision spec.config.rollbackTo was set to.
+	// +required
+	Revision int64 `json:"revision"`
+	// rolledBackHash is the content hash of the configuration rendered from
+	// spec.config when it was rolled back.
+	// +required
+	RolledBackHash string `json:"rolledBackHash"`
+}
+
+// RolloutState is the state of a rollout.
+// +kubebuilder:validation:Enum=Progressing;Healthy;Failed;RolledBack
+type RolloutState string
+
+const (
+	// RolloutStateProgressing is a rollout within its window.
+	RolloutStateProgressing RolloutState = "Progressing"
+	// RolloutStateHealthy is a rollout whose Deployment became available.
+	RolloutStateHealthy RolloutState = "Healthy"
+	// RolloutStateFailed is a rollout that did not become available and that could
+	// not be rolled back, because nothing was healthy before.
+	RolloutStateFailed RolloutState = "Failed"
+	// RolloutStateRolledBack is a rollout that did not become available and was
+	// rolled back to lastKnownGood.
+	RolloutStateRolledBack RolloutState = "RolledBack"
+)
+
+// RolloutStatus reports the last rollout of the Kueue manager.
+type RolloutStatus struct {
+	// current is the image and configuration revision being rolled out.
+	// +required
+	Current RolloutRevision `json:"current"`
+	// state of the rollout of current.
+	// +required
+	State RolloutState `json:"state"`
+	// startedAt is when the rollout of current was observed.
+	// +required
+	StartedAt metav1.Time `json:"startedAt"`
+	// lastKnownGood is the last image and configuration revision the Deployment was
+	// available with.
+	// +optional
+	LastKnownGood *RolloutRevision `json:"lastKnownGood,omitempty"`
+	// rolledBackFrom is the image and configuration revision that was rolled back.
+	// It is cleared by the next change.
+	// +optional
+	RolledBackFrom *RolloutRevision `json:"rolledBackFrom,omitempty"`
+	// pinnedConfig is set while spec.config.rollbackTo holds the configuration
+	// revision a rollback restored.
+	// +optional
+	PinnedConfig *PinnedConfig `json:"pinnedConfig,omitempty"`
+	// message explains the state.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// Canary is a second Kueue manager for some namespaces. It runs the configuration of
+// spec.config and waits on the leader election lease of the stable manager, so it only
+// serves webhooks while the stable manager leads.
+type Canary struct {
+	// image of the canary Kueue manager.
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	Image string `json:"image"`
+	// namespaceSelector selects the namespaces whose webhook calls the canary serves.
+	// The stable manager serves the webhook calls of the other namespaces. It must hold
+	// a single label or expression, so that the stable webhooks can exclude the
+	// namespaces. Webhooks of cluster-scoped objects are always served by the stable
+	// manager.
+	// +kubebuilder:validation:XValidation:rule="(has(self.matchLabels) ? size(self.matchLabels) : 0) + (has(self.matchExpressions) ? size(self.matchExpressions) : 0) == 1",message="namespaceSelector must hold a single label or expression"
+	// +required
+	NamespaceSelector metav1.LabelSelector `json:"namespaceSelector"`
+	// action ends the canary. The operator records the outcome in status.canary and
+	// leaves the spec to the user. After Promote the canary keeps serving its namespaces
+	// until spec.image is set to the canary image and spec.canary is removed. Abort
+	// routes every namespace back to the stable manager and deletes the canary right
+	// away, spec.canary is then removed by the user.
+	// +optional
+	Action CanaryAction `json:"action,omitempty"`
+}
+
+// CanaryAction ends a canary.
+// +kubebuilder:validation:Enum=Promote;Abort
+type CanaryAction string
+
+const (
+	// CanaryActionPromote marks the canary image as the next image of the Kueue manager.
+	CanaryActionPromote CanaryAction = "Promote"
+	// CanaryActionAbort removes the canary.
+	CanaryActionAbort CanaryAction = "Abort"
+)
+
+// CanaryState is the state of a canary.
+// +kubebuilder:validation:Enum=Running;Promoted;Aborted
+type CanaryState string
+
+const (
+	// CanaryStateRunning is a canary in spec.canary.
+	CanaryStateRunning CanaryState = "Running"
+	// CanaryStatePromoted is a canary whose image is to become spec.image.
+	CanaryStatePromoted CanaryState = "Promoted"
+	// CanaryStateAborted is a canary that was aborted, or removed without an action.
+	CanaryStateAborted CanaryState = "Aborted"
+)
+
+// CanaryStatus reports the current or last canary. It compares the managers by the
+// state of their Deployments only.
+type CanaryStatus struct {
+	// image of the canary.
+	// +required
+	Image string `json:"image"`
+	// state of the canary.
+	// +required
+	State CanaryState `json:"state"`
+	// namespaces is the number of namespaces whose webhook calls the canary serves.
+	// +optional
+	Namespaces int32 `json:"namespaces,omitempty"`
+	// stable reports the stable Kueue manager.
+	// +optional
+	Stable ManagerStatus `json:"stable,omitempty"`
+	// canary reports the canary Kueue manager.
+	// +optional
+	Canary ManagerStatus `json:"canary,omitempty"`
+	// lastTransitionTime is when the state last changed.
+	// +optional
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+}
+
+// ManagerStatus reports the pods of a Kueue manager Deployment.
+type ManagerStatus struct {
+	// image the Deployment runs.
+	// +optional
+	Image string `json:"image,omitempty"`
+	// replicas is the number of pods of the Deployment.
+	// +optional
+	Replicas int32 `json:"replicas,omitempty"`
+	// availableReplicas is the number of available pods of the Deployment.
+	// +optional
+	AvailableReplicas int32 `json:"availableReplicas,omitempty"`
+	// restarts is the sum of the container restarts of the pods of the Deployment.
+	// +optional
+	Restarts int32 `json:"restarts,omitempty"`
+}
@@ -21,10 +21,234 @@ limitations under the License.
 package v1alpha1
 
 import (
+	configv1 "github.com/openshift/api/config/v1"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	v1beta1 "sigs.k8s.io/kueue/apis/config/v1beta1"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AdmissionCheckHTTPCallout) DeepCopyInto(out *AdmissionCheckHTTPCallout) {
+	*out = *in
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdmissionCheckHTTPCallout.
+func (in *AdmissionCheckHTTPCallout) DeepCopy() *AdmissionCheckHTTPCallout {
+	if in == nil {
+		return nil
+	}
+	out := new(AdmissionCheckHTTPCallout)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AdmissionCheckPlugin) DeepCopyInto(out *AdmissionCheckPlugin) {
+	*out = *in
+	if in.HTTP != nil {
+		in, out := &in.HTTP, &out.HTTP
+		*out = new(AdmissionCheckHTTPCallout)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdmissionCheckPlugin.
+func (in *AdmissionCheckPlugin) DeepCopy() *AdmissionCheckPlugin {
+	if in == nil {
+		return nil
+	}
+	out := new(AdmissionCheckPlugin)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AdmissionCheckPluginStatus) DeepCopyInto(out *AdmissionCheckPluginStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdmissionCheckPluginStatus.
+func (in *AdmissionCheckPluginStatus) DeepCopy() *AdmissionCheckPluginStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AdmissionCheckPluginStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutomaticRollback) DeepCopyInto(out *AutomaticRollback) {
+	*out = *in
+	out.Window = in.Window
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutomaticRollback.
+func (in *AutomaticRollback) DeepCopy() *AutomaticRollback {
+	if in == nil {
+		return nil
+	}
+	out := new(AutomaticRollback)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Canary) DeepCopyInto(out *Canary) {
+	*out = *in
+	in.NamespaceSelector.DeepCopyInto(&out.NamespaceSelector)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Canary.
+func (in *Canary) DeepCopy() *Canary {
+	if in == nil {
+		return nil
+	}
+	out := new(Canary)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryStatus) DeepCopyInto(out *CanaryStatus) {
+	*out = *in
+	out.Stable = in.Stable
+	out.Canary = in.Canary
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryStatus.
+func (in *CanaryStatus) DeepCopy() *CanaryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigRevision) DeepCopyInto(out *ConfigRevision) {
+	*out = *in
+	in.CreatedAt.DeepCopyInto(&out.CreatedAt)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigRevision.
+func (in *ConfigRevision) DeepCopy() *ConfigRevision {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigRevision)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiscoveredFlavor) DeepCopyInto(out *DiscoveredFlavor) {
+	*out = *in
+	if in.NodeLabels != nil {
+		in, out := &in.NodeLabels, &out.NodeLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.NodeTaints != nil {
+		in, out := &in.NodeTaints, &out.NodeTaints
+		*out = make([]corev1.Taint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiscoveredFlavor.
+func (in *DiscoveredFlavor) DeepCopy() *DiscoveredFlavor {
+	if in == nil {
+		return nil
+	}
+	out := new(DiscoveredFlavor)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FlavorCapacity) DeepCopyInto(out *FlavorCapacity) {
+	*out = *in
+	if in.Allocatable != nil {
+		in, out := &in.Allocatable, &out.Allocatable
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.NominalQuota != nil {
+		in, out := &in.NominalQuota, &out.NominalQuota
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlavorCapacity.
+func (in *FlavorCapacity) DeepCopy() *FlavorCapacity {
+	if in == nil {
+		return nil
+	}
+	out := new(FlavorCapacity)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FlavorDiscovery) DeepCopyInto(out *FlavorDiscovery) {
+	*out = *in
+	if in.NodeLabels != nil {
+		in, out := &in.NodeLabels, &out.NodeLabels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlavorDiscovery.
+func (in *FlavorDiscovery) DeepCopy() *FlavorDiscovery {
+	if in == nil {
+		return nil
+	}
+	out := new(FlavorDiscovery)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Kueue) DeepCopyInto(out *Kueue) {
 	*out = *in
@@ -74,6 +298,16 @@ func (in *KueueConfiguration) DeepCopyInto(out *KueueConfiguration) {
 		*out = new(v1beta1.Resources)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.RevisionHistoryLimit != nil {
+		in, out := &in.RevisionHistoryLimit, &out.RevisionHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.RollbackTo != nil {
+		in, out := &in.RollbackTo, &out.RollbackTo
+		*out = new(int64)
+		**out = **in
+	}
 	return
 }
 
@@ -87,6 +321,32 @@ func (in *KueueConfiguration) DeepCopy() *KueueConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KueueDefaults) DeepCopyInto(out *KueueDefaults) {
+	*out = *in
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CoveredResources != nil {
+		in, out := &in.CoveredResources, &out.CoveredResources
+		*out = make([]corev1.ResourceName, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KueueDefaults.
+func (in *KueueDefaults) DeepCopy() *KueueDefaults {
+	if in == nil {
+		return nil
+	}
+	out := new(KueueDefaults)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KueueList) DeepCopyInto(out *KueueList) {
 	*out = *in
@@ -125,6 +385,70 @@ func (in *KueueOperandSpec) DeepCopyInto(out *KueueOperandSpec) {
 	*out = *in
 	in.OperatorSpec.DeepCopyInto(&out.OperatorSpec)
 	in.Config.DeepCopyInto(&out.Config)
+	if in.Defaults != nil {
+		in, out := &in.Defaults, &out.Defaults
+		*out = new(KueueDefaults)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LocalQueueProvisioning != nil {
+		in, out := &in.LocalQueueProvisioning, &out.LocalQueueProvisioning
+		*out = new(LocalQueueProvisioning)
+		**out = **in
+	}
+	if in.FlavorDiscovery != nil {
+		in, out := &in.FlavorDiscovery, &out.FlavorDiscovery
+		*out = new(FlavorDiscovery)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.QuotaManagement != nil {
+		in, out := &in.QuotaManagement, &out.QuotaManagement
+		*out = new(QuotaManagement)
+		**out = **in
+	}
+	if in.QueueAccess != nil {
+		in, out := &in.QueueAccess, &out.QueueAccess
+		*out = make([]QueueAccess, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.MultiKueue != nil {
+		in, out := &in.MultiKueue, &out.MultiKueue
+		*out = new(MultiKueue)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ProvisioningRequest != nil {
+		in, out := &in.ProvisioningRequest, &out.ProvisioningRequest
+		*out = new(ProvisioningRequest)
+		**out = **in
+	}
+	if in.TopologyAwareScheduling != nil {
+		in, out := &in.TopologyAwareScheduling, &out.TopologyAwareScheduling
+		*out = new(TopologyAwareScheduling)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.WorkloadPriorities != nil {
+		in, out := &in.WorkloadPriorities, &out.WorkloadPriorities
+		*out = new(WorkloadPriorities)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AdmissionChecks != nil {
+		in, out := &in.AdmissionChecks, &out.AdmissionChecks
+		*out = make([]AdmissionCheckPlugin, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AutomaticRollback != nil {
+		in, out := &in.AutomaticRollback, &out.AutomaticRollback
+		*out = new(AutomaticRollback)
+		**out = **in
+	}
+	if in.Canary != nil {
+		in, out := &in.Canary, &out.Canary
+		*out = new(Canary)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -142,6 +466,97 @@ func (in *KueueOperandSpec) DeepCopy() *KueueOperandSpec {
 func (in *KueueStatus) DeepCopyInto(out *KueueStatus) {
 	*out = *in
 	in.OperatorStatus.DeepCopyInto(&out.OperatorStatus)
+	if in.RelatedObjects != nil {
+		in, out := &in.RelatedObjects, &out.RelatedObjects
+		*out = make([]configv1.ObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.Versions != nil {
+		in, out := &in.Versions, &out.Versions
+		*out = make([]configv1.OperandVersion, len(*in))
+		copy(*out, *in)
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]ResourceStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LocalQueues != nil {
+		in, out := &in.LocalQueues, &out.LocalQueues
+		*out = make([]LocalQueueMapping, len(*in))
+		copy(*out, *in)
+	}
+	if in.DiscoveredFlavors != nil {
+		in, out := &in.DiscoveredFlavors, &out.DiscoveredFlavors
+		*out = make([]DiscoveredFlavor, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.FlavorCapacities != nil {
+		in, out := &in.FlavorCapacities, &out.FlavorCapacities
+		*out = make([]FlavorCapacity, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.QuotaRecommendations != nil {
+		in, out := &in.QuotaRecommendations, &out.QuotaRecommendations
+		*out = make([]QuotaRecommendation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.QueueAccess != nil {
+		in, out := &in.QueueAccess, &out.QueueAccess
+		*out = make([]QueueAccessStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.MultiKueueWorkers != nil {
+		in, out := &in.MultiKueueWorkers, &out.MultiKueueWorkers
+		*out = make([]MultiKueueWorkerStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Topologies != nil {
+		in, out := &in.Topologies, &out.Topologies
+		*out = make([]TopologyStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.WorkloadPriorityClasses != nil {
+		in, out := &in.WorkloadPriorityClasses, &out.WorkloadPriorityClasses
+		*out = make([]WorkloadPriorityClassStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.ConfigRevisions != nil {
+		in, out := &in.ConfigRevisions, &out.ConfigRevisions
+		*out = make([]ConfigRevision, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(RolloutStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Canary != nil {
+		in, out := &in.Canary, &out.Canary
+		*out = new(CanaryStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AdmissionChecks != nil {
+		in, out := &in.AdmissionChecks, &out.AdmissionChecks
+		*out = make([]AdmissionCheckPluginStatus, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -154,3 +569,427 @@ func (in *KueueStatus) DeepCopy() *KueueStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LocalQueueMapping) DeepCopyInto(out *LocalQueueMapping) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalQueueMapping.
+func (in *LocalQueueMapping) DeepCopy() *LocalQueueMapping {
+	if in == nil {
+		return nil
+	}
+	out := new(LocalQueueMapping)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LocalQueueProvisioning) DeepCopyInto(out *LocalQueueProvisioning) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalQueueProvisioning.
+func (in *LocalQueueProvisioning) DeepCopy() *LocalQueueProvisioning {
+	if in == nil {
+		return nil
+	}
+	out := new(LocalQueueProvisioning)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagerStatus) DeepCopyInto(out *ManagerStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagerStatus.
+func (in *ManagerStatus) DeepCopy() *ManagerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MultiKueue) DeepCopyInto(out *MultiKueue) {
+	*out = *in
+	if in.GCInterval != nil {
+		in, out := &in.GCInterval, &out.GCInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Origin != nil {
+		in, out := &in.Origin, &out.Origin
+		*out = new(string)
+		**out = **in
+	}
+	if in.WorkerLostTimeout != nil {
+		in, out := &in.WorkerLostTimeout, &out.WorkerLostTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Workers != nil {
+		in, out := &in.Workers, &out.Workers
+		*out = make([]MultiKueueWorker, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MultiKueue.
+func (in *MultiKueue) DeepCopy() *MultiKueue {
+	if in == nil {
+		return nil
+	}
+	out := new(MultiKueue)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MultiKueueWorker) DeepCopyInto(out *MultiKueueWorker) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MultiKueueWorker.
+func (in *MultiKueueWorker) DeepCopy() *MultiKueueWorker {
+	if in == nil {
+		return nil
+	}
+	out := new(MultiKueueWorker)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MultiKueueWorkerStatus) DeepCopyInto(out *MultiKueueWorkerStatus) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MultiKueueWorkerStatus.
+func (in *MultiKueueWorkerStatus) DeepCopy() *MultiKueueWorkerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MultiKueueWorkerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PinnedConfig) DeepCopyInto(out *PinnedConfig) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PinnedConfig.
+func (in *PinnedConfig) DeepCopy() *PinnedConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(PinnedConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProvisioningRequest) DeepCopyInto(out *ProvisioningRequest) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProvisioningRequest.
+func (in *ProvisioningRequest) DeepCopy() *ProvisioningRequest {
+	if in == nil {
+		return nil
+	}
+	out := new(ProvisioningRequest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QueueAccess) DeepCopyInto(out *QueueAccess) {
+	*out = *in
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueueAccess.
+func (in *QueueAccess) DeepCopy() *QueueAccess {
+	if in == nil {
+		return nil
+	}
+	out := new(QueueAccess)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QueueAccessStatus) DeepCopyInto(out *QueueAccessStatus) {
+	*out = *in
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueueAccessStatus.
+func (in *QueueAccessStatus) DeepCopy() *QueueAccessStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(QueueAccessStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QuotaManagement) DeepCopyInto(out *QuotaManagement) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QuotaManagement.
+func (in *QuotaManagement) DeepCopy() *QuotaManagement {
+	if in == nil {
+		return nil
+	}
+	out := new(QuotaManagement)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QuotaRecommendation) DeepCopyInto(out *QuotaRecommendation) {
+	*out = *in
+	if in.NominalQuota != nil {
+		in, out := &in.NominalQuota, &out.NominalQuota
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QuotaRecommendation.
+func (in *QuotaRecommendation) DeepCopy() *QuotaRecommendation {
+	if in == nil {
+		return nil
+	}
+	out := new(QuotaRecommendation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceStatus) DeepCopyInto(out *ResourceStatus) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceStatus.
+func (in *ResourceStatus) DeepCopy() *ResourceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutRevision) DeepCopyInto(out *RolloutRevision) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutRevision.
+func (in *RolloutRevision) DeepCopy() *RolloutRevision {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutRevision)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutStatus) DeepCopyInto(out *RolloutStatus) {
+	*out = *in
+	out.Current = in.Current
+	in.StartedAt.DeepCopyInto(&out.StartedAt)
+	if in.LastKnownGood != nil {
+		in, out := &in.LastKnownGood, &out.LastKnownGood
+		*out = new(RolloutRevision)
+		**out = **in
+	}
+	if in.RolledBackFrom != nil {
+		in, out := &in.RolledBackFrom, &out.RolledBackFrom
+		*out = new(RolloutRevision)
+		**out = **in
+	}
+	if in.PinnedConfig != nil {
+		in, out := &in.PinnedConfig, &out.PinnedConfig
+		*out = new(PinnedConfig)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutStatus.
+func (in *RolloutStatus) DeepCopy() *RolloutStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TopologyAwareScheduling) DeepCopyInto(out *TopologyAwareScheduling) {
+	*out = *in
+	if in.Topologies != nil {
+		in, out := &in.Topologies, &out.Topologies
+		*out = make([]TopologyDefinition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TopologyAwareScheduling.
+func (in *TopologyAwareScheduling) DeepCopy() *TopologyAwareScheduling {
+	if in == nil {
+		return nil
+	}
+	out := new(TopologyAwareScheduling)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TopologyDefinition) DeepCopyInto(out *TopologyDefinition) {
+	*out = *in
+	if in.Levels != nil {
+		in, out := &in.Levels, &out.Levels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ResourceFlavors != nil {
+		in, out := &in.ResourceFlavors, &out.ResourceFlavors
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TopologyDefinition.
+func (in *TopologyDefinition) DeepCopy() *TopologyDefinition {
+	if in == nil {
+		return nil
+	}
+	out := new(TopologyDefinition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TopologyStatus) DeepCopyInto(out *TopologyStatus) {
+	*out = *in
+	if in.ResourceFlavors != nil {
+		in, out := &in.ResourceFlavors, &out.ResourceFlavors
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TopologyStatus.
+func (in *TopologyStatus) DeepCopy() *TopologyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TopologyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadPriorities) DeepCopyInto(out *WorkloadPriorities) {
+	*out = *in
+	if in.Classes != nil {
+		in, out := &in.Classes, &out.Classes
+		*out = make([]WorkloadPriorityTier, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadPriorities.
+func (in *WorkloadPriorities) DeepCopy() *WorkloadPriorities {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkloadPriorities)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadPriorityClassStatus) DeepCopyInto(out *WorkloadPriorityClassStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadPriorityClassStatus.
+func (in *WorkloadPriorityClassStatus) DeepCopy() *WorkloadPriorityClassStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkloadPriorityClassStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadPriorityTier) DeepCopyInto(out *WorkloadPriorityTier) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadPriorityTier.
+func (in *WorkloadPriorityTier) DeepCopy() *WorkloadPriorityTier {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkloadPriorityTier)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -17,8 +17,13 @@ limitations under the License.
 package configmap
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	componentconfigv1alpha1 "k8s.io/component-base/config/v1alpha1"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/yaml"
 
@@ -27,22 +32,76 @@ import (
 	kueue "github.com/openshift/kueue-operator/pkg/apis/kueueoperator/v1alpha1"
 )
 
-func BuildConfigMap(namespace string, kueueCfg kueue.KueueConfiguration) (*corev1.ConfigMap, error) {
+// ConfigKey is the key of the Kueue configuration in the ConfigMap.
+const ConfigKey = "controller_manager_config.yaml"
+
+// BuildConfigMap renders the Kueue configuration. The MultiKueue settings are only set
+// when the cluster is a MultiKueue manager.
+func BuildConfigMap(namespace string, kueueCfg kueue.KueueConfiguration, multiKueue *kueue.MultiKueue) (*corev1.ConfigMap, error) {
+	config := kueueConfiguration(namespace, kueueCfg, multiKueue)
+	return buildConfigMap("kueue-manager-config", namespace, config)
+}
+
+// BuildCanaryConfigMap renders the Kueue configuration of a canary manager. It sets the
+// leader election lease of the stable manager explicitly, as the default of the canary
+// image may differ, so that the canary only serves webhooks while the stable manager
+// leads and runs the scheduler and the controllers.
+func BuildCanaryConfigMap(namespace string, kueueCfg kueue.KueueConfiguration, multiKueue *kueue.MultiKueue) (*corev1.ConfigMap, error) {
+	config := kueueConfiguration(namespace, kueueCfg, multiKueue)
+	config.LeaderElection = &componentconfigv1alpha1.LeaderElectionConfiguration{
+		LeaderElect:  ptr.To(true),
+		ResourceName: configapi.DefaultLeaderElectionID,
+	}
+	return buildConfigMap("kueue-manager-config-canary", namespace, config)
+}
+
+func kueueConfiguration(namespace string, kueueCfg kueue.KueueConfiguration, multiKueue *kueue.MultiKueue) *configapi.Configuration {
 	config := defaultKueueConfigurationTemplate(kueueCfg)
+	if multiKueue != nil && multiKueue.Role == kueue.MultiKueueRoleManager {
+		config.MultiKueue = &configapi.MultiKueue{
+			GCInterval:        multiKueue.GCInterval,
+			Origin:            multiKueue.Origin,
+			WorkerLostTimeout: multiKueue.WorkerLostTimeout,
+		}
+	}
+	config.Namespace = ptr.To(namespace)
+	return config
+}
+
+func buildConfigMap(name, namespace string, config *configapi.Configuration) (*corev1.ConfigMap, error) {
 	cfg, err := yaml.Marshal(config)
 	if err != nil {
 		return nil, err
 	}
 	cfgMap := &corev1.ConfigMap{
 		ObjectMeta: v1.ObjectMeta{
-			Name:      "kueue-manager-config",
+			Name:      name,
 			Namespace: namespace,
 		},
-		Data: map[string]string{"controller_manager_config.yaml": string(cfg)},
+		Data: map[string]string{ConfigKey: string(cfg)},
 	}
 	return cfgMap, nil
 }
 
+// Hash returns a short content hash of the data of a ConfigMap. It only depends on the
+// keys and values, so metadata changes do not change it.
+func Hash(data map[string]string) string {
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	hash := sha256.New()
+	for _, key := range keys {
+		// The separators keep {"ab": "c"} and {"a": "bc"} apart.
+		hash.Write([]byte(key))
+		hash.Write([]byte{0})
+		hash.Write([]byte(data[key]))
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil))[:16]
+}
+
 func defaultKueueConfigurationTemplate(kueueCfg kueue.KueueConfiguration) *configapi.Configuration {
 	return &configapi.Configuration{
 		TypeMeta: v1.TypeMeta{
@@ -18,10 +18,13 @@ package configmap
 
 import (
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 
 	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 	configapi "sigs.k8s.io/kueue/apis/config/v1beta1"
 
 	kueue "github.com/openshift/kueue-operator/pkg/apis/kueueoperator/v1alpha1"
@@ -30,6 +33,7 @@ import (
 func TestBuildConfigMap(t *testing.T) {
 	testCases := map[string]struct {
 		configuration kueue.KueueConfiguration
+		multiKueue    *kueue.MultiKueue
 		wantCfgMap    *corev1.ConfigMap
 		wantErr       error
 	}{
@@ -62,6 +66,7 @@ manageJobsWithoutQueueName: false
 metrics:
   bindAddress: :8080
   enableClusterQueueResources: true
+namespace: test
 webhook:
   port: 9443
 `,
@@ -69,11 +74,57 @@ webhook:
 			},
 			wantErr: nil,
 		},
+		"multikueue manager": {
+			configuration: kueue.KueueConfiguration{
+				Integrations: configapi.Integrations{
+					Frameworks: []string{"batch.job"},
+				},
+			},
+			multiKueue: &kueue.MultiKueue{
+				Role:              kueue.MultiKueueRoleManager,
+				GCInterval:        &v1.Duration{Duration: 2 * time.Minute},
+				Origin:            ptr.To("manager-east"),
+				WorkerLostTimeout: &v1.Duration{Duration: 5 * time.Minute},
+			},
+			wantCfgMap: &corev1.ConfigMap{
+				Data: map[string]string{
+					"controller_manager_config.yaml": `apiVersion: config.kueue.x-k8s.io/v1beta1
+controller:
+  groupKindConcurrency:
+    ClusterQueue.kueue.x-k8s.io: 1
+    Job.batch: 5
+    LocalQueue.kueue.x-k8s.io: 1
+    Pod: 5
+    ResourceFlavor.kueue.x-k8s.io: 1
+    Workload.kueue.x-k8s.io: 5
+health:
+  healthProbeBindAddress: :8081
+integrations:
+  frameworks:
+  - batch.job
+internalCertManagement:
+  enable: false
+kind: Configuration
+manageJobsWithoutQueueName: false
+metrics:
+  bindAddress: :8080
+  enableClusterQueueResources: true
+multiKueue:
+  gcInterval: 2m0s
+  origin: manager-east
+  workerLostTimeout: 5m0s
+namespace: test
+webhook:
+  port: 9443
+`,
+				},
+			},
+		},
 	}
 
 	for desc, tc := range testCases {
 		t.Run(desc, func(t *testing.T) {
-			got, err := BuildConfigMap("test", tc.configuration)
+			got, err := BuildConfigMap("test", tc.configuration, tc.multiKueue)
 			if diff := cmp.Diff(got.Data["controller_manager_config.yaml"], tc.wantCfgMap.Data["controller_manager_config.yaml"]); len(diff) != 0 {
 				t.Errorf("Unexpected buckets (-want,+got):\n%s", diff)
 			}
@@ -0,0 +1,153 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configmap
+
+import (
+	"fmt"
+	"os"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/component-base/featuregate"
+	configapi "sigs.k8s.io/kueue/apis/config/v1beta1"
+	kueueconfig "sigs.k8s.io/kueue/pkg/config"
+	// Registers the integrations, integrations.frameworks is validated against them.
+	_ "sigs.k8s.io/kueue/pkg/controller/jobs"
+	"sigs.k8s.io/kueue/pkg/features"
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	validationScheme = runtime.NewScheme()
+	validationCodecs = serializer.NewCodecFactory(validationScheme, serializer.EnableStrict)
+)
+
+func init() {
+	utilruntime.Must(clientgoscheme.AddToScheme(validationScheme))
+	utilruntime.Must(configapi.AddToScheme(validationScheme))
+}
+
+// InvalidConfigError is returned by Validate for a configuration the Kueue manager
+// would refuse to start with.
+type InvalidConfigError struct {
+	Err error
+}
+
+func (e *InvalidConfigError) Error() string {
+	return fmt.Sprintf("invalid Kueue configuration: %v", e.Err)
+}
+
+func (e *InvalidConfigError) Unwrap() error {
+	return e.Err
+}
+
+// Validate loads the configuration of cfgMap the way the Kueue manager does when it
+// starts, with the decoding, defaulting and validation of the vendored Kueue release,
+// under the feature gates passed with --feature-gates. Gates the vendored release does
+// not know are skipped, as the image may be a newer release.
+func Validate(cfgMap *corev1.ConfigMap, featureGates map[string]bool) error {
+	gates, err := knownFeatureGates(featureGates)
+	if err != nil {
+		return &InvalidConfigError{Err: err}
+	}
+	data, ok := cfgMap.Data[ConfigKey]
+	if !ok {
+		return &InvalidConfigError{Err: fmt.Errorf("the ConfigMap has no %s key", ConfigKey)}
+	}
+
+	// Kueue validates managedJobsNamespaceSelector under the process-wide gates, so it
+	// is left to its default there and validated here under featureGates.
+	config := &configapi.Configuration{}
+	if err := runtime.DecodeInto(validationCodecs.UniversalDecoder(), []byte(data), config); err != nil {
+		return &InvalidConfigError{Err: err}
+	}
+	content := map[string]interface{}{}
+	if err := yaml.Unmarshal([]byte(data), &content); err != nil {
+		return &InvalidConfigError{Err: err}
+	}
+	delete(content, "managedJobsNamespaceSelector")
+	unscoped, err := yaml.Marshal(content)
+	if err != nil {
+		return err
+	}
+
+	// Kueue only loads its configuration from a file.
+	file, err := os.CreateTemp("", "kueue-config-*.yaml")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	if _, err := file.Write(unscoped); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	if _, _, err := kueueconfig.Load(validationScheme, file.Name()); err != nil {
+		return &InvalidConfigError{Err: err}
+	}
+
+	if gates.Enabled(features.ManagedJobsNamespaceSelector) {
+		if err := validateManagedJobsNamespaceSelector(config); err != nil {
+			return &InvalidConfigError{Err: err}
+		}
+	}
+	return nil
+}
+
+// knownFeatureGates returns a copy of the process-wide feature gates with the gates of
+// featureGates that it knows set.
+func knownFeatureGates(featureGates map[string]bool) (featuregate.FeatureGate, error) {
+	gates := utilfeature.DefaultMutableFeatureGate.DeepCopy()
+	known := gates.GetAll()
+	set := make(map[string]bool, len(featureGates))
+	for name, enabled := range featureGates {
+		if _, ok := known[featuregate.Feature(name)]; ok {
+			set[name] = enabled
+		}
+	}
+	if err := gates.SetFromMap(set); err != nil {
+		return nil, err
+	}
+	return gates, nil
+}
+
+// validateManagedJobsNamespaceSelector is the check of Kueue for a defaulted
+// configuration: the selector must not match kube-system or the Kueue namespace.
+func validateManagedJobsNamespaceSelector(config *configapi.Configuration) error {
+	selector, err := metav1.LabelSelectorAsSelector(config.ManagedJobsNamespaceSelector)
+	if err != nil {
+		return fmt.Errorf("managedJobsNamespaceSelector: %w", err)
+	}
+	prohibited := []string{metav1.NamespaceSystem}
+	if config.Namespace != nil && len(*config.Namespace) > 0 {
+		prohibited = append(prohibited, *config.Namespace)
+	}
+	for _, namespace := range prohibited {
+		if selector.Matches(labels.Set{corev1.LabelMetadataName: namespace}) {
+			return fmt.Errorf("managedJobsNamespaceSelector: should not match the %q namespace", namespace)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configmap
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+	configapi "sigs.k8s.io/kueue/apis/config/v1beta1"
+	"sigs.k8s.io/kueue/pkg/features"
+
+	kueue "github.com/openshift/kueue-operator/pkg/apis/kueueoperator/v1alpha1"
+)
+
+func TestValidate(t *testing.T) {
+	testCases := map[string]struct {
+		configuration kueue.KueueConfiguration
+		featureGates  map[string]bool
+		wantErr       string
+	}{
+		"valid configuration": {
+			configuration: kueue.KueueConfiguration{
+				Integrations: configapi.Integrations{Frameworks: []string{"batch/job", "kubeflow.org/pytorchjob"}},
+			},
+			featureGates: map[string]bool{"TopologyAwareScheduling": true},
+		},
+		"unknown framework": {
+			configuration: kueue.KueueConfiguration{
+				Integrations: configapi.Integrations{Frameworks: []string{"batch/job", "example.com/notebook"}},
+			},
+			wantErr: `integrations.frameworks[1]: Unsupported value: "example.com/notebook"`,
+		},
+		"negative backoff": {
+			configuration: kueue.KueueConfiguration{
+				Integrations: configapi.Integrations{Frameworks: []string{"batch/job"}},
+				WaitForPodsReady: &configapi.WaitForPodsReady{
+					Enable:            true,
+					RequeuingStrategy: &configapi.RequeuingStrategy{BackoffBaseSeconds: ptr.To[int32](-1)},
+				},
+			},
+			wantErr: "waitForPodsReady.requeuingStrategy.backoffBaseSeconds: Invalid value: -1",
+		},
+		// A newer Kueue image may know more gates than the vendored release.
+		"unknown feature gate": {
+			configuration: kueue.KueueConfiguration{
+				Integrations: configapi.Integrations{Frameworks: []string{"batch/job"}},
+			},
+			featureGates: map[string]bool{"Teleportation": true},
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			cfgMap, err := BuildConfigMap("openshift-kueue-operator", tc.configuration, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = Validate(cfgMap, tc.featureGates)
+			if len(tc.wantErr) == 0 {
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				return
+			}
+			var invalid *InvalidConfigError
+			if !errors.As(err, &invalid) || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("Expected an InvalidConfigError containing %q, got %v", tc.wantErr, err)
+			}
+		})
+	}
+
+	if err := Validate(&corev1.ConfigMap{}, nil); err == nil || !strings.Contains(err.Error(), "has no "+ConfigKey) {
+		t.Errorf("Expected an error about the missing key, got %v", err)
+	}
+}
+
+func TestValidateUsesFeatureGates(t *testing.T) {
+	// An empty selector matches kube-system, which Kueue only rejects while the
+	// ManagedJobsNamespaceSelector gate is enabled.
+	cfgMap := &corev1.ConfigMap{Data: map[string]string{ConfigKey: `apiVersion: config.kueue.x-k8s.io/v1beta1
+kind: Configuration
+namespace: openshift-kueue-operator
+managedJobsNamespaceSelector: {}
+integrations:
+  frameworks:
+  - batch/job
+`}}
+
+	err := Validate(cfgMap, map[string]bool{string(features.ManagedJobsNamespaceSelector): true})
+	if err == nil || !strings.Contains(err.Error(), `should not match the "kube-system" namespace`) {
+		t.Errorf("Expected the selector to be rejected with the gate enabled, got %v", err)
+	}
+	if err := Validate(cfgMap, map[string]bool{string(features.ManagedJobsNamespaceSelector): false}); err != nil {
+		t.Errorf("Unexpected error with the gate disabled: %v", err)
+	}
+	if !features.Enabled(features.ManagedJobsNamespaceSelector) {
+		t.Errorf("Expected the process-wide gate to be left alone")
+	}
+}
+
+func TestValidateConcurrently(t *testing.T) {
+	cfgMap := &corev1.ConfigMap{Data: map[string]string{ConfigKey: `apiVersion: config.kueue.x-k8s.io/v1beta1
+kind: Configuration
+namespace: openshift-kueue-operator
+managedJobsNamespaceSelector: {}
+integrations:
+  frameworks:
+  - batch/job
+`}}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 100)
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if err := Validate(cfgMap, map[string]bool{string(features.ManagedJobsNamespaceSelector): true}); err == nil {
+				errs <- errors.New("expected the selector to be rejected with the gate enabled")
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if err := Validate(cfgMap, map[string]bool{string(features.ManagedJobsNamespaceSelector): false}); err != nil {
+				errs <- fmt.Errorf("unexpected error with the gate disabled: %w", err)
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
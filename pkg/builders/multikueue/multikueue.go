@@ -0,0 +1,213 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package multikueue
+
+import (
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/clientcmd"
+	kueuev1beta1 "sigs.k8s.io/kueue/apis/kueue/v1beta1"
+
+	"github.com/openshift/kueue-operator/pkg/builders/queues"
+)
+
+const (
+	// KubeconfigKey is the key of the worker kubeconfig in its Secret, as expected by Kueue.
+	KubeconfigKey = "kubeconfig"
+
+	// ConfigName is the name of the MultiKueueConfig and of the AdmissionCheck of a manager.
+	ConfigName = "multikueue"
+
+	// WorkerServiceAccountName is the ServiceAccount a manager uses on a worker.
+	WorkerServiceAccountName = "multikueue-manager"
+	// WorkerTokenSecretName holds the token of the worker ServiceAccount.
+	WorkerTokenSecretName = "multikueue-manager-token"
+	// WorkerClusterRoleName grants a manager what it needs on a worker.
+	WorkerClusterRoleName = "kueue-multikueue-worker-role"
+)
+
+var (
+	MultiKueueClustersGVR = kueuev1beta1.GroupVersion.WithResource("multikueueclusters")
+	MultiKueueConfigsGVR  = kueuev1beta1.GroupVersion.WithResource("multikueueconfigs")
+	AdmissionChecksGVR    = queues.AdmissionChecksGVR
+)
+
+func BuildMultiKueueCluster(name, kubeconfigSecret string) *kueuev1beta1.MultiKueueCluster {
+	return &kueuev1beta1.MultiKueueCluster{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: kueuev1beta1.GroupVersion.String(),
+			Kind:       "MultiKueueCluster",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Spec: kueuev1beta1.MultiKueueClusterSpec{
+			KubeConfig: kueuev1beta1.KubeConfig{
+				Location:     kubeconfigSecret,
+				LocationType: kueuev1beta1.SecretLocationType,
+			},
+		},
+	}
+}
+
+func BuildMultiKueueConfig(clusters []string) *kueuev1beta1.MultiKueueConfig {
+	return &kueuev1beta1.MultiKueueConfig{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: kueuev1beta1.GroupVersion.String(),
+			Kind:       "MultiKueueConfig",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: ConfigName,
+		},
+		Spec: kueuev1beta1.MultiKueueConfigSpec{
+			Clusters: clusters,
+		},
+	}
+}
+
+// BuildAdmissionCheck returns the AdmissionCheck that ClusterQueues list to have their
+// workloads dispatched to the workers of the MultiKueueConfig.
+func BuildAdmissionCheck() *kueuev1beta1.AdmissionCheck {
+	return &kueuev1beta1.AdmissionCheck{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: kueuev1beta1.GroupVersion.String(),
+			Kind:       "AdmissionCheck",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: ConfigName,
+		},
+		Spec: kueuev1beta1.AdmissionCheckSpec{
+			ControllerName: kueuev1beta1.MultiKueueControllerName,
+			Parameters: &kueuev1beta1.AdmissionCheckParametersReference{
+				APIGroup: kueuev1beta1.GroupVersion.Group,
+				Kind:     "MultiKueueConfig",
+				Name:     ConfigName,
+			},
+		},
+	}
+}
+
+// ValidateKubeconfig checks that a worker kubeconfig can be used by the Kueue manager:
+// its current context must name a cluster with a server and a user, and it must not
+// refer to files or credential plugins, which do not exist in the Kueue pod.
+func ValidateKubeconfig(data []byte) error {
+	if len(data) == 0 {
+		return errors.New("the kubeconfig is empty")
+	}
+	config, err := clientcmd.Load(data)
+	if err != nil {
+		return fmt.Errorf("the kubeconfig cannot be parsed: %w", err)
+	}
+	if len(config.CurrentContext) == 0 {
+		return errors.New("the kubeconfig has no current-context")
+	}
+	context, ok := config.Contexts[config.CurrentContext]
+	if !ok {
+		return fmt.Errorf("the current-context %q does not exist", config.CurrentContext)
+	}
+	cluster, ok := config.Clusters[context.Cluster]
+	if !ok {
+		return fmt.Errorf("the cluster %q of the current-context does not exist", context.Cluster)
+	}
+	if len(cluster.Server) == 0 {
+		return fmt.Errorf("the cluster %q has no server", context.Cluster)
+	}
+	if len(cluster.CertificateAuthority) > 0 {
+		return fmt.Errorf("the cluster %q refers to the file %s, use certificate-authority-data instead", context.Cluster, cluster.CertificateAuthority)
+	}
+	user, ok := config.AuthInfos[context.AuthInfo]
+	if !ok {
+		return fmt.Errorf("the user %q of the current-context does not exist", context.AuthInfo)
+	}
+	switch {
+	case len(user.TokenFile) > 0:
+		return fmt.Errorf("the user %q refers to the file %s, use token instead", context.AuthInfo, user.TokenFile)
+	case len(user.ClientCertificate) > 0 || len(user.ClientKey) > 0:
+		return fmt.Errorf("the user %q refers to certificate files, use client-certificate-data and client-key-data instead", context.AuthInfo)
+	case user.Exec != nil || user.AuthProvider != nil:
+		return fmt.Errorf("the user %q uses a credential plugin, which is not available to Kueue", context.AuthInfo)
+	}
+	return nil
+}
+
+// BuildWorkerServiceAccount returns the ServiceAccount a manager authenticates as on a
+// worker.
+func BuildWorkerServiceAccount(namespace string) *corev1.ServiceAccount {
+	return &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      WorkerServiceAccountName,
+			Namespace: namespace,
+		},
+	}
+}
+
+// BuildWorkerTokenSecret returns a long-lived token Secret of the worker ServiceAccount,
+// from which the kubeconfig of the worker is built for the manager.
+func BuildWorkerTokenSecret(namespace string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        WorkerTokenSecretName,
+			Namespace:   namespace,
+			Annotations: map[string]string{corev1.ServiceAccountNameKey: WorkerServiceAccountName},
+		},
+		Type: corev1.SecretTypeServiceAccountToken,
+	}
+}
+
+// BuildWorkerClusterRole returns the permissions a manager needs on a worker to mirror
+// workloads and jobs, following the upstream MultiKueue setup guide.
+func BuildWorkerClusterRole() *rbacv1.ClusterRole {
+	crud := []string{"create", "delete", "get", "list", "watch"}
+	statusVerbs := []string{"get", "patch", "update"}
+	return &rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: WorkerClusterRoleName,
+		},
+		Rules: []rbacv1.PolicyRule{
+			{APIGroups: []string{"batch"}, Resources: []string{"jobs"}, Verbs: crud},
+			{APIGroups: []string{"batch"}, Resources: []string{"jobs/status"}, Verbs: []string{"get"}},
+			{APIGroups: []string{"jobset.x-k8s.io"}, Resources: []string{"jobsets"}, Verbs: crud},
+			{APIGroups: []string{"jobset.x-k8s.io"}, Resources: []string{"jobsets/status"}, Verbs: []string{"get"}},
+			{APIGroups: []string{"kueue.x-k8s.io"}, Resources: []string{"workloads"}, Verbs: crud},
+			{APIGroups: []string{"kueue.x-k8s.io"}, Resources: []string{"workloads/status"}, Verbs: statusVerbs},
+			{APIGroups: []string{"kubeflow.org"}, Resources: []string{"mpijobs", "paddlejobs", "pytorchjobs", "tfjobs", "xgboostjobs"}, Verbs: crud},
+			{APIGroups: []string{"kubeflow.org"}, Resources: []string{"mpijobs/status", "paddlejobs/status", "pytorchjobs/status", "tfjobs/status", "xgboostjobs/status"}, Verbs: []string{"get"}},
+		},
+	}
+}
+
+func BuildWorkerClusterRoleBinding(namespace string) *rbacv1.ClusterRoleBinding {
+	return &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: WorkerClusterRoleName + "-binding",
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "ClusterRole",
+			Name:     WorkerClusterRoleName,
+		},
+		Subjects: []rbacv1.Subject{{
+			Kind:      rbacv1.ServiceAccountKind,
+			Name:      WorkerServiceAccountName,
+			Namespace: namespace,
+		}},
+	}
+}
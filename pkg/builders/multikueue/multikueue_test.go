@@ -0,0 +1,104 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package multikueue
+
+import (
+	"strings"
+	"testing"
+)
+
+const validKubeconfig = `apiVersion: v1
+kind: Config
+current-context: worker
+contexts:
+- name: worker
+  context:
+    cluster: worker
+    user: multikueue-manager
+clusters:
+- name: worker
+  cluster:
+    server: https://api.worker.example.com:6443
+    certificate-authority-data: Y2E=
+users:
+- name: multikueue-manager
+  user:
+    token: secret-token
+`
+
+func TestValidateKubeconfig(t *testing.T) {
+	tests := []struct {
+		name       string
+		kubeconfig string
+		wantErr    string
+	}{
+		{
+			name:       "valid",
+			kubeconfig: validKubeconfig,
+		},
+		{
+			name:    "empty",
+			wantErr: "empty",
+		},
+		{
+			name:       "not a kubeconfig",
+			kubeconfig: "{",
+			wantErr:    "cannot be parsed",
+		},
+		{
+			name:       "no current context",
+			kubeconfig: strings.Replace(validKubeconfig, "current-context: worker\n", "", 1),
+			wantErr:    "no current-context",
+		},
+		{
+			name:       "unknown cluster",
+			kubeconfig: strings.Replace(validKubeconfig, "    cluster: worker\n", "    cluster: other\n", 1),
+			wantErr:    `cluster "other" of the current-context does not exist`,
+		},
+		{
+			name:       "token file",
+			kubeconfig: strings.Replace(validKubeconfig, "token: secret-token", "tokenFile: /var/run/token", 1),
+			wantErr:    "refers to the file /var/run/token",
+		},
+		{
+			name:       "CA file",
+			kubeconfig: strings.Replace(validKubeconfig, "certificate-authority-data: Y2E=", "certificate-authority: /etc/ca.crt", 1),
+			wantErr:    "use certificate-authority-data",
+		},
+		{
+			name: "exec plugin",
+			kubeconfig: strings.Replace(validKubeconfig, "token: secret-token", `exec:
+      apiVersion: client.authentication.k8s.io/v1
+      command: oc`, 1),
+			wantErr: "credential plugin",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateKubeconfig([]byte(tc.kubeconfig))
+			if len(tc.wantErr) == 0 {
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("Expected an error containing %q, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
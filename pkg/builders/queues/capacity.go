@@ -0,0 +1,117 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queues
+
+import (
+	"math/big"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// FlavorNodes returns the schedulable nodes that have every label of nodeLabels. A
+// flavor without nodeLabels matches every node.
+func FlavorNodes(nodes []*corev1.Node, nodeLabels map[string]string) []*corev1.Node {
+	selector := labels.SelectorFromSet(nodeLabels)
+	var matching []*corev1.Node
+	for _, node := range nodes {
+		if node.Spec.Unschedulable || !selector.Matches(labels.Set(node.Labels)) {
+			continue
+		}
+		matching = append(matching, node)
+	}
+	return matching
+}
+
+// TotalAllocatable sums the allocatable capacity of every resource the nodes report.
+func TotalAllocatable(nodes []*corev1.Node) corev1.ResourceList {
+	total := corev1.ResourceList{}
+	for _, node := range nodes {
+		for name, allocatable := range node.Status.Allocatable {
+			sum := total[name]
+			sum.Add(allocatable)
+			total[name] = sum
+		}
+	}
+	return total
+}
+
+// RecommendQuotas shares the capacity of each resource between the queues that have a
+// quota for it, in proportion to their current nominal quotas or evenly when all of them
+// are zero. Resources missing from capacity get no recommendation. Shares are rounded
+// down, to millicores for CPU and to whole units otherwise, so that they never add up
+// to more than capacity.
+func RecommendQuotas(capacity corev1.ResourceList, quotas map[string]corev1.ResourceList) map[string]corev1.ResourceList {
+	names := make([]string, 0, len(quotas))
+	for name := range quotas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	recommendations := make(map[string]corev1.ResourceList, len(quotas))
+	for _, name := range names {
+		recommendations[name] = corev1.ResourceList{}
+	}
+
+	resources := map[corev1.ResourceName]bool{}
+	for _, quota := range quotas {
+		for resourceName := range quota {
+			resources[resourceName] = true
+		}
+	}
+	for resourceName := range resources {
+		available, ok := capacity[resourceName]
+		if !ok {
+			continue
+		}
+		value := func(q resource.Quantity) *big.Int {
+			if resourceName == corev1.ResourceCPU {
+				return big.NewInt(q.MilliValue())
+			}
+			return big.NewInt(q.Value())
+		}
+
+		var sharing []string
+		total := new(big.Int)
+		for _, name := range names {
+			quota, ok := quotas[name][resourceName]
+			if !ok {
+				continue
+			}
+			sharing = append(sharing, name)
+			total.Add(total, value(quota))
+		}
+		for _, name := range sharing {
+			share := new(big.Int)
+			if total.Sign() > 0 {
+				quota := quotas[name][resourceName]
+				share.Mul(value(available), value(quota))
+				share.Quo(share, total)
+			} else {
+				share.Quo(value(available), big.NewInt(int64(len(sharing))))
+			}
+			if resourceName == corev1.ResourceCPU {
+				recommendations[name][resourceName] = *resource.NewMilliQuantity(share.Int64(), available.Format)
+			} else {
+				recommendations[name][resourceName] = *resource.NewQuantity(share.Int64(), available.Format)
+			}
+		}
+	}
+	return recommendations
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queues
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRecommendQuotas(t *testing.T) {
+	capacity := corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("10"),
+		corev1.ResourceMemory: resource.MustParse("30Gi"),
+		"nvidia.com/gpu":      resource.MustParse("3"),
+	}
+	got := RecommendQuotas(capacity, map[string]corev1.ResourceList{
+		"research": {
+			corev1.ResourceCPU:    resource.MustParse("12"),
+			corev1.ResourceMemory: resource.MustParse("0"),
+			"nvidia.com/gpu":      resource.MustParse("4"),
+		},
+		"batch": {
+			corev1.ResourceCPU:    resource.MustParse("4"),
+			corev1.ResourceMemory: resource.MustParse("0"),
+			"example.com/license": resource.MustParse("2"),
+		},
+	})
+
+	want := map[string]corev1.ResourceList{
+		"research": {
+			corev1.ResourceCPU:    resource.MustParse("7500m"),
+			corev1.ResourceMemory: resource.MustParse("15Gi"),
+			"nvidia.com/gpu":      resource.MustParse("3"),
+		},
+		"batch": {
+			corev1.ResourceCPU:    resource.MustParse("2500m"),
+			corev1.ResourceMemory: resource.MustParse("15Gi"),
+		},
+	}
+	for name, quota := range want {
+		for resourceName, q := range quota {
+			got, ok := got[name][resourceName]
+			if !ok || got.Cmp(q) != 0 {
+				t.Errorf("Expected %s of %s to be %s, got %s", resourceName, name, q.String(), got.String())
+			}
+		}
+		if len(got[name]) != len(quota) {
+			t.Errorf("Unexpected recommendation for %s: %v", name, got[name])
+		}
+	}
+}
+
+func TestFlavorNodes(t *testing.T) {
+	nodes := []*corev1.Node{
+		{ObjectMeta: metav1.ObjectMeta{Name: "gpu", Labels: map[string]string{"pool": "gpu"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "cpu", Labels: map[string]string{"pool": "cpu"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "cordoned", Labels: map[string]string{"pool": "gpu"}}, Spec: corev1.NodeSpec{Unschedulable: true}},
+	}
+	names := func(nodes []*corev1.Node) []string {
+		var names []string
+		for _, node := range nodes {
+			names = append(names, node.Name)
+		}
+		return names
+	}
+	if diff := cmp.Diff([]string{"gpu"}, names(FlavorNodes(nodes, map[string]string{"pool": "gpu"}))); len(diff) != 0 {
+		t.Errorf("Unexpected nodes (-want,+got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"gpu", "cpu"}, names(FlavorNodes(nodes, nil))); len(diff) != 0 {
+		t.Errorf("Unexpected nodes (-want,+got):\n%s", diff)
+	}
+}
@@ -0,0 +1,173 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queues
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	kueuev1beta1 "sigs.k8s.io/kueue/apis/kueue/v1beta1"
+)
+
+// NodePool is a group of nodes that have the same values for a set of node labels.
+type NodePool struct {
+	// Name is the name of the ResourceFlavor of the pool.
+	Name string
+	// NodeLabels are the values of the labels the pool was grouped by.
+	NodeLabels map[string]string
+	// NodeTaints are the NoSchedule and NoExecute taints every node of the pool has.
+	NodeTaints []corev1.Taint
+	Nodes      []*corev1.Node
+}
+
+// NodePools groups nodes by the values of the given label keys. Keys a node does not
+// have are left out of its group and nodes that have none of the keys are ignored.
+// Pools are sorted by name.
+func NodePools(nodes []*corev1.Node, labelKeys []string) []NodePool {
+	groups := map[string]*NodePool{}
+	for _, node := range nodes {
+		nodeLabels := map[string]string{}
+		values := make([]string, 0, len(labelKeys))
+		for _, key := range labelKeys {
+			if value, ok := node.Labels[key]; ok && len(value) > 0 {
+				nodeLabels[key] = value
+				values = append(values, key+"="+value)
+			}
+		}
+		if len(nodeLabels) == 0 {
+			continue
+		}
+		groupKey := strings.Join(values, ",")
+		group, ok := groups[groupKey]
+		if !ok {
+			group = &NodePool{NodeLabels: nodeLabels, NodeTaints: schedulingTaints(node)}
+			groups[groupKey] = group
+		} else {
+			group.NodeTaints = commonTaints(group.NodeTaints, schedulingTaints(node))
+		}
+		group.Nodes = append(group.Nodes, node)
+	}
+
+	groupKeys := make([]string, 0, len(groups))
+	for key := range groups {
+		groupKeys = append(groupKeys, key)
+	}
+	sort.Strings(groupKeys)
+
+	pools := make([]NodePool, 0, len(groups))
+	names := map[string]bool{}
+	for _, key := range groupKeys {
+		pool := groups[key]
+		pool.Name = flavorName(pool.NodeLabels, labelKeys, key)
+		if names[pool.Name] {
+			// Different label values can sanitize to the same name.
+			pool.Name = hashedName(pool.Name, key)
+		}
+		names[pool.Name] = true
+		pools = append(pools, *pool)
+	}
+	sort.Slice(pools, func(i, j int) bool { return pools[i].Name < pools[j].Name })
+	return pools
+}
+
+// BuildNodePoolResourceFlavor returns the ResourceFlavor of a node pool.
+func BuildNodePoolResourceFlavor(pool NodePool) *kueuev1beta1.ResourceFlavor {
+	flavor := BuildResourceFlavor(pool.Name)
+	flavor.Spec.NodeLabels = pool.NodeLabels
+	flavor.Spec.NodeTaints = pool.NodeTaints
+	return flavor
+}
+
+// flavorName joins the label values in the order of labelKeys into a valid object name,
+// for example "m5.xlarge-nvidia-a100-sxm4-40gb".
+func flavorName(nodeLabels map[string]string, labelKeys []string, groupKey string) string {
+	parts := make([]string, 0, len(nodeLabels))
+	for _, key := range labelKeys {
+		if value, ok := nodeLabels[key]; ok {
+			parts = append(parts, value)
+		}
+	}
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-', r == '.':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r - 'A' + 'a'
+		default:
+			return '-'
+		}
+	}, strings.Join(parts, "-"))
+	name = strings.Trim(name, "-.")
+	if len(name) > validation.DNS1123LabelMaxLength || len(validation.IsDNS1123Subdomain(name)) > 0 {
+		return hashedName(name, groupKey)
+	}
+	return name
+}
+
+// hashedName shortens name and appends a hash of groupKey so that it is unique and valid.
+func hashedName(name, groupKey string) string {
+	h := fnv.New32a()
+	h.Write([]byte(groupKey))
+	suffix := fmt.Sprintf("%08x", h.Sum32())
+	if max := validation.DNS1123LabelMaxLength - len(suffix) - 1; len(name) > max {
+		name = name[:max]
+	}
+	name = strings.Trim(name, "-.")
+	if len(name) == 0 {
+		return "flavor-" + suffix
+	}
+	return name + "-" + suffix
+}
+
+// schedulingTaints returns the taints of node that keep pods off it, except the ones the
+// node lifecycle controller adds temporarily.
+func schedulingTaints(node *corev1.Node) []corev1.Taint {
+	var taints []corev1.Taint
+	for _, taint := range node.Spec.Taints {
+		if taint.Effect != corev1.TaintEffectNoSchedule && taint.Effect != corev1.TaintEffectNoExecute {
+			continue
+		}
+		if strings.HasPrefix(taint.Key, "node.kubernetes.io/") || strings.HasPrefix(taint.Key, "node.cloudprovider.kubernetes.io/") {
+			continue
+		}
+		taints = append(taints, corev1.Taint{Key: taint.Key, Value: taint.Value, Effect: taint.Effect})
+	}
+	sort.Slice(taints, func(i, j int) bool {
+		if taints[i].Key != taints[j].Key {
+			return taints[i].Key < taints[j].Key
+		}
+		return taints[i].Effect < taints[j].Effect
+	})
+	return taints
+}
+
+func commonTaints(a, b []corev1.Taint) []corev1.Taint {
+	var common []corev1.Taint
+	for _, taint := range a {
+		for _, other := range b {
+			if taint.MatchTaint(&other) && taint.Value == other.Value {
+				common = append(common, taint)
+				break
+			}
+		}
+	}
+	return common
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queues
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	instanceTypeLabel = "node.kubernetes.io/instance-type"
+	gpuProductLabel   = "nvidia.com/gpu.product"
+)
+
+func poolNode(name string, labels map[string]string, taints ...corev1.Taint) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+		Spec:       corev1.NodeSpec{Taints: taints},
+	}
+}
+
+func TestNodePools(t *testing.T) {
+	gpuTaint := corev1.Taint{Key: "nvidia.com/gpu", Value: "present", Effect: corev1.TaintEffectNoSchedule}
+	spotTaint := corev1.Taint{Key: "spot", Effect: corev1.TaintEffectNoExecute}
+	nodes := []*corev1.Node{
+		poolNode("cpu-0", map[string]string{instanceTypeLabel: "m5.xlarge"}),
+		poolNode("cpu-1", map[string]string{instanceTypeLabel: "m5.xlarge"},
+			corev1.Taint{Key: "node.kubernetes.io/unreachable", Effect: corev1.TaintEffectNoExecute}),
+		poolNode("gpu-0", map[string]string{instanceTypeLabel: "p4d.24xlarge", gpuProductLabel: "NVIDIA-A100-SXM4-40GB"}, gpuTaint, spotTaint),
+		poolNode("gpu-1", map[string]string{instanceTypeLabel: "p4d.24xlarge", gpuProductLabel: "NVIDIA-A100-SXM4-40GB"}, gpuTaint,
+			corev1.Taint{Key: "dedicated", Effect: corev1.TaintEffectPreferNoSchedule}),
+		poolNode("bare-metal", map[string]string{"kubernetes.io/hostname": "bare-metal"}),
+	}
+
+	pools := NodePools(nodes, []string{instanceTypeLabel, gpuProductLabel})
+
+	type pool struct {
+		Name       string
+		NodeLabels map[string]string
+		NodeTaints []corev1.Taint
+		Nodes      []string
+	}
+	var got []pool
+	for _, p := range pools {
+		var names []string
+		for _, node := range p.Nodes {
+			names = append(names, node.Name)
+		}
+		got = append(got, pool{Name: p.Name, NodeLabels: p.NodeLabels, NodeTaints: p.NodeTaints, Nodes: names})
+	}
+	want := []pool{
+		{
+			Name:       "m5.xlarge",
+			NodeLabels: map[string]string{instanceTypeLabel: "m5.xlarge"},
+			Nodes:      []string{"cpu-0", "cpu-1"},
+		},
+		{
+			Name:       "p4d.24xlarge-nvidia-a100-sxm4-40gb",
+			NodeLabels: map[string]string{instanceTypeLabel: "p4d.24xlarge", gpuProductLabel: "NVIDIA-A100-SXM4-40GB"},
+			NodeTaints: []corev1.Taint{gpuTaint},
+			Nodes:      []string{"gpu-0", "gpu-1"},
+		},
+	}
+	if diff := cmp.Diff(want, got); len(diff) != 0 {
+		t.Errorf("Unexpected node pools (-want,+got):\n%s", diff)
+	}
+}
+
+func TestNodePoolNames(t *testing.T) {
+	nodes := []*corev1.Node{
+		poolNode("a", map[string]string{"pool": "GPU_A"}),
+		poolNode("b", map[string]string{"pool": "gpu-a"}),
+		poolNode("c", map[string]string{"pool": strings.Repeat("x", 80)}),
+	}
+	pools := NodePools(nodes, []string{"pool"})
+	if len(pools) != 3 {
+		t.Fatalf("Expected 3 pools, got %d", len(pools))
+	}
+	names := map[string]bool{}
+	for _, pool := range pools {
+		if len(pool.Name) > 63 {
+			t.Errorf("Name %q is longer than 63 characters", pool.Name)
+		}
+		names[pool.Name] = true
+	}
+	if len(names) != 3 {
+		t.Errorf("Expected unique names, got %v", names)
+	}
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queues
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kueuev1beta1 "sigs.k8s.io/kueue/apis/kueue/v1beta1"
+)
+
+var (
+	ResourceFlavorsGVR = kueuev1beta1.GroupVersion.WithResource("resourceflavors")
+	ClusterQueuesGVR   = kueuev1beta1.GroupVersion.WithResource("clusterqueues")
+	LocalQueuesGVR     = kueuev1beta1.GroupVersion.WithResource("localqueues")
+
+	WorkloadPriorityClassesGVR = kueuev1beta1.GroupVersion.WithResource("workloadpriorityclasses")
+	AdmissionChecksGVR         = kueuev1beta1.GroupVersion.WithResource("admissionchecks")
+	WorkloadsGVR               = kueuev1beta1.GroupVersion.WithResource("workloads")
+)
+
+func BuildResourceFlavor(name string) *kueuev1beta1.ResourceFlavor {
+	return &kueuev1beta1.ResourceFlavor{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: kueuev1beta1.GroupVersion.String(),
+			Kind:       "ResourceFlavor",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+	}
+}
+
+// BuildClusterQueue returns a ClusterQueue with a single flavor whose nominal quota for
+// each covered resource is taken from quota. A nil namespaceSelector admits workloads
+// from every namespace.
+func BuildClusterQueue(name, flavor string, namespaceSelector *metav1.LabelSelector, coveredResources []corev1.ResourceName, quota corev1.ResourceList) *kueuev1beta1.ClusterQueue {
+	if namespaceSelector == nil {
+		namespaceSelector = &metav1.LabelSelector{}
+	}
+	resources := make([]kueuev1beta1.ResourceQuota, 0, len(coveredResources))
+	for _, name := range coveredResources {
+		nominalQuota, ok := quota[name]
+		if !ok {
+			nominalQuota = resource.MustParse("0")
+		}
+		resources = append(resources, kueuev1beta1.ResourceQuota{
+			Name:         name,
+			NominalQuota: nominalQuota,
+		})
+	}
+	return &kueuev1beta1.ClusterQueue{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: kueuev1beta1.GroupVersion.String(),
+			Kind:       "ClusterQueue",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Spec: kueuev1beta1.ClusterQueueSpec{
+			NamespaceSelector: namespaceSelector,
+			ResourceGroups: []kueuev1beta1.ResourceGroup{{
+				CoveredResources: coveredResources,
+				Flavors: []kueuev1beta1.FlavorQuotas{{
+					Name:      kueuev1beta1.ResourceFlavorReference(flavor),
+					Resources: resources,
+				}},
+			}},
+		},
+	}
+}
+
+func BuildLocalQueue(namespace, name, clusterQueue string) *kueuev1beta1.LocalQueue {
+	return &kueuev1beta1.LocalQueue{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: kueuev1beta1.GroupVersion.String(),
+			Kind:       "LocalQueue",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: kueuev1beta1.LocalQueueSpec{
+			ClusterQueue: kueuev1beta1.ClusterQueueReference(clusterQueue),
+		},
+	}
+}
+
+func BuildWorkloadPriorityClass(name string, value int32, description string) *kueuev1beta1.WorkloadPriorityClass {
+	return &kueuev1beta1.WorkloadPriorityClass{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: kueuev1beta1.GroupVersion.String(),
+			Kind:       "WorkloadPriorityClass",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Value:       value,
+		Description: description,
+	}
+}
+
+// AllocatableQuota sums the allocatable capacity of the given resources over the nodes
+// that accept new pods.
+func AllocatableQuota(nodes []*corev1.Node, resources []corev1.ResourceName) corev1.ResourceList {
+	quota := corev1.ResourceList{}
+	for _, name := range resources {
+		quota[name] = resource.MustParse("0")
+	}
+	for _, node := range nodes {
+		if node.Spec.Unschedulable {
+			continue
+		}
+		for _, name := range resources {
+			allocatable, ok := node.Status.Allocatable[name]
+			if !ok {
+				continue
+			}
+			total := quota[name]
+			total.Add(allocatable)
+			quota[name] = total
+		}
+	}
+	return quota
+}
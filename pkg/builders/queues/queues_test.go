@@ -0,0 +1,71 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queues
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kueuev1beta1 "sigs.k8s.io/kueue/apis/kueue/v1beta1"
+)
+
+func TestBuildClusterQueue(t *testing.T) {
+	covered := []corev1.ResourceName{corev1.ResourceCPU, "nvidia.com/gpu"}
+	got := BuildClusterQueue("cluster-queue", "default-flavor", nil, covered, corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("6"),
+		corev1.ResourceMemory: resource.MustParse("24Gi"),
+	})
+
+	want := kueuev1beta1.ClusterQueueSpec{
+		NamespaceSelector: &metav1.LabelSelector{},
+		ResourceGroups: []kueuev1beta1.ResourceGroup{{
+			CoveredResources: covered,
+			Flavors: []kueuev1beta1.FlavorQuotas{{
+				Name: "default-flavor",
+				Resources: []kueuev1beta1.ResourceQuota{
+					{Name: corev1.ResourceCPU, NominalQuota: resource.MustParse("6")},
+					{Name: "nvidia.com/gpu", NominalQuota: resource.MustParse("0")},
+				},
+			}},
+		}},
+	}
+	if diff := cmp.Diff(want, got.Spec); len(diff) != 0 {
+		t.Errorf("Unexpected spec (-want,+got):\n%s", diff)
+	}
+}
+
+func TestAllocatableQuota(t *testing.T) {
+	node := func(cpu string, unschedulable bool) *corev1.Node {
+		return &corev1.Node{
+			Spec: corev1.NodeSpec{Unschedulable: unschedulable},
+			Status: corev1.NodeStatus{Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU: resource.MustParse(cpu),
+			}},
+		}
+	}
+	got := AllocatableQuota([]*corev1.Node{node("3500m", false), node("2", false), node("8", true)}, []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory})
+	if cpu := got[corev1.ResourceCPU]; cpu.Cmp(resource.MustParse("5500m")) != 0 {
+		t.Errorf("Unexpected cpu quota %s", cpu.String())
+	}
+	if memory := got[corev1.ResourceMemory]; !memory.IsZero() {
+		t.Errorf("Unexpected memory quota %s", memory.String())
+	}
+}
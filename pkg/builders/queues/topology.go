@@ -0,0 +1,78 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queues
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// TopologiesGVR is the Topology API of Kueue. It is still alpha and its Go types are
+// not vendored, Topologies are handled as unstructured objects.
+var TopologiesGVR = schema.GroupVersionResource{Group: "kueue.x-k8s.io", Version: "v1alpha1", Resource: "topologies"}
+
+// BuildTopology returns a Topology with one level per node label, from the broadest to
+// the narrowest.
+func BuildTopology(name string, levels []string) *unstructured.Unstructured {
+	topologyLevels := make([]interface{}, 0, len(levels))
+	for _, level := range levels {
+		topologyLevels = append(topologyLevels, map[string]interface{}{"nodeLabel": level})
+	}
+	topology := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"levels": topologyLevels,
+		},
+	}}
+	topology.SetAPIVersion(TopologiesGVR.GroupVersion().String())
+	topology.SetKind("Topology")
+	topology.SetName(name)
+	return topology
+}
+
+// TopologyLevels returns the node labels of the levels of a Topology.
+func TopologyLevels(topology *unstructured.Unstructured) []string {
+	items, _, _ := unstructured.NestedSlice(topology.Object, "spec", "levels")
+	levels := make([]string, 0, len(items))
+	for _, item := range items {
+		if level, ok := item.(map[string]interface{}); ok {
+			if nodeLabel, ok := level["nodeLabel"].(string); ok {
+				levels = append(levels, nodeLabel)
+			}
+		}
+	}
+	return levels
+}
+
+// TopologyNodes returns the nodes that have a label for every level, the only nodes
+// Kueue places workloads on with the topology.
+func TopologyNodes(nodes []*corev1.Node, levels []string) []*corev1.Node {
+	var selected []*corev1.Node
+	for _, node := range nodes {
+		complete := true
+		for _, level := range levels {
+			if len(node.Labels[level]) == 0 {
+				complete = false
+				break
+			}
+		}
+		if complete {
+			selected = append(selected, node)
+		}
+	}
+	return selected
+}
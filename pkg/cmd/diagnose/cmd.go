@@ -0,0 +1,108 @@
+package diagnose
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/openshift/kueue-operator/pkg/diagnose"
+	kueueoperatorclient "github.com/openshift/kueue-operator/pkg/generated/clientset/versioned"
+	"github.com/openshift/kueue-operator/pkg/namespace"
+)
+
+type options struct {
+	kubeconfig string
+	namespace  string
+	output     string
+	tailLines  int64
+}
+
+func NewDiagnose() *cobra.Command {
+	o := &options{
+		namespace: namespace.GetNamespace(),
+		tailLines: 10000,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "diagnose",
+		Short: "Collect a diagnostics bundle of the Kueue installation for support cases",
+		Long: `Collect the Kueue CR, every object listed in its status.relatedObjects (the generated
+kueue-manager-config ConfigMap, the Deployment, webhook configurations, CRDs and RBAC),
+pod status and logs, events, ClusterQueues, LocalQueues, ResourceFlavors and a summary
+of Workloads into a gzipped tarball.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.run(cmd.Context())
+		},
+	}
+
+	cmd.Flags().StringVar(&o.kubeconfig, "kubeconfig", o.kubeconfig, "Path to the kubeconfig file. Defaults to the in-cluster config or $KUBECONFIG.")
+	cmd.Flags().StringVarP(&o.namespace, "namespace", "n", o.namespace, "Namespace the Kueue operator is installed in.")
+	cmd.Flags().StringVarP(&o.output, "output", "o", o.output, "Path of the tarball to write. Defaults to kueue-diagnostics-<timestamp>.tar.gz.")
+	cmd.Flags().Int64Var(&o.tailLines, "tail", o.tailLines, "Number of log lines to collect per container. Zero collects the whole log.")
+
+	return cmd
+}
+
+func (o *options) run(ctx context.Context) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = o.kubeconfig
+	restConfig, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{}).ClientConfig()
+	if err != nil {
+		return err
+	}
+
+	kubeClient, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+	operatorClient, err := kueueoperatorclient.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+	dynamicClient, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+
+	output := o.output
+	if len(output) == 0 {
+		output = fmt.Sprintf("kueue-diagnostics-%s.tar.gz", time.Now().UTC().Format("20060102-150405"))
+	}
+	f, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	collector := &diagnose.Collector{
+		KubeClient:     kubeClient,
+		OperatorClient: operatorClient,
+		DynamicClient:  dynamicClient,
+		RESTMapper:     restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(discoveryClient)),
+		Namespace:      o.namespace,
+		TailLines:      o.tailLines,
+	}
+	if err := collector.Collect(ctx, f); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stdout, "Diagnostics written to %s\n", output)
+	return nil
+}
@@ -0,0 +1,97 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/yaml"
+
+	kueuev1alpha1 "github.com/openshift/kueue-operator/pkg/apis/kueueoperator/v1alpha1"
+	"github.com/openshift/kueue-operator/pkg/namespace"
+	"github.com/openshift/kueue-operator/pkg/operator"
+)
+
+type options struct {
+	kueueCR   string
+	namespace string
+}
+
+func NewRender() *cobra.Command {
+	o := &options{
+		namespace: namespace.GetNamespace(),
+	}
+
+	cmd := &cobra.Command{
+		Use:   "render",
+		Short: "Print every manifest the operator would apply for a Kueue CR",
+		Long: `Render runs the same rendering as the operator reconciler offline and prints the
+resulting objects as a YAML stream, so changes to a Kueue CR can be reviewed and diffed
+before they reach a cluster. Pod template annotations that track the resourceVersions
+of applied objects are not rendered.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.run(cmd.OutOrStdout())
+		},
+	}
+
+	cmd.Flags().StringVar(&o.kueueCR, "kueue-cr", o.kueueCR, "Path to the Kueue CR to render.")
+	cmd.Flags().StringVarP(&o.namespace, "namespace", "n", o.namespace, "Namespace used when the Kueue CR does not set one.")
+	if err := cmd.MarkFlagRequired("kueue-cr"); err != nil {
+		panic(err)
+	}
+
+	return cmd
+}
+
+func (o *options) run(out io.Writer) error {
+	data, err := os.ReadFile(o.kueueCR)
+	if err != nil {
+		return err
+	}
+	kueue := &kueuev1alpha1.Kueue{}
+	if err := yaml.UnmarshalStrict(data, kueue); err != nil {
+		return fmt.Errorf("unable to decode %s: %w", o.kueueCR, err)
+	}
+	if len(kueue.Namespace) == 0 {
+		kueue.Namespace = o.namespace
+	}
+
+	objects, err := operator.RenderManifests(kueue)
+	if err != nil {
+		return err
+	}
+	return printObjects(out, objects)
+}
+
+var renderScheme = runtime.NewScheme()
+
+func init() {
+	utilruntime.Must(scheme.AddToScheme(renderScheme))
+	utilruntime.Must(apiextensionsv1.AddToScheme(renderScheme))
+}
+
+// printObjects writes objects as a YAML stream. Objects read from bindata lose their
+// TypeMeta when decoded, so it is restored from the scheme.
+func printObjects(out io.Writer, objects []runtime.Object) error {
+	for _, obj := range objects {
+		gvks, _, err := renderScheme.ObjectKinds(obj)
+		if err != nil {
+			return err
+		}
+		obj.GetObjectKind().SetGroupVersionKind(gvks[0])
+
+		data, err := yaml.Marshal(obj)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(out, "---\n%s", data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
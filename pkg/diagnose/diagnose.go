@@ -0,0 +1,313 @@
+package diagnose
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+
+	kueueoperatorclient "github.com/openshift/kueue-operator/pkg/generated/clientset/versioned"
+)
+
+var (
+	clusterQueuesGVR   = schema.GroupVersionResource{Group: "kueue.x-k8s.io", Version: "v1beta1", Resource: "clusterqueues"}
+	localQueuesGVR     = schema.GroupVersionResource{Group: "kueue.x-k8s.io", Version: "v1beta1", Resource: "localqueues"}
+	resourceFlavorsGVR = schema.GroupVersionResource{Group: "kueue.x-k8s.io", Version: "v1beta1", Resource: "resourceflavors"}
+	workloadsGVR       = schema.GroupVersionResource{Group: "kueue.x-k8s.io", Version: "v1beta1", Resource: "workloads"}
+)
+
+// Collector gathers the state of a Kueue installation into a gzipped tarball
+// that can be attached to a support case.
+type Collector struct {
+	KubeClient     kubernetes.Interface
+	OperatorClient kueueoperatorclient.Interface
+	DynamicClient  dynamic.Interface
+	// RESTMapper resolves the group/resource pairs published in status.relatedObjects.
+	RESTMapper meta.RESTMapper
+	// Namespace is the namespace the operator and its operand run in.
+	Namespace string
+	// TailLines limits the number of log lines collected per container.
+	TailLines int64
+	// Now returns the time recorded in the tarball headers.
+	Now func() time.Time
+}
+
+// bundle is a tar archive being written by a Collector. Failures to collect a single
+// item are recorded in errors.txt instead of aborting the whole collection.
+type bundle struct {
+	tw     *tar.Writer
+	now    time.Time
+	errors []string
+}
+
+func (b *bundle) addFile(name string, data []byte) error {
+	header := &tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    int64(len(data)),
+		ModTime: b.now,
+	}
+	if err := b.tw.WriteHeader(header); err != nil {
+		return err
+	}
+	_, err := b.tw.Write(data)
+	return err
+}
+
+func (b *bundle) addYAML(name string, obj interface{}) error {
+	data, err := yaml.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	return b.addFile(name, data)
+}
+
+func (b *bundle) recordError(what string, err error) {
+	klog.Warningf("unable to collect %s: %v", what, err)
+	b.errors = append(b.errors, fmt.Sprintf("%s: %v", what, err))
+}
+
+// Collect writes the diagnostics tarball to w.
+func (c *Collector) Collect(ctx context.Context, w io.Writer) error {
+	now := time.Now()
+	if c.Now != nil {
+		now = c.Now()
+	}
+	gz := gzip.NewWriter(w)
+	b := &bundle{tw: tar.NewWriter(gz), now: now}
+
+	collectors := []func(context.Context, *bundle) error{
+		c.collectKueues,
+		c.collectPods,
+		c.collectEvents,
+		c.collectKueueResources,
+		c.collectWorkloadSummary,
+	}
+	for _, collect := range collectors {
+		if err := collect(ctx, b); err != nil {
+			return err
+		}
+	}
+
+	if len(b.errors) > 0 {
+		if err := b.addFile("errors.txt", []byte(strings.Join(b.errors, "\n")+"\n")); err != nil {
+			return err
+		}
+	}
+	if err := b.tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// collectKueues stores the Kueue CRs and every object they list in status.relatedObjects,
+// which covers the generated ConfigMap, the Deployment, webhooks, CRDs and RBAC. The
+// values of Secrets are redacted.
+func (c *Collector) collectKueues(ctx context.Context, b *bundle) error {
+	kueues, err := c.OperatorClient.KueueV1alpha1().Kueues(c.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		b.recordError("kueues", err)
+		return nil
+	}
+	for i := range kueues.Items {
+		kueue := &kueues.Items[i]
+		if err := b.addYAML(path.Join("kueues", kueue.Name+".yaml"), kueue); err != nil {
+			return err
+		}
+		for _, ref := range kueue.Status.RelatedObjects {
+			if ref.Group == "operator.openshift.io" && ref.Resource == "kueues" {
+				continue
+			}
+			what := path.Join(relatedObjectDir(ref.Group, ref.Resource), ref.Namespace, ref.Name)
+			gvr, err := c.RESTMapper.ResourceFor(schema.GroupVersionResource{Group: ref.Group, Resource: ref.Resource})
+			if err != nil {
+				b.recordError(what, err)
+				continue
+			}
+			var obj *unstructured.Unstructured
+			if len(ref.Namespace) > 0 {
+				obj, err = c.DynamicClient.Resource(gvr).Namespace(ref.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+			} else {
+				obj, err = c.DynamicClient.Resource(gvr).Get(ctx, ref.Name, metav1.GetOptions{})
+			}
+			if err != nil {
+				b.recordError(what, err)
+				continue
+			}
+			if len(ref.Group) == 0 && ref.Resource == "secrets" {
+				redactSecret(obj)
+			}
+			if err := b.addYAML(what+".yaml", obj.Object); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// redactSecret replaces the values of a Secret, such as the private key of the webhook
+// certificate, with their size. The keys are kept to tell an empty Secret from a filled one.
+func redactSecret(obj *unstructured.Unstructured) {
+	for _, field := range []string{"data", "stringData"} {
+		values, found, _ := unstructured.NestedMap(obj.Object, field)
+		if !found {
+			continue
+		}
+		for key, value := range values {
+			size := 0
+			if value, ok := value.(string); ok {
+				size = len(value)
+			}
+			values[key] = fmt.Sprintf("<redacted %d bytes>", size)
+		}
+		_ = unstructured.SetNestedMap(obj.Object, values, field)
+	}
+	// kubectl apply keeps a copy of the whole object, data included, in an annotation.
+	annotations := obj.GetAnnotations()
+	if _, ok := annotations[corev1.LastAppliedConfigAnnotation]; ok {
+		delete(annotations, corev1.LastAppliedConfigAnnotation)
+		obj.SetAnnotations(annotations)
+	}
+}
+
+func relatedObjectDir(group, resource string) string {
+	if len(group) == 0 {
+		return path.Join("core", resource)
+	}
+	return path.Join(group, resource)
+}
+
+// collectPods stores the status and logs of every pod in the operator namespace,
+// including the logs of the previous container instance when it restarted.
+func (c *Collector) collectPods(ctx context.Context, b *bundle) error {
+	pods, err := c.KubeClient.CoreV1().Pods(c.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		b.recordError("pods", err)
+		return nil
+	}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		dir := path.Join("pods", pod.Name)
+		if err := b.addYAML(path.Join(dir, pod.Name+".yaml"), pod); err != nil {
+			return err
+		}
+		for _, status := range pod.Status.ContainerStatuses {
+			if err := c.collectLogs(ctx, b, pod, status.Name, false); err != nil {
+				return err
+			}
+			if status.RestartCount > 0 {
+				if err := c.collectLogs(ctx, b, pod, status.Name, true); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (c *Collector) collectLogs(ctx context.Context, b *bundle, pod *corev1.Pod, container string, previous bool) error {
+	name := container + ".log"
+	if previous {
+		name = container + ".previous.log"
+	}
+	name = path.Join("pods", pod.Name, name)
+
+	opts := &corev1.PodLogOptions{Container: container, Previous: previous}
+	if c.TailLines > 0 {
+		opts.TailLines = &c.TailLines
+	}
+	stream, err := c.KubeClient.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, opts).Stream(ctx)
+	if err != nil {
+		b.recordError(name, err)
+		return nil
+	}
+	defer stream.Close()
+	logs, err := io.ReadAll(stream)
+	if err != nil {
+		b.recordError(name, err)
+		return nil
+	}
+	return b.addFile(name, logs)
+}
+
+func (c *Collector) collectEvents(ctx context.Context, b *bundle) error {
+	events, err := c.KubeClient.CoreV1().Events(c.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		b.recordError("events", err)
+		return nil
+	}
+	return b.addYAML("events.yaml", events)
+}
+
+// collectKueueResources stores the cluster-wide Kueue objects that shape admission.
+func (c *Collector) collectKueueResources(ctx context.Context, b *bundle) error {
+	for _, gvr := range []schema.GroupVersionResource{clusterQueuesGVR, localQueuesGVR, resourceFlavorsGVR} {
+		list, err := c.DynamicClient.Resource(gvr).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			b.recordError(gvr.Resource, err)
+			continue
+		}
+		if err := b.addYAML(path.Join(gvr.Group, gvr.Resource+".yaml"), list.UnstructuredContent()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// collectWorkloadSummary stores one line per Workload instead of the full objects,
+// which can be numerous and large on busy clusters.
+func (c *Collector) collectWorkloadSummary(ctx context.Context, b *bundle) error {
+	list, err := c.DynamicClient.Resource(workloadsGVR).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		b.recordError(workloadsGVR.Resource, err)
+		return nil
+	}
+
+	var sb strings.Builder
+	tw := tabwriter.NewWriter(&sb, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAMESPACE\tNAME\tQUEUE\tQUOTARESERVED\tADMITTED\tFINISHED")
+	for _, workload := range list.Items {
+		queue, _, _ := unstructured.NestedString(workload.Object, "spec", "queueName")
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			workload.GetNamespace(),
+			workload.GetName(),
+			queue,
+			conditionStatus(&workload, "QuotaReserved"),
+			conditionStatus(&workload, "Admitted"),
+			conditionStatus(&workload, "Finished"),
+		)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	return b.addFile(path.Join(workloadsGVR.Group, "workloads-summary.txt"), []byte(sb.String()))
+}
+
+func conditionStatus(obj *unstructured.Unstructured, conditionType string) string {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, condition := range conditions {
+		condition, ok := condition.(map[string]interface{})
+		if !ok || condition["type"] != conditionType {
+			continue
+		}
+		if status, ok := condition["status"].(string); ok {
+			return status
+		}
+	}
+	return string(metav1.ConditionUnknown)
+}
@@ -0,0 +1,186 @@
+package diagnose
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	configv1 "github.com/openshift/api/config/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	kueue "github.com/openshift/kueue-operator/pkg/apis/kueueoperator/v1alpha1"
+	operatorfake "github.com/openshift/kueue-operator/pkg/generated/clientset/versioned/fake"
+)
+
+func TestCollect(t *testing.T) {
+	const ns = "openshift-kueue-operator"
+
+	kueueCR := &kueue.Kueue{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: ns},
+		Status: kueue.KueueStatus{
+			RelatedObjects: []configv1.ObjectReference{
+				{Group: "operator.openshift.io", Resource: "kueues", Namespace: ns, Name: "cluster"},
+				{Group: "", Resource: "configmaps", Namespace: ns, Name: "kueue-manager-config"},
+				{Group: "admissionregistration.k8s.io", Resource: "mutatingwebhookconfigurations", Name: "kueue-mutating-webhook-configuration"},
+				{Group: "", Resource: "secrets", Namespace: ns, Name: "kueue-webhook-server-cert"},
+			},
+		},
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "kueue-abc", Namespace: ns},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{
+				{Name: "manager", RestartCount: 1},
+			},
+		},
+	}
+
+	configMapGVR := schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+	webhookGVR := schema.GroupVersionResource{Group: "admissionregistration.k8s.io", Version: "v1", Resource: "mutatingwebhookconfigurations"}
+	secretGVR := schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Secret"}, meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Group: "admissionregistration.k8s.io", Version: "v1", Kind: "MutatingWebhookConfiguration"}, meta.RESTScopeRoot)
+
+	newUnstructured := func(apiVersion, kind, namespace, name string, fields map[string]interface{}) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+		for k, v := range fields {
+			obj.Object[k] = v
+		}
+		obj.SetAPIVersion(apiVersion)
+		obj.SetKind(kind)
+		obj.SetNamespace(namespace)
+		obj.SetName(name)
+		return obj
+	}
+
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{
+			configMapGVR:       "ConfigMapList",
+			webhookGVR:         "MutatingWebhookConfigurationList",
+			secretGVR:          "SecretList",
+			clusterQueuesGVR:   "ClusterQueueList",
+			localQueuesGVR:     "LocalQueueList",
+			resourceFlavorsGVR: "ResourceFlavorList",
+			workloadsGVR:       "WorkloadList",
+		},
+		newUnstructured("v1", "ConfigMap", ns, "kueue-manager-config", nil),
+		newUnstructured("admissionregistration.k8s.io/v1", "MutatingWebhookConfiguration", "", "kueue-mutating-webhook-configuration", nil),
+		newUnstructured("v1", "Secret", ns, "kueue-webhook-server-cert", map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"annotations": map[string]interface{}{
+					corev1.LastAppliedConfigAnnotation: `{"data":{"tls.key":"cHJpdmF0ZS1rZXk="}}`,
+				},
+			},
+			"data":       map[string]interface{}{"tls.crt": "Y2VydGlmaWNhdGU=", "tls.key": "cHJpdmF0ZS1rZXk="},
+			"stringData": map[string]interface{}{"token": "s3cr3t"},
+		}),
+		newUnstructured("kueue.x-k8s.io/v1beta1", "ClusterQueue", "", "cluster-queue", nil),
+		newUnstructured("kueue.x-k8s.io/v1beta1", "Workload", "team-a", "job-1", map[string]interface{}{
+			"spec": map[string]interface{}{"queueName": "user-queue"},
+			"status": map[string]interface{}{
+				"conditions": []interface{}{
+					map[string]interface{}{"type": "Admitted", "status": "True"},
+				},
+			},
+		}),
+	)
+
+	collector := &Collector{
+		KubeClient:     kubefake.NewSimpleClientset(pod),
+		OperatorClient: operatorfake.NewSimpleClientset(kueueCR),
+		DynamicClient:  dynamicClient,
+		RESTMapper:     mapper,
+		Namespace:      ns,
+		Now:            func() time.Time { return time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC) },
+	}
+
+	var buf bytes.Buffer
+	if err := collector.Collect(context.Background(), &buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	files := readTarball(t, &buf)
+	var names []string
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	wantNames := []string{
+		"admissionregistration.k8s.io/mutatingwebhookconfigurations/kueue-mutating-webhook-configuration.yaml",
+		"core/configmaps/openshift-kueue-operator/kueue-manager-config.yaml",
+		"core/secrets/openshift-kueue-operator/kueue-webhook-server-cert.yaml",
+		"events.yaml",
+		"kueue.x-k8s.io/clusterqueues.yaml",
+		"kueue.x-k8s.io/localqueues.yaml",
+		"kueue.x-k8s.io/resourceflavors.yaml",
+		"kueue.x-k8s.io/workloads-summary.txt",
+		"kueues/cluster.yaml",
+		"pods/kueue-abc/kueue-abc.yaml",
+		"pods/kueue-abc/manager.log",
+		"pods/kueue-abc/manager.previous.log",
+	}
+	if diff := cmp.Diff(wantNames, names); len(diff) != 0 {
+		t.Errorf("Unexpected files (-want,+got):\n%s", diff)
+	}
+
+	secret := files["core/secrets/openshift-kueue-operator/kueue-webhook-server-cert.yaml"]
+	for _, value := range []string{"Y2VydGlmaWNhdGU=", "cHJpdmF0ZS1rZXk=", "s3cr3t"} {
+		for name, content := range files {
+			if strings.Contains(content, value) {
+				t.Errorf("Secret data %q leaked into %s", value, name)
+			}
+		}
+	}
+	if !strings.Contains(secret, "tls.key: <redacted 16 bytes>") {
+		t.Errorf("Expected the keys of the Secret to be kept:\n%s", secret)
+	}
+
+	summary := files["kueue.x-k8s.io/workloads-summary.txt"]
+	if !strings.Contains(summary, "team-a") || !strings.Contains(summary, "user-queue") {
+		t.Errorf("Unexpected workload summary:\n%s", summary)
+	}
+}
+
+func readTarball(t *testing.T, r io.Reader) map[string]string {
+	t.Helper()
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	tr := tar.NewReader(gz)
+	files := map[string]string{}
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		files[header.Name] = string(data)
+	}
+	return files
+}
@@ -18,16 +18,20 @@ limitations under the License.
 package v1alpha1
 
 import (
+	kueueoperatorv1alpha1 "github.com/openshift/kueue-operator/pkg/apis/kueueoperator/v1alpha1"
 	v1beta1 "sigs.k8s.io/kueue/apis/config/v1beta1"
 )
 
 // KueueConfigurationApplyConfiguration represents a declarative configuration of the KueueConfiguration type for use
 // with apply.
 type KueueConfigurationApplyConfiguration struct {
-	WaitForPodsReady *v1beta1.WaitForPodsReady `json:"waitForPodsReady,omitempty"`
-	Integrations     *v1beta1.Integrations     `json:"integrations,omitempty"`
-	FeatureGates     map[string]bool           `json:"featureGates,omitempty"`
-	Resources        *v1beta1.Resources        `json:"resources,omitempty"`
+	WaitForPodsReady     *v1beta1.WaitForPodsReady                 `json:"waitForPodsReady,omitempty"`
+	Integrations         *v1beta1.Integrations                     `json:"integrations,omitempty"`
+	FeatureGates         map[string]bool                           `json:"featureGates,omitempty"`
+	Resources            *v1beta1.Resources                        `json:"resources,omitempty"`
+	RevisionHistoryLimit *int32                                    `json:"revisionHistoryLimit,omitempty"`
+	RollbackTo           *int64                                    `json:"rollbackTo,omitempty"`
+	ChangePolicy         *kueueoperatorv1alpha1.ConfigChangePolicy `json:"changePolicy,omitempty"`
 }
 
 // KueueConfigurationApplyConfiguration constructs a declarative configuration of the KueueConfiguration type for use with
@@ -73,3 +77,27 @@ func (b *KueueConfigurationApplyConfiguration) WithResources(value v1beta1.Resou
 	b.Resources = &value
 	return b
 }
+
+// WithRevisionHistoryLimit sets the RevisionHistoryLimit field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the RevisionHistoryLimit field is set to the value of the last call.
+func (b *KueueConfigurationApplyConfiguration) WithRevisionHistoryLimit(value int32) *KueueConfigurationApplyConfiguration {
+	b.RevisionHistoryLimit = &value
+	return b
+}
+
+// WithRollbackTo sets the RollbackTo field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the RollbackTo field is set to the value of the last call.
+func (b *KueueConfigurationApplyConfiguration) WithRollbackTo(value int64) *KueueConfigurationApplyConfiguration {
+	b.RollbackTo = &value
+	return b
+}
+
+// WithChangePolicy sets the ChangePolicy field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ChangePolicy field is set to the value of the last call.
+func (b *KueueConfigurationApplyConfiguration) WithChangePolicy(value kueueoperatorv1alpha1.ConfigChangePolicy) *KueueConfigurationApplyConfiguration {
+	b.ChangePolicy = &value
+	return b
+}
@@ -27,8 +27,20 @@ import (
 // with apply.
 type KueueOperandSpecApplyConfiguration struct {
 	v1.OperatorSpecApplyConfiguration `json:",inline"`
-	Config                            *KueueConfigurationApplyConfiguration `json:"config,omitempty"`
-	Image                             *string                               `json:"image,omitempty"`
+	Config                            *KueueConfigurationApplyConfiguration      `json:"config,omitempty"`
+	Image                             *string                                    `json:"image,omitempty"`
+	Defaults                          *KueueDefaultsApplyConfiguration           `json:"defaults,omitempty"`
+	LocalQueueProvisioning            *LocalQueueProvisioningApplyConfiguration  `json:"localQueueProvisioning,omitempty"`
+	FlavorDiscovery                   *FlavorDiscoveryApplyConfiguration         `json:"flavorDiscovery,omitempty"`
+	QuotaManagement                   *QuotaManagementApplyConfiguration         `json:"quotaManagement,omitempty"`
+	QueueAccess                       []QueueAccessApplyConfiguration            `json:"queueAccess,omitempty"`
+	MultiKueue                        *MultiKueueApplyConfiguration              `json:"multiKueue,omitempty"`
+	ProvisioningRequest               *ProvisioningRequestApplyConfiguration     `json:"provisioningRequest,omitempty"`
+	TopologyAwareScheduling           *TopologyAwareSchedulingApplyConfiguration `json:"topologyAwareScheduling,omitempty"`
+	WorkloadPriorities                *WorkloadPrioritiesApplyConfiguration      `json:"workloadPriorities,omitempty"`
+	AdmissionChecks                   []AdmissionCheckPluginApplyConfiguration   `json:"admissionChecks,omitempty"`
+	AutomaticRollback                 *AutomaticRollbackApplyConfiguration       `json:"automaticRollback,omitempty"`
+	Canary                            *CanaryApplyConfiguration                  `json:"canary,omitempty"`
 }
 
 // KueueOperandSpecApplyConfiguration constructs a declarative configuration of the KueueOperandSpec type for use with
@@ -92,3 +104,109 @@ func (b *KueueOperandSpecApplyConfiguration) WithImage(value string) *KueueOpera
 	b.Image = &value
 	return b
 }
+
+// WithDefaults sets the Defaults field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Defaults field is set to the value of the last call.
+func (b *KueueOperandSpecApplyConfiguration) WithDefaults(value *KueueDefaultsApplyConfiguration) *KueueOperandSpecApplyConfiguration {
+	b.Defaults = value
+	return b
+}
+
+// WithLocalQueueProvisioning sets the LocalQueueProvisioning field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the LocalQueueProvisioning field is set to the value of the last call.
+func (b *KueueOperandSpecApplyConfiguration) WithLocalQueueProvisioning(value *LocalQueueProvisioningApplyConfiguration) *KueueOperandSpecApplyConfiguration {
+	b.LocalQueueProvisioning = value
+	return b
+}
+
+// WithFlavorDiscovery sets the FlavorDiscovery field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the FlavorDiscovery field is set to the value of the last call.
+func (b *KueueOperandSpecApplyConfiguration) WithFlavorDiscovery(value *FlavorDiscoveryApplyConfiguration) *KueueOperandSpecApplyConfiguration {
+	b.FlavorDiscovery = value
+	return b
+}
+
+// WithQuotaManagement sets the QuotaManagement field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the QuotaManagement field is set to the value of the last call.
+func (b *KueueOperandSpecApplyConfiguration) WithQuotaManagement(value *QuotaManagementApplyConfiguration) *KueueOperandSpecApplyConfiguration {
+	b.QuotaManagement = value
+	return b
+}
+
+// WithQueueAccess adds the given value to the QueueAccess field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the QueueAccess field.
+func (b *KueueOperandSpecApplyConfiguration) WithQueueAccess(values ...*QueueAccessApplyConfiguration) *KueueOperandSpecApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithQueueAccess")
+		}
+		b.QueueAccess = append(b.QueueAccess, *values[i])
+	}
+	return b
+}
+
+// WithMultiKueue sets the MultiKueue field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the MultiKueue field is set to the value of the last call.
+func (b *KueueOperandSpecApplyConfiguration) WithMultiKueue(value *MultiKueueApplyConfiguration) *KueueOperandSpecApplyConfiguration {
+	b.MultiKueue = value
+	return b
+}
+
+// WithProvisioningRequest sets the ProvisioningRequest field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ProvisioningRequest field is set to the value of the last call.
+func (b *KueueOperandSpecApplyConfiguration) WithProvisioningRequest(value *ProvisioningRequestApplyConfiguration) *KueueOperandSpecApplyConfiguration {
+	b.ProvisioningRequest = value
+	return b
+}
+
+// WithTopologyAwareScheduling sets the TopologyAwareScheduling field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the TopologyAwareScheduling field is set to the value of the last call.
+func (b *KueueOperandSpecApplyConfiguration) WithTopologyAwareScheduling(value *TopologyAwareSchedulingApplyConfiguration) *KueueOperandSpecApplyConfiguration {
+	b.TopologyAwareScheduling = value
+	return b
+}
+
+// WithWorkloadPriorities sets the WorkloadPriorities field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the WorkloadPriorities field is set to the value of the last call.
+func (b *KueueOperandSpecApplyConfiguration) WithWorkloadPriorities(value *WorkloadPrioritiesApplyConfiguration) *KueueOperandSpecApplyConfiguration {
+	b.WorkloadPriorities = value
+	return b
+}
+
+// WithAdmissionChecks adds the given value to the AdmissionChecks field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the AdmissionChecks field.
+func (b *KueueOperandSpecApplyConfiguration) WithAdmissionChecks(values ...*AdmissionCheckPluginApplyConfiguration) *KueueOperandSpecApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithAdmissionChecks")
+		}
+		b.AdmissionChecks = append(b.AdmissionChecks, *values[i])
+	}
+	return b
+}
+
+// WithAutomaticRollback sets the AutomaticRollback field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the AutomaticRollback field is set to the value of the last call.
+func (b *KueueOperandSpecApplyConfiguration) WithAutomaticRollback(value *AutomaticRollbackApplyConfiguration) *KueueOperandSpecApplyConfiguration {
+	b.AutomaticRollback = value
+	return b
+}
+
+// WithCanary sets the Canary field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Canary field is set to the value of the last call.
+func (b *KueueOperandSpecApplyConfiguration) WithCanary(value *CanaryApplyConfiguration) *KueueOperandSpecApplyConfiguration {
+	b.Canary = value
+	return b
+}
@@ -18,6 +18,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	configv1 "github.com/openshift/client-go/config/applyconfigurations/config/v1"
 	v1 "github.com/openshift/client-go/operator/applyconfigurations/operator/v1"
 )
 
@@ -25,6 +26,21 @@ import (
 // with apply.
 type KueueStatusApplyConfiguration struct {
 	v1.OperatorStatusApplyConfiguration `json:",inline"`
+	RelatedObjects                      []configv1.ObjectReferenceApplyConfiguration    `json:"relatedObjects,omitempty"`
+	Versions                            []configv1.OperandVersionApplyConfiguration     `json:"versions,omitempty"`
+	Resources                           []ResourceStatusApplyConfiguration              `json:"resources,omitempty"`
+	LocalQueues                         []LocalQueueMappingApplyConfiguration           `json:"localQueues,omitempty"`
+	DiscoveredFlavors                   []DiscoveredFlavorApplyConfiguration            `json:"discoveredFlavors,omitempty"`
+	FlavorCapacities                    []FlavorCapacityApplyConfiguration              `json:"flavorCapacities,omitempty"`
+	QuotaRecommendations                []QuotaRecommendationApplyConfiguration         `json:"quotaRecommendations,omitempty"`
+	QueueAccess                         []QueueAccessStatusApplyConfiguration           `json:"queueAccess,omitempty"`
+	MultiKueueWorkers                   []MultiKueueWorkerStatusApplyConfiguration      `json:"multiKueueWorkers,omitempty"`
+	Topologies                          []TopologyStatusApplyConfiguration              `json:"topologies,omitempty"`
+	WorkloadPriorityClasses             []WorkloadPriorityClassStatusApplyConfiguration `json:"workloadPriorityClasses,omitempty"`
+	ConfigRevisions                     []ConfigRevisionApplyConfiguration              `json:"configRevisions,omitempty"`
+	Rollout                             *RolloutStatusApplyConfiguration                `json:"rollout,omitempty"`
+	Canary                              *CanaryStatusApplyConfiguration                 `json:"canary,omitempty"`
+	AdmissionChecks                     []AdmissionCheckPluginStatusApplyConfiguration  `json:"admissionChecks,omitempty"`
 }
 
 // KueueStatusApplyConfiguration constructs a declarative configuration of the KueueStatus type for use with
@@ -90,3 +106,188 @@ func (b *KueueStatusApplyConfiguration) WithGenerations(values ...*v1.Generation
 	}
 	return b
 }
+
+// WithRelatedObjects adds the given value to the RelatedObjects field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the RelatedObjects field.
+func (b *KueueStatusApplyConfiguration) WithRelatedObjects(values ...*configv1.ObjectReferenceApplyConfiguration) *KueueStatusApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithRelatedObjects")
+		}
+		b.RelatedObjects = append(b.RelatedObjects, *values[i])
+	}
+	return b
+}
+
+// WithVersions adds the given value to the Versions field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the Versions field.
+func (b *KueueStatusApplyConfiguration) WithVersions(values ...*configv1.OperandVersionApplyConfiguration) *KueueStatusApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithVersions")
+		}
+		b.Versions = append(b.Versions, *values[i])
+	}
+	return b
+}
+
+// WithResources adds the given value to the Resources field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the Resources field.
+func (b *KueueStatusApplyConfiguration) WithResources(values ...*ResourceStatusApplyConfiguration) *KueueStatusApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithResources")
+		}
+		b.Resources = append(b.Resources, *values[i])
+	}
+	return b
+}
+
+// WithLocalQueues adds the given value to the LocalQueues field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the LocalQueues field.
+func (b *KueueStatusApplyConfiguration) WithLocalQueues(values ...*LocalQueueMappingApplyConfiguration) *KueueStatusApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithLocalQueues")
+		}
+		b.LocalQueues = append(b.LocalQueues, *values[i])
+	}
+	return b
+}
+
+// WithDiscoveredFlavors adds the given value to the DiscoveredFlavors field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the DiscoveredFlavors field.
+func (b *KueueStatusApplyConfiguration) WithDiscoveredFlavors(values ...*DiscoveredFlavorApplyConfiguration) *KueueStatusApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithDiscoveredFlavors")
+		}
+		b.DiscoveredFlavors = append(b.DiscoveredFlavors, *values[i])
+	}
+	return b
+}
+
+// WithFlavorCapacities adds the given value to the FlavorCapacities field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the FlavorCapacities field.
+func (b *KueueStatusApplyConfiguration) WithFlavorCapacities(values ...*FlavorCapacityApplyConfiguration) *KueueStatusApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithFlavorCapacities")
+		}
+		b.FlavorCapacities = append(b.FlavorCapacities, *values[i])
+	}
+	return b
+}
+
+// WithQuotaRecommendations adds the given value to the QuotaRecommendations field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the QuotaRecommendations field.
+func (b *KueueStatusApplyConfiguration) WithQuotaRecommendations(values ...*QuotaRecommendationApplyConfiguration) *KueueStatusApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithQuotaRecommendations")
+		}
+		b.QuotaRecommendations = append(b.QuotaRecommendations, *values[i])
+	}
+	return b
+}
+
+// WithQueueAccess adds the given value to the QueueAccess field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the QueueAccess field.
+func (b *KueueStatusApplyConfiguration) WithQueueAccess(values ...*QueueAccessStatusApplyConfiguration) *KueueStatusApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithQueueAccess")
+		}
+		b.QueueAccess = append(b.QueueAccess, *values[i])
+	}
+	return b
+}
+
+// WithMultiKueueWorkers adds the given value to the MultiKueueWorkers field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the MultiKueueWorkers field.
+func (b *KueueStatusApplyConfiguration) WithMultiKueueWorkers(values ...*MultiKueueWorkerStatusApplyConfiguration) *KueueStatusApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithMultiKueueWorkers")
+		}
+		b.MultiKueueWorkers = append(b.MultiKueueWorkers, *values[i])
+	}
+	return b
+}
+
+// WithTopologies adds the given value to the Topologies field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the Topologies field.
+func (b *KueueStatusApplyConfiguration) WithTopologies(values ...*TopologyStatusApplyConfiguration) *KueueStatusApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithTopologies")
+		}
+		b.Topologies = append(b.Topologies, *values[i])
+	}
+	return b
+}
+
+// WithWorkloadPriorityClasses adds the given value to the WorkloadPriorityClasses field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the WorkloadPriorityClasses field.
+func (b *KueueStatusApplyConfiguration) WithWorkloadPriorityClasses(values ...*WorkloadPriorityClassStatusApplyConfiguration) *KueueStatusApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithWorkloadPriorityClasses")
+		}
+		b.WorkloadPriorityClasses = append(b.WorkloadPriorityClasses, *values[i])
+	}
+	return b
+}
+
+// WithConfigRevisions adds the given value to the ConfigRevisions field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the ConfigRevisions field.
+func (b *KueueStatusApplyConfiguration) WithConfigRevisions(values ...*ConfigRevisionApplyConfiguration) *KueueStatusApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithConfigRevisions")
+		}
+		b.ConfigRevisions = append(b.ConfigRevisions, *values[i])
+	}
+	return b
+}
+
+// WithRollout sets the Rollout field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Rollout field is set to the value of the last call.
+func (b *KueueStatusApplyConfiguration) WithRollout(value *RolloutStatusApplyConfiguration) *KueueStatusApplyConfiguration {
+	b.Rollout = value
+	return b
+}
+
+// WithCanary sets the Canary field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Canary field is set to the value of the last call.
+func (b *KueueStatusApplyConfiguration) WithCanary(value *CanaryStatusApplyConfiguration) *KueueStatusApplyConfiguration {
+	b.Canary = value
+	return b
+}
+
+// WithAdmissionChecks adds the given value to the AdmissionChecks field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the AdmissionChecks field.
+func (b *KueueStatusApplyConfiguration) WithAdmissionChecks(values ...*AdmissionCheckPluginStatusApplyConfiguration) *KueueStatusApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithAdmissionChecks")
+		}
+		b.AdmissionChecks = append(b.AdmissionChecks, *values[i])
+	}
+	return b
+}
@@ -31,14 +31,78 @@ import (
 func ForKind(kind schema.GroupVersionKind) interface{} {
 	switch kind {
 	// Group=operator.openshift.io, Version=v1alpha1
+	case v1alpha1.SchemeGroupVersion.WithKind("AdmissionCheckHTTPCallout"):
+		return &kueueoperatorv1alpha1.AdmissionCheckHTTPCalloutApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("AdmissionCheckPlugin"):
+		return &kueueoperatorv1alpha1.AdmissionCheckPluginApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("AdmissionCheckPluginStatus"):
+		return &kueueoperatorv1alpha1.AdmissionCheckPluginStatusApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("AutomaticRollback"):
+		return &kueueoperatorv1alpha1.AutomaticRollbackApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("Canary"):
+		return &kueueoperatorv1alpha1.CanaryApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("CanaryStatus"):
+		return &kueueoperatorv1alpha1.CanaryStatusApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("ConfigRevision"):
+		return &kueueoperatorv1alpha1.ConfigRevisionApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("DiscoveredFlavor"):
+		return &kueueoperatorv1alpha1.DiscoveredFlavorApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("FlavorCapacity"):
+		return &kueueoperatorv1alpha1.FlavorCapacityApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("FlavorDiscovery"):
+		return &kueueoperatorv1alpha1.FlavorDiscoveryApplyConfiguration{}
 	case v1alpha1.SchemeGroupVersion.WithKind("Kueue"):
 		return &kueueoperatorv1alpha1.KueueApplyConfiguration{}
 	case v1alpha1.SchemeGroupVersion.WithKind("KueueConfiguration"):
 		return &kueueoperatorv1alpha1.KueueConfigurationApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("KueueDefaults"):
+		return &kueueoperatorv1alpha1.KueueDefaultsApplyConfiguration{}
 	case v1alpha1.SchemeGroupVersion.WithKind("KueueOperandSpec"):
 		return &kueueoperatorv1alpha1.KueueOperandSpecApplyConfiguration{}
 	case v1alpha1.SchemeGroupVersion.WithKind("KueueStatus"):
 		return &kueueoperatorv1alpha1.KueueStatusApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("LocalQueueMapping"):
+		return &kueueoperatorv1alpha1.LocalQueueMappingApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("LocalQueueProvisioning"):
+		return &kueueoperatorv1alpha1.LocalQueueProvisioningApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("ManagerStatus"):
+		return &kueueoperatorv1alpha1.ManagerStatusApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("MultiKueue"):
+		return &kueueoperatorv1alpha1.MultiKueueApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("MultiKueueWorker"):
+		return &kueueoperatorv1alpha1.MultiKueueWorkerApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("MultiKueueWorkerStatus"):
+		return &kueueoperatorv1alpha1.MultiKueueWorkerStatusApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("PinnedConfig"):
+		return &kueueoperatorv1alpha1.PinnedConfigApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("ProvisioningRequest"):
+		return &kueueoperatorv1alpha1.ProvisioningRequestApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("QueueAccess"):
+		return &kueueoperatorv1alpha1.QueueAccessApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("QueueAccessStatus"):
+		return &kueueoperatorv1alpha1.QueueAccessStatusApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("QuotaManagement"):
+		return &kueueoperatorv1alpha1.QuotaManagementApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("QuotaRecommendation"):
+		return &kueueoperatorv1alpha1.QuotaRecommendationApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("ResourceStatus"):
+		return &kueueoperatorv1alpha1.ResourceStatusApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("RolloutRevision"):
+		return &kueueoperatorv1alpha1.RolloutRevisionApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("RolloutStatus"):
+		return &kueueoperatorv1alpha1.RolloutStatusApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("TopologyAwareScheduling"):
+		return &kueueoperatorv1alpha1.TopologyAwareSchedulingApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("TopologyDefinition"):
+		return &kueueoperatorv1alpha1.TopologyDefinitionApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("TopologyStatus"):
+		return &kueueoperatorv1alpha1.TopologyStatusApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("WorkloadPriorities"):
+		return &kueueoperatorv1alpha1.WorkloadPrioritiesApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("WorkloadPriorityClassStatus"):
+		return &kueueoperatorv1alpha1.WorkloadPriorityClassStatusApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("WorkloadPriorityTier"):
+		return &kueueoperatorv1alpha1.WorkloadPriorityTierApplyConfiguration{}
 
 	}
 	return nil
@@ -1,16 +1,27 @@
 package namespace
 
-import "os"
+import (
+	"os"
+	"strings"
+)
 
 const (
+	watchNamespaceEnv = "WATCH_NAMESPACE"
 	podNamespaceEnv   = "POD_NAMESPACE"
 	operatorNamespace = "openshift-kueue-operator"
 )
 
-// getNamespace returns in-cluster namespace
+// GetNamespace returns the namespace of the Kueue CR and of the Kueue manager. When OLM
+// installs the operator for a single namespace, in the OwnNamespace or SingleNamespace
+// install mode, it is that namespace. Otherwise it is the namespace the operator runs in.
 func GetNamespace() string {
+	if watchNamespace := strings.TrimSpace(os.Getenv(watchNamespaceEnv)); len(watchNamespace) > 0 && !strings.Contains(watchNamespace, ",") {
+		return watchNamespace
+	}
 	if nsBytes, err := os.ReadFile("/var/run/secrets/kubernetes.io/serviceaccount/namespace"); err == nil {
-		return string(nsBytes)
+		if ns := strings.TrimSpace(string(nsBytes)); len(ns) > 0 {
+			return ns
+		}
 	}
 	if podNamespace := os.Getenv(podNamespaceEnv); len(podNamespace) > 0 {
 		return podNamespace
@@ -0,0 +1,366 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/kueue-operator/pkg/admissioncheck"
+	kueuev1alpha1 "github.com/openshift/kueue-operator/pkg/apis/kueueoperator/v1alpha1"
+	"github.com/openshift/kueue-operator/pkg/builders/queues"
+	kueueconfigclient "github.com/openshift/kueue-operator/pkg/generated/clientset/versioned/typed/kueueoperator/v1alpha1"
+	operatorclientinformers "github.com/openshift/kueue-operator/pkg/generated/informers/externalversions/kueueoperator/v1alpha1"
+	"github.com/openshift/kueue-operator/pkg/namespace"
+	"github.com/openshift/kueue-operator/pkg/operator/operatorclient"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+	kueuev1beta1 "sigs.k8s.io/kueue/apis/kueue/v1beta1"
+)
+
+const (
+	// admissionCheckLabel marks the AdmissionChecks created for spec.admissionChecks. Only
+	// these are updated and deleted by the controller.
+	admissionCheckLabel = "kueue.openshift.io/admission-check"
+
+	// Plugins that answer Pending are asked again after this interval.
+	admissionCheckPollInterval = 30 * time.Second
+
+	workloadResyncInterval = 10 * time.Minute
+)
+
+// AdmissionCheckController runs the admission check plugins of spec.admissionChecks of
+// the Kueue CR. It creates an AdmissionCheck for every entry, keeps its Active condition
+// in line with the plugin, and sets the state of the checks of workloads that have
+// quota reserved to the decision of the plugin.
+//
+// The queue holds factory.DefaultQueueKey to reconcile the AdmissionChecks and
+// namespace/name keys of workloads to decide.
+type AdmissionCheckController struct {
+	ctx               context.Context
+	operatorClient    kueueconfigclient.KueueV1alpha1Interface
+	dynamicClient     dynamic.Interface
+	workloadLister    cache.GenericLister
+	eventRecorder     events.Recorder
+	queue             workqueue.RateLimitingInterface
+	operatorNamespace string
+
+	// pluginsLock guards plugins, the plugins of the AdmissionChecks that are active.
+	pluginsLock sync.RWMutex
+	plugins     map[string]admissioncheck.Plugin
+}
+
+func NewAdmissionCheckController(
+	ctx context.Context,
+	operatorConfigClient kueueconfigclient.KueueV1alpha1Interface,
+	operatorClientInformer operatorclientinformers.KueueInformer,
+	dynamicClient dynamic.Interface,
+	eventRecorder events.Recorder,
+) (factory.Controller, error) {
+	workloadInformer := dynamicinformer.NewDynamicSharedInformerFactory(dynamicClient, workloadResyncInterval)
+	workloads := workloadInformer.ForResource(queues.WorkloadsGVR)
+	syncCtx := factory.NewSyncContext("AdmissionCheckController", eventRecorder)
+	c := &AdmissionCheckController{
+		ctx:               ctx,
+		operatorClient:    operatorConfigClient,
+		dynamicClient:     dynamicClient,
+		workloadLister:    workloads.Lister(),
+		eventRecorder:     eventRecorder.WithComponentSuffix("admission-check-controller"),
+		queue:             syncCtx.Queue(),
+		operatorNamespace: namespace.GetNamespace(),
+		plugins:           map[string]admissioncheck.Plugin{},
+	}
+
+	if _, err := operatorClientInformer.Informer().AddEventHandler(queueHandler(syncCtx, kueueSpecChanged)); err != nil {
+		return nil, err
+	}
+	if _, err := workloads.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    c.enqueueWorkload,
+		UpdateFunc: func(old, new interface{}) { c.enqueueWorkload(new) },
+	}); err != nil {
+		return nil, err
+	}
+
+	return factory.New().
+		WithSyncContext(syncCtx).
+		WithBareInformers(operatorClientInformer.Informer()).
+		WithPostStartHooks(startDynamicInformers(workloadInformer)).
+		WithSync(func(ctx context.Context, syncCtx factory.SyncContext) error { return c.sync(syncCtx.QueueKey()) }).
+		// Restore AdmissionChecks that were edited or deleted, nothing watches them.
+		ResyncEvery(workloadResyncInterval).
+		ToController("AdmissionCheckController", c.eventRecorder), nil
+}
+
+func (c *AdmissionCheckController) sync(key string) error {
+	if key == factory.DefaultQueueKey {
+		return c.syncAdmissionChecks()
+	}
+	return c.syncWorkload(key)
+}
+
+func (c *AdmissionCheckController) syncAdmissionChecks() error {
+	kueue, err := c.operatorClient.Kueues(c.operatorNamespace).Get(c.ctx, operatorclient.OperatorConfigName, metav1.GetOptions{})
+	if err != nil {
+		klog.ErrorS(err, "unable to get operator configuration", "namespace", c.operatorNamespace, "kueue", operatorclient.OperatorConfigName)
+		return err
+	}
+
+	var errs []error
+	plugins := make(map[string]admissioncheck.Plugin, len(kueue.Spec.AdmissionChecks))
+	statuses := make([]kueuev1alpha1.AdmissionCheckPluginStatus, 0, len(kueue.Spec.AdmissionChecks))
+	wanted := sets.New[string]()
+	for _, entry := range kueue.Spec.AdmissionChecks {
+		wanted.Insert(entry.Name)
+		status := kueuev1alpha1.AdmissionCheckPluginStatus{Name: entry.Name}
+		plugin, pluginErr := newAdmissionCheckPlugin(entry)
+		if pluginErr != nil {
+			status.Message = pluginErr.Error()
+		}
+		if err := c.applyAdmissionCheck(entry.Name, pluginErr); err != nil {
+			errs = append(errs, err)
+			status.Message = err.Error()
+		} else if pluginErr == nil {
+			status.Active = true
+			plugins[entry.Name] = plugin
+		}
+		statuses = append(statuses, status)
+	}
+	c.setPlugins(plugins)
+
+	existing, err := c.dynamicClient.Resource(queues.AdmissionChecksGVR).List(c.ctx, metav1.ListOptions{LabelSelector: admissionCheckLabel})
+	if err != nil {
+		errs = append(errs, err)
+	} else {
+		for _, check := range existing.Items {
+			if wanted.Has(check.GetName()) {
+				continue
+			}
+			if err := c.dynamicClient.Resource(queues.AdmissionChecksGVR).Delete(c.ctx, check.GetName(), metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+				errs = append(errs, fmt.Errorf("unable to delete AdmissionCheck %s: %w", check.GetName(), err))
+				continue
+			}
+			c.eventRecorder.Eventf("AdmissionCheckDeleted", "Deleted AdmissionCheck %s", check.GetName())
+		}
+	}
+
+	degraded := operatorv1.OperatorCondition{
+		Type:   "AdmissionCheckControllerDegraded",
+		Status: operatorv1.ConditionFalse,
+		Reason: "AsExpected",
+	}
+	if len(errs) > 0 {
+		degraded.Status = operatorv1.ConditionTrue
+		degraded.Reason = "SyncFailed"
+		degraded.Message = utilerrors.NewAggregate(errs).Error()
+	}
+	if err := updateKueueStatus(c.ctx, c.operatorClient, c.operatorNamespace, kueue.Name, func(status *kueuev1alpha1.KueueStatus) {
+		status.AdmissionChecks = statuses
+		if len(statuses) == 0 {
+			status.AdmissionChecks = nil
+		}
+		v1helpers.SetOperatorCondition(&status.Conditions, degraded)
+	}); err != nil {
+		errs = append(errs, err)
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+func newAdmissionCheckPlugin(entry kueuev1alpha1.AdmissionCheckPlugin) (admissioncheck.Plugin, error) {
+	if entry.HTTP != nil {
+		var timeout time.Duration
+		if entry.HTTP.Timeout != nil {
+			timeout = entry.HTTP.Timeout.Duration
+		}
+		return admissioncheck.NewHTTP(entry.Name, entry.HTTP.URL, timeout, entry.Parameters)
+	}
+	return admissioncheck.New(entry.Plugin, entry.Parameters)
+}
+
+// setPlugins replaces the plugins and queues the workloads again when they changed, so
+// that checks waiting for a plugin are decided.
+func (c *AdmissionCheckController) setPlugins(plugins map[string]admissioncheck.Plugin) {
+	c.pluginsLock.Lock()
+	changed := !sets.KeySet(c.plugins).Equal(sets.KeySet(plugins))
+	c.plugins = plugins
+	c.pluginsLock.Unlock()
+	if !changed {
+		return
+	}
+	workloads, err := c.workloadLister.List(labels.Everything())
+	if err != nil {
+		klog.ErrorS(err, "unable to list workloads")
+		return
+	}
+	for _, workload := range workloads {
+		c.enqueueWorkload(workload)
+	}
+}
+
+func (c *AdmissionCheckController) plugin(name string) (admissioncheck.Plugin, bool) {
+	c.pluginsLock.RLock()
+	defer c.pluginsLock.RUnlock()
+	plugin, ok := c.plugins[name]
+	return plugin, ok
+}
+
+// applyAdmissionCheck creates the AdmissionCheck and sets its Active condition, which
+// Kueue requires before it admits workloads of ClusterQueues using the check.
+func (c *AdmissionCheckController) applyAdmissionCheck(name string, pluginErr error) error {
+	required := admissioncheck.BuildAdmissionCheck(name)
+	required.Labels = map[string]string{admissionCheckLabel: "true"}
+	client := c.dynamicClient.Resource(queues.AdmissionChecksGVR)
+	existing, err := client.Get(c.ctx, name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		if err := createIfMissing(c.ctx, c.dynamicClient, c.eventRecorder, queues.AdmissionChecksGVR, required); err != nil {
+			return err
+		}
+		existing, err = client.Get(c.ctx, name, metav1.GetOptions{})
+	}
+	if err != nil {
+		return err
+	}
+	if _, ok := existing.GetLabels()[admissionCheckLabel]; !ok {
+		return fmt.Errorf("AdmissionCheck %s exists and is not managed by the operator", name)
+	}
+	if controllerName, _, _ := unstructured.NestedString(existing.Object, "spec", "controllerName"); controllerName != admissioncheck.ControllerName {
+		// The controllerName of an AdmissionCheck is immutable.
+		if err := client.Delete(c.ctx, name, metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		return c.applyAdmissionCheck(name, pluginErr)
+	}
+
+	status := kueuev1beta1.AdmissionCheckStatus{}
+	content, _, _ := unstructured.NestedMap(existing.Object, "status")
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(content, &status); err != nil {
+		return err
+	}
+	active := metav1.Condition{
+		Type:    kueuev1beta1.AdmissionCheckActive,
+		Status:  metav1.ConditionTrue,
+		Reason:  "Active",
+		Message: "The plugin of the check runs in the Kueue operator",
+	}
+	if pluginErr != nil {
+		active.Status = metav1.ConditionFalse
+		active.Reason = "PluginUnavailable"
+		active.Message = pluginErr.Error()
+	}
+	updatedStatus := status.DeepCopy()
+	meta.SetStatusCondition(&updatedStatus.Conditions, active)
+	if equality.Semantic.DeepEqual(status, *updatedStatus) {
+		return nil
+	}
+	content, err = runtime.DefaultUnstructuredConverter.ToUnstructured(updatedStatus)
+	if err != nil {
+		return err
+	}
+	updated := existing.DeepCopy()
+	updated.Object["status"] = content
+	if _, err := client.UpdateStatus(c.ctx, updated, metav1.UpdateOptions{FieldManager: fieldManager}); err != nil {
+		return fmt.Errorf("unable to update the status of AdmissionCheck %s: %w", name, err)
+	}
+	return nil
+}
+
+// syncWorkload decides the Pending checks of a workload that have a plugin. Kueue only
+// waits for checks once the workload has quota reserved.
+func (c *AdmissionCheckController) syncWorkload(key string) error {
+	ns, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return err
+	}
+	obj, err := c.workloadLister.ByNamespace(ns).Get(name)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	existing, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return fmt.Errorf("unexpected object %T for workload %s", obj, key)
+	}
+	workload := &kueuev1beta1.Workload{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(existing.Object, workload); err != nil {
+		return err
+	}
+	if !meta.IsStatusConditionTrue(workload.Status.Conditions, kueuev1beta1.WorkloadQuotaReserved) ||
+		meta.IsStatusConditionTrue(workload.Status.Conditions, kueuev1beta1.WorkloadFinished) {
+		return nil
+	}
+
+	// The checks are edited in place so that fields unknown to the vendored API are kept.
+	updated := existing.DeepCopy()
+	checks, _, _ := unstructured.NestedSlice(updated.Object, "status", "admissionChecks")
+	var errs []error
+	changed, pending := false, false
+	for i, check := range workload.Status.AdmissionChecks {
+		plugin, ok := c.plugin(check.Name)
+		if !ok || check.State != kueuev1beta1.CheckStatePending {
+			continue
+		}
+		result, err := plugin.Check(c.ctx, workload)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("admission check %s of workload %s: %w", check.Name, key, err))
+			result = admissioncheck.Result{State: kueuev1beta1.CheckStatePending, Message: err.Error()}
+		}
+		if result.State == kueuev1beta1.CheckStatePending {
+			pending = true
+		}
+		if result.State == check.State && result.Message == check.Message {
+			continue
+		}
+		entry, ok := checks[i].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		entry["state"] = string(result.State)
+		entry["message"] = result.Message
+		if result.State != check.State {
+			entry["lastTransitionTime"] = metav1.Now().UTC().Format(time.RFC3339)
+			klog.V(2).InfoS("Decided admission check", "workload", key, "admissionCheck", check.Name, "state", result.State, "message", result.Message)
+		}
+		changed = true
+	}
+
+	if changed {
+		if err := unstructured.SetNestedSlice(updated.Object, checks, "status", "admissionChecks"); err != nil {
+			return err
+		}
+		if _, err := c.dynamicClient.Resource(queues.WorkloadsGVR).Namespace(ns).UpdateStatus(c.ctx, updated, metav1.UpdateOptions{FieldManager: fieldManager}); err != nil {
+			errs = append(errs, fmt.Errorf("unable to update the admission checks of workload %s: %w", key, err))
+		}
+	}
+	if pending && len(errs) == 0 {
+		c.queue.AddAfter(key, admissionCheckPollInterval)
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+func (c *AdmissionCheckController) enqueueWorkload(obj interface{}) {
+	key, err := cache.MetaNamespaceKeyFunc(obj)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	c.queue.Add(key)
+}
@@ -0,0 +1,167 @@
+package operator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	kueuev1beta1 "sigs.k8s.io/kueue/apis/kueue/v1beta1"
+
+	"github.com/openshift/kueue-operator/pkg/admissioncheck"
+	kueuev1alpha1 "github.com/openshift/kueue-operator/pkg/apis/kueueoperator/v1alpha1"
+	"github.com/openshift/kueue-operator/pkg/builders/queues"
+	"github.com/openshift/kueue-operator/pkg/namespace"
+	"github.com/openshift/kueue-operator/pkg/operator/operatorclient"
+)
+
+func newTestAdmissionCheckController(t *testing.T, kueue *kueuev1alpha1.Kueue) (*AdmissionCheckController, cache.Indexer) {
+	t.Helper()
+	defaults, _ := newTestDefaultsController(t, kueue)
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		queues.AdmissionChecksGVR: "AdmissionCheckList",
+		queues.WorkloadsGVR:       "WorkloadList",
+	})
+	return &AdmissionCheckController{
+		ctx:               defaults.ctx,
+		operatorClient:    defaults.operatorClient,
+		dynamicClient:     dynamicClient,
+		workloadLister:    cache.NewGenericLister(indexer, queues.WorkloadsGVR.GroupResource()),
+		eventRecorder:     defaults.eventRecorder,
+		queue:             workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "test"),
+		operatorNamespace: defaults.operatorNamespace,
+		plugins:           map[string]admissioncheck.Plugin{},
+	}, indexer
+}
+
+// addWorkload creates a workload with quota reserved and a Pending check, and adds it
+// to the lister.
+func (c *AdmissionCheckController) addWorkload(t *testing.T, indexer cache.Indexer, name, approved string, checks ...string) {
+	t.Helper()
+	workload := &kueuev1beta1.Workload{
+		TypeMeta:   metav1.TypeMeta{APIVersion: kueuev1beta1.GroupVersion.String(), Kind: "Workload"},
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "team-a", Annotations: map[string]string{admissioncheck.DefaultApprovalAnnotation: approved}},
+		Status: kueuev1beta1.WorkloadStatus{
+			Conditions: []metav1.Condition{{Type: kueuev1beta1.WorkloadQuotaReserved, Status: metav1.ConditionTrue, Reason: "QuotaReserved", LastTransitionTime: metav1.Now()}},
+		},
+	}
+	for _, check := range checks {
+		workload.Status.AdmissionChecks = append(workload.Status.AdmissionChecks, kueuev1beta1.AdmissionCheckState{
+			Name: check, State: kueuev1beta1.CheckStatePending, LastTransitionTime: metav1.Now(),
+		})
+	}
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(workload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	created, err := c.dynamicClient.Resource(queues.WorkloadsGVR).Namespace("team-a").Create(c.ctx, &unstructured.Unstructured{Object: content}, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := indexer.Add(created); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func (c *AdmissionCheckController) checkStates(t *testing.T, name string) []string {
+	t.Helper()
+	obj, err := c.dynamicClient.Resource(queues.WorkloadsGVR).Namespace("team-a").Get(c.ctx, name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	workload := &kueuev1beta1.Workload{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, workload); err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, check := range workload.Status.AdmissionChecks {
+		got = append(got, check.Name+" "+string(check.State))
+	}
+	return got
+}
+
+func TestAdmissionCheckController(t *testing.T) {
+	kueue := newTestKueue()
+	kueue.Spec.AdmissionChecks = []kueuev1alpha1.AdmissionCheckPlugin{
+		{Name: "approval", Plugin: admissioncheck.ApprovalPluginName},
+		{Name: "missing", Plugin: "Missing"},
+		{Name: "taken", Plugin: admissioncheck.ApprovalPluginName},
+	}
+	c, indexer := newTestAdmissionCheckController(t, kueue)
+	// A check created by someone else is not taken over.
+	if err := createIfMissing(c.ctx, c.dynamicClient, c.eventRecorder, queues.AdmissionChecksGVR, admissioncheck.BuildAdmissionCheck("taken")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.sync(factory.DefaultQueueKey); err == nil || !strings.Contains(err.Error(), "taken exists and is not managed") {
+		t.Fatalf("Expected an error about the unmanaged check, got %v", err)
+	}
+	kueue, err := c.operatorClient.Kueues(namespace.GetNamespace()).Get(c.ctx, operatorclient.OperatorConfigName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]kueuev1alpha1.AdmissionCheckPluginStatus{
+		{Name: "approval", Active: true},
+		{Name: "missing", Message: `unknown admission check plugin "Missing", registered plugins are [Approval]`},
+		{Name: "taken", Message: "AdmissionCheck taken exists and is not managed by the operator"},
+	}, kueue.Status.AdmissionChecks); len(diff) != 0 {
+		t.Errorf("Unexpected status.admissionChecks (-want,+got):\n%s", diff)
+	}
+	for name, want := range map[string]bool{"approval": true, "missing": false} {
+		obj, err := c.dynamicClient.Resource(queues.AdmissionChecksGVR).Get(c.ctx, name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		check := &kueuev1beta1.AdmissionCheck{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, check); err != nil {
+			t.Fatal(err)
+		}
+		if got := meta.IsStatusConditionTrue(check.Status.Conditions, kueuev1beta1.AdmissionCheckActive); got != want {
+			t.Errorf("Expected AdmissionCheck %s to be active=%t, got %t", name, want, got)
+		}
+	}
+
+	// Only the checks with an active plugin are decided.
+	c.addWorkload(t, indexer, "approved", "true", "approval", "missing", "other")
+	c.addWorkload(t, indexer, "waiting", "", "approval")
+	for _, name := range []string{"approved", "waiting"} {
+		if err := c.sync("team-a/" + name); err != nil {
+			t.Fatalf("Unexpected sync error: %v", err)
+		}
+	}
+	if diff := cmp.Diff([]string{"approval Ready", "missing Pending", "other Pending"}, c.checkStates(t, "approved")); len(diff) != 0 {
+		t.Errorf("Unexpected checks of the approved workload (-want,+got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"approval Pending"}, c.checkStates(t, "waiting")); len(diff) != 0 {
+		t.Errorf("Unexpected checks of the waiting workload (-want,+got):\n%s", diff)
+	}
+
+	// Removed entries are pruned, checks of others are kept.
+	kueue.Spec.AdmissionChecks = nil
+	if _, err := c.operatorClient.Kueues(namespace.GetNamespace()).Update(c.ctx, kueue, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.sync(factory.DefaultQueueKey); err != nil {
+		t.Fatalf("Unexpected sync error: %v", err)
+	}
+	list, err := c.dynamicClient.Resource(queues.AdmissionChecksGVR).List(c.ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, item := range list.Items {
+		names = append(names, item.GetName())
+	}
+	if diff := cmp.Diff([]string{"taken"}, names); len(diff) != 0 {
+		t.Errorf("Unexpected AdmissionChecks (-want,+got):\n%s", diff)
+	}
+}
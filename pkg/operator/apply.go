@@ -0,0 +1,138 @@
+package operator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsv1ac "k8s.io/apiextensions-apiserver/pkg/client/applyconfiguration/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	admissionregistrationv1ac "k8s.io/client-go/applyconfigurations/admissionregistration/v1"
+	appsv1ac "k8s.io/client-go/applyconfigurations/apps/v1"
+	corev1ac "k8s.io/client-go/applyconfigurations/core/v1"
+	rbacv1ac "k8s.io/client-go/applyconfigurations/rbac/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/klog/v2"
+)
+
+// fieldManager is the server-side apply field manager of every operand object. It must
+// not change between releases, otherwise fields the operator stops setting are not
+// released and fields it keeps setting conflict with the previous manager.
+const fieldManager = "kueue-operator"
+
+var operandScheme = runtime.NewScheme()
+
+func init() {
+	utilruntime.Must(scheme.AddToScheme(operandScheme))
+	utilruntime.Must(apiextensionsv1.AddToScheme(operandScheme))
+}
+
+// applier is implemented by the typed clients of every operand kind, A being the
+// applyconfiguration of the kind.
+type applier[A any, T metav1.Object] interface {
+	Apply(ctx context.Context, config *A, opts metav1.ApplyOptions) (T, error)
+}
+
+// applyObject server-side applies a required object. Only the fields set in required are
+// owned by the operator, so fields written by other controllers, such as the CA bundles
+// injected by the service-ca operator or labels added by OLM, are left alone.
+//
+// When a field is owned by another manager, for example after an `oc edit`, the conflict is
+// returned and the apply is forced so that the operator stays authoritative for its fields.
+func (c *TargetConfigReconciler) applyObject(required runtime.Object) (metav1.Object, string, error) {
+	accessor, err := meta.Accessor(required)
+	if err != nil {
+		return nil, "", err
+	}
+
+	name, namespace := accessor.GetName(), accessor.GetNamespace()
+	switch required.(type) {
+	case *v1.ConfigMap:
+		return serverSideApply[corev1ac.ConfigMapApplyConfiguration](c, c.kubeClient.CoreV1().ConfigMaps(namespace), required, name)
+	case *v1.ServiceAccount:
+		return serverSideApply[corev1ac.ServiceAccountApplyConfiguration](c, c.kubeClient.CoreV1().ServiceAccounts(namespace), required, name)
+	case *v1.Secret:
+		return serverSideApply[corev1ac.SecretApplyConfiguration](c, c.kubeClient.CoreV1().Secrets(namespace), required, name)
+	case *v1.Service:
+		return serverSideApply[corev1ac.ServiceApplyConfiguration](c, c.kubeClient.CoreV1().Services(namespace), required, name)
+	case *rbacv1.Role:
+		return serverSideApply[rbacv1ac.RoleApplyConfiguration](c, c.kubeClient.RbacV1().Roles(namespace), required, name)
+	case *rbacv1.RoleBinding:
+		return serverSideApply[rbacv1ac.RoleBindingApplyConfiguration](c, c.kubeClient.RbacV1().RoleBindings(namespace), required, name)
+	case *rbacv1.ClusterRole:
+		return serverSideApply[rbacv1ac.ClusterRoleApplyConfiguration](c, c.kubeClient.RbacV1().ClusterRoles(), required, name)
+	case *rbacv1.ClusterRoleBinding:
+		return serverSideApply[rbacv1ac.ClusterRoleBindingApplyConfiguration](c, c.kubeClient.RbacV1().ClusterRoleBindings(), required, name)
+	case *apiextensionsv1.CustomResourceDefinition:
+		return serverSideApply[apiextensionsv1ac.CustomResourceDefinitionApplyConfiguration](c, c.crdClient.CustomResourceDefinitions(), required, name)
+	case *appsv1.Deployment:
+		return serverSideApply[appsv1ac.DeploymentApplyConfiguration](c, c.kubeClient.AppsV1().Deployments(namespace), required, name)
+	case *admissionregistrationv1.MutatingWebhookConfiguration:
+		return serverSideApply[admissionregistrationv1ac.MutatingWebhookConfigurationApplyConfiguration](c, c.kubeClient.AdmissionregistrationV1().MutatingWebhookConfigurations(), required, name)
+	case *admissionregistrationv1.ValidatingWebhookConfiguration:
+		return serverSideApply[admissionregistrationv1ac.ValidatingWebhookConfigurationApplyConfiguration](c, c.kubeClient.AdmissionregistrationV1().ValidatingWebhookConfigurations(), required, name)
+	default:
+		return nil, "", fmt.Errorf("unsupported object type %T", required)
+	}
+}
+
+func serverSideApply[A any, T metav1.Object](c *TargetConfigReconciler, client applier[A, T], required runtime.Object, name string) (metav1.Object, string, error) {
+	config, err := applyConfiguration[A](required)
+	if err != nil {
+		return nil, "", err
+	}
+	applied, err := client.Apply(c.ctx, config, metav1.ApplyOptions{FieldManager: fieldManager})
+	if err == nil {
+		return applied, "", nil
+	}
+	if !errors.IsConflict(err) {
+		return nil, "", err
+	}
+
+	conflict := err.Error()
+	kind := required.GetObjectKind().GroupVersionKind().Kind
+	klog.InfoS("Forcing ownership of conflicting fields", "kind", kind, "name", name, "conflict", conflict)
+	c.eventRecorder.Warningf(kind+"ApplyConflict", "Forced ownership of fields of %s %s: %s", kind, name, conflict)
+	applied, err = client.Apply(c.ctx, config, metav1.ApplyOptions{FieldManager: fieldManager, Force: true})
+	if err != nil {
+		return nil, conflict, err
+	}
+	return applied, conflict, nil
+}
+
+// applyConfiguration converts required into the applyconfiguration A of its kind. Objects
+// read from bindata lose their TypeMeta when decoded, so it is restored from the scheme.
+//
+// Every field of an applyconfiguration is optional, so unset fields, such as
+// creationTimestamp: null, are not part of the patch, while empty structs set in the
+// manifests, such as emptyDir: {}, are. The status is left to the operands.
+func applyConfiguration[A any](required runtime.Object) (*A, error) {
+	gvks, _, err := operandScheme.ObjectKinds(required)
+	if err != nil {
+		return nil, err
+	}
+	required.GetObjectKind().SetGroupVersionKind(gvks[0])
+	fields, err := runtime.DefaultUnstructuredConverter.ToUnstructured(required)
+	if err != nil {
+		return nil, err
+	}
+	delete(fields, "status")
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+	config := new(A)
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
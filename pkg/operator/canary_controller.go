@@ -0,0 +1,278 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	kueuev1alpha1 "github.com/openshift/kueue-operator/pkg/apis/kueueoperator/v1alpha1"
+	kueueconfigclient "github.com/openshift/kueue-operator/pkg/generated/clientset/versioned/typed/kueueoperator/v1alpha1"
+	operatorclientinformers "github.com/openshift/kueue-operator/pkg/generated/informers/externalversions/kueueoperator/v1alpha1"
+	"github.com/openshift/kueue-operator/pkg/namespace"
+	"github.com/openshift/kueue-operator/pkg/operator/operatorclient"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	appsv1listers "k8s.io/client-go/listers/apps/v1"
+	coordinationv1listers "k8s.io/client-go/listers/coordination/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+	configapi "sigs.k8s.io/kueue/apis/config/v1beta1"
+)
+
+// deleteCanary deletes the objects of a canary that was removed or aborted. The
+// canary webhooks go first, so that no call is routed to a Deployment that is going away.
+func (c *TargetConfigReconciler) deleteCanary() error {
+	canaryObjects := []struct {
+		obj    metav1.Object
+		delete func(ctx context.Context, name string, opts metav1.DeleteOptions) error
+	}{
+		{
+			obj:    &admissionregistrationv1.MutatingWebhookConfiguration{ObjectMeta: metav1.ObjectMeta{Name: canaryMutatingWebhookName}},
+			delete: c.kubeClient.AdmissionregistrationV1().MutatingWebhookConfigurations().Delete,
+		},
+		{
+			obj:    &admissionregistrationv1.ValidatingWebhookConfiguration{ObjectMeta: metav1.ObjectMeta{Name: canaryValidatingWebhookName}},
+			delete: c.kubeClient.AdmissionregistrationV1().ValidatingWebhookConfigurations().Delete,
+		},
+		{
+			obj:    &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: canaryDeploymentName, Namespace: c.operatorNamespace}},
+			delete: c.kubeClient.AppsV1().Deployments(c.operatorNamespace).Delete,
+		},
+		{
+			obj:    &v1.Service{ObjectMeta: metav1.ObjectMeta{Name: canaryWebhookServiceName, Namespace: c.operatorNamespace}},
+			delete: c.kubeClient.CoreV1().Services(c.operatorNamespace).Delete,
+		},
+		{
+			obj:    &v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: canaryConfigMapName, Namespace: c.operatorNamespace}},
+			delete: c.kubeClient.CoreV1().ConfigMaps(c.operatorNamespace).Delete,
+		},
+	}
+	var errs []error
+	for _, canary := range canaryObjects {
+		// Our own deletions are not drift.
+		c.appliedVersions.recordDeletion(canary.obj)
+		err := canary.delete(c.ctx, canary.obj.GetName(), metav1.DeleteOptions{})
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("unable to delete canary %s: %w", resourceName(canary.obj.(runtime.Object)), err))
+			continue
+		}
+		c.eventRecorder.Eventf("CanaryObjectDeleted", "Deleted %s %s of the removed canary", resourceName(canary.obj.(runtime.Object)), objectName(canary.obj))
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// CanaryController runs spec.canary next to the TargetConfigReconciler, which applies the
+// canary Deployment and webhooks. It records the outcome of spec.canary.action and
+// compares the canary manager with the stable one in status.canary. Both managers share a
+// leader election lease, and it reports the canary holding it, as the canary then runs
+// the scheduler for every namespace.
+type CanaryController struct {
+	ctx               context.Context
+	operatorClient    kueueconfigclient.KueueV1alpha1Interface
+	deploymentLister  appsv1listers.DeploymentLister
+	podLister         corev1listers.PodLister
+	leaseLister       coordinationv1listers.LeaseLister
+	namespaceLister   corev1listers.NamespaceLister
+	eventRecorder     events.Recorder
+	operatorNamespace string
+}
+
+func NewCanaryController(
+	ctx context.Context,
+	operatorConfigClient kueueconfigclient.KueueV1alpha1Interface,
+	operatorClientInformer operatorclientinformers.KueueInformer,
+	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
+	eventRecorder events.Recorder,
+) (factory.Controller, error) {
+	operandInformers := kubeInformersForNamespaces.InformersFor(namespace.GetNamespace())
+	clusterInformers := kubeInformersForNamespaces.InformersFor("")
+	c := &CanaryController{
+		ctx:               ctx,
+		operatorClient:    operatorConfigClient,
+		deploymentLister:  operandInformers.Apps().V1().Deployments().Lister(),
+		podLister:         operandInformers.Core().V1().Pods().Lister(),
+		leaseLister:       operandInformers.Coordination().V1().Leases().Lister(),
+		namespaceLister:   clusterInformers.Core().V1().Namespaces().Lister(),
+		eventRecorder:     eventRecorder.WithComponentSuffix("canary-controller"),
+		operatorNamespace: namespace.GetNamespace(),
+	}
+
+	syncCtx := factory.NewSyncContext("CanaryController", eventRecorder)
+	if _, err := operatorClientInformer.Informer().AddEventHandler(queueHandler(syncCtx, kueueSpecChanged)); err != nil {
+		return nil, err
+	}
+
+	return factory.New().
+		WithSyncContext(syncCtx).
+		WithFilteredEventsInformers(func(obj interface{}) bool {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			deployment, ok := obj.(*appsv1.Deployment)
+			return ok && (deployment.Name == operatorclient.OperandName || deployment.Name == canaryDeploymentName)
+		}, operandInformers.Apps().V1().Deployments().Informer()).
+		// Container restarts are only reported on the pods.
+		WithFilteredEventsInformers(func(obj interface{}) bool {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			pod, ok := obj.(*v1.Pod)
+			return ok && (pod.Labels[controlPlaneLabel] == stableControlPlane || pod.Labels[controlPlaneLabel] == canaryControlPlane)
+		}, operandInformers.Core().V1().Pods().Informer()).
+		WithFilteredEventsInformers(func(obj interface{}) bool {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			lease, ok := obj.(*coordinationv1.Lease)
+			return ok && lease.Name == configapi.DefaultLeaderElectionID
+		}, operandInformers.Coordination().V1().Leases().Informer()).
+		WithBareInformers(operatorClientInformer.Informer(), clusterInformers.Core().V1().Namespaces().Informer()).
+		WithSync(func(ctx context.Context, _ factory.SyncContext) error { return c.sync() }).
+		ToController("CanaryController", c.eventRecorder), nil
+}
+
+func (c *CanaryController) sync() error {
+	kueue, err := c.operatorClient.Kueues(c.operatorNamespace).Get(c.ctx, operatorclient.OperatorConfigName, metav1.GetOptions{})
+	if err != nil {
+		klog.ErrorS(err, "unable to get operator configuration", "namespace", c.operatorNamespace, "kueue", operatorclient.OperatorConfigName)
+		return err
+	}
+
+	degraded := operatorv1.OperatorCondition{
+		Type:   "CanaryControllerDegraded",
+		Status: operatorv1.ConditionFalse,
+		Reason: "AsExpected",
+	}
+	canary := kueue.Spec.Canary
+	if canary == nil {
+		// A canary removed from the spec without an action is aborted.
+		return updateKueueStatus(c.ctx, c.operatorClient, c.operatorNamespace, kueue.Name, func(status *kueuev1alpha1.KueueStatus) {
+			if status.Canary != nil && status.Canary.State == kueuev1alpha1.CanaryStateRunning {
+				status.Canary.State = kueuev1alpha1.CanaryStateAborted
+				status.Canary.LastTransitionTime = metav1.Now()
+			}
+			v1helpers.SetOperatorCondition(&status.Conditions, degraded)
+		})
+	}
+
+	canaryStatus := &kueuev1alpha1.CanaryStatus{Image: canary.Image, State: kueuev1alpha1.CanaryStateRunning}
+	var errs []error
+	if canaryStatus.Stable, err = c.managerStatus(operatorclient.OperandName, stableControlPlane); err != nil {
+		errs = append(errs, err)
+	}
+	if canaryStatus.Canary, err = c.managerStatus(canaryDeploymentName, canaryControlPlane); err != nil {
+		errs = append(errs, err)
+	}
+	if selector, err := metav1.LabelSelectorAsSelector(&canary.NamespaceSelector); err != nil {
+		errs = append(errs, fmt.Errorf("spec.canary.namespaceSelector: %w", err))
+	} else if namespaces, err := c.namespaceLister.List(selector); err != nil {
+		errs = append(errs, err)
+	} else {
+		canaryStatus.Namespaces = int32(len(namespaces))
+	}
+	if err := c.checkLeader(); err != nil {
+		errs = append(errs, err)
+	}
+
+	switch canary.Action {
+	case kueuev1alpha1.CanaryActionPromote:
+		canaryStatus.State = kueuev1alpha1.CanaryStatePromoted
+	case kueuev1alpha1.CanaryActionAbort:
+		canaryStatus.State = kueuev1alpha1.CanaryStateAborted
+	}
+	// The spec is left to the user and their tooling, the outcome is only reported once.
+	if previous := kueue.Status.Canary; previous == nil || previous.State != canaryStatus.State || previous.Image != canaryStatus.Image {
+		switch canaryStatus.State {
+		case kueuev1alpha1.CanaryStatePromoted:
+			c.eventRecorder.Eventf("CanaryPromoted", "Promoted canary image %s, set spec.image to it and remove spec.canary", canary.Image)
+		case kueuev1alpha1.CanaryStateAborted:
+			c.eventRecorder.Warningf("CanaryAborted", "Aborted canary image %s, remove spec.canary", canary.Image)
+		}
+	}
+
+	if len(errs) > 0 {
+		degraded.Status = operatorv1.ConditionTrue
+		degraded.Reason = "SyncFailed"
+		degraded.Message = utilerrors.NewAggregate(errs).Error()
+	}
+	if err := c.updateCanaryStatus(kueue.Name, canaryStatus, degraded); err != nil {
+		errs = append(errs, err)
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// updateCanaryStatus writes status.canary, keeping the lastTransitionTime while the state
+// and image do not change.
+func (c *CanaryController) updateCanaryStatus(name string, canary *kueuev1alpha1.CanaryStatus, degraded operatorv1.OperatorCondition) error {
+	return updateKueueStatus(c.ctx, c.operatorClient, c.operatorNamespace, name, func(status *kueuev1alpha1.KueueStatus) {
+		updated := canary.DeepCopy()
+		if status.Canary != nil && status.Canary.State == canary.State && status.Canary.Image == canary.Image {
+			updated.LastTransitionTime = status.Canary.LastTransitionTime
+		} else {
+			updated.LastTransitionTime = metav1.Now()
+		}
+		status.Canary = updated
+		v1helpers.SetOperatorCondition(&status.Conditions, degraded)
+	})
+}
+
+// managerStatus reports the Deployment of a Kueue manager and the container restarts of
+// its pods. A Deployment that does not exist yet is reported empty.
+func (c *CanaryController) managerStatus(name, controlPlane string) (kueuev1alpha1.ManagerStatus, error) {
+	manager := kueuev1alpha1.ManagerStatus{}
+	deployment, err := c.deploymentLister.Deployments(c.operatorNamespace).Get(name)
+	if errors.IsNotFound(err) {
+		return manager, nil
+	}
+	if err != nil {
+		return manager, err
+	}
+	if len(deployment.Spec.Template.Spec.Containers) > 0 {
+		manager.Image = deployment.Spec.Template.Spec.Containers[0].Image
+	}
+	manager.Replicas = deployment.Status.Replicas
+	manager.AvailableReplicas = deployment.Status.AvailableReplicas
+
+	pods, err := c.podLister.Pods(c.operatorNamespace).List(labels.SelectorFromSet(labels.Set{controlPlaneLabel: controlPlane}))
+	if err != nil {
+		return manager, err
+	}
+	for _, pod := range pods {
+		for _, container := range pod.Status.ContainerStatuses {
+			manager.Restarts += container.RestartCount
+		}
+	}
+	return manager, nil
+}
+
+// checkLeader fails when the canary manager holds the leader election lease. It only
+// takes the lease while the stable manager is down, and keeps it until it restarts.
+func (c *CanaryController) checkLeader() error {
+	lease, err := c.leaseLister.Leases(c.operatorNamespace).Get(configapi.DefaultLeaderElectionID)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	// The holder identity starts with the name of the pod of the leader.
+	if lease.Spec.HolderIdentity != nil && strings.HasPrefix(*lease.Spec.HolderIdentity, canaryDeploymentName+"-") {
+		return fmt.Errorf("the canary manager holds the lease %s and runs the scheduler and the controllers for every namespace, restart its pods to hand the lease back to the stable manager", lease.Name)
+	}
+	return nil
+}
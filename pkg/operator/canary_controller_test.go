@@ -0,0 +1,419 @@
+package operator
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	appsv1listers "k8s.io/client-go/listers/apps/v1"
+	coordinationv1listers "k8s.io/client-go/listers/coordination/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/ptr"
+	configapi "sigs.k8s.io/kueue/apis/config/v1beta1"
+	"sigs.k8s.io/yaml"
+
+	kueuev1alpha1 "github.com/openshift/kueue-operator/pkg/apis/kueueoperator/v1alpha1"
+	"github.com/openshift/kueue-operator/pkg/builders/configmap"
+	"github.com/openshift/kueue-operator/pkg/namespace"
+	"github.com/openshift/kueue-operator/pkg/operator/operatorclient"
+)
+
+func newTestCanary() *kueuev1alpha1.Canary {
+	return &kueuev1alpha1.Canary{
+		Image:             "registry.k8s.io/kueue/kueue:v0.11.0",
+		NamespaceSelector: metav1.LabelSelector{MatchLabels: map[string]string{"kueue.openshift.io/canary": "true"}},
+	}
+}
+
+// webhookSelectors returns the namespace selectors of the webhooks of a configuration,
+// keyed by webhook name.
+func webhookSelectors(t *testing.T, r *testReconciler, name string) map[string]*metav1.LabelSelector {
+	t.Helper()
+	webhooks, err := r.kubeClient.AdmissionregistrationV1().ValidatingWebhookConfigurations().Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	selectors := map[string]*metav1.LabelSelector{}
+	for _, webhook := range webhooks.Webhooks {
+		selectors[webhook.Name] = webhook.NamespaceSelector
+		if name == canaryValidatingWebhookName && webhook.ClientConfig.Service.Name != canaryWebhookServiceName {
+			t.Errorf("Unexpected service of canary webhook %s: %s", webhook.Name, webhook.ClientConfig.Service.Name)
+		}
+	}
+	return selectors
+}
+
+func hasRequirement(selector *metav1.LabelSelector, operator metav1.LabelSelectorOperator) bool {
+	if selector == nil {
+		return false
+	}
+	for _, requirement := range selector.MatchExpressions {
+		if requirement.Key == "kueue.openshift.io/canary" && requirement.Operator == operator {
+			return true
+		}
+	}
+	return false
+}
+
+func TestSyncAppliesCanary(t *testing.T) {
+	ns := namespace.GetNamespace()
+	ctx := context.Background()
+	kueue := newTestKueue()
+	kueue.Spec.Canary = newTestCanary()
+	r := newTestReconciler(t, kueue)
+	if err := r.sync(queueItem{kind: "kueue"}); err != nil {
+		t.Fatalf("Unexpected sync error: %v", err)
+	}
+
+	deployment, err := r.kubeClient.AppsV1().Deployments(ns).Get(ctx, canaryDeploymentName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := deployment.Spec.Template.Spec.Containers[0].Image; got != kueue.Spec.Canary.Image {
+		t.Errorf("Unexpected canary image: %s", got)
+	}
+	if got := deployment.Spec.Selector.MatchLabels[controlPlaneLabel]; got != canaryControlPlane {
+		t.Errorf("Unexpected canary selector: %s", got)
+	}
+	if _, ok := deployment.Spec.Template.Annotations["deployment"]; ok {
+		t.Errorf("The canary must not track the stable deployment")
+	}
+	service, err := r.kubeClient.CoreV1().Services(ns).Get(ctx, canaryWebhookServiceName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := service.Annotations[servingCertSecretAnnotation]; got != canaryWebhookCertName {
+		t.Errorf("Unexpected serving certificate of the canary service: %s", got)
+	}
+
+	canary := webhookSelectors(t, r, canaryValidatingWebhookName)
+	stable := webhookSelectors(t, r, "kueue-validating-webhook-configuration")
+	for name, selector := range stable {
+		_, routed := canary[name]
+		if clusterScoped := name == "vclusterqueue.kb.io" || name == "vcohort.kb.io" || name == "vresourceflavor.kb.io"; routed == clusterScoped {
+			t.Errorf("Unexpected routing of webhook %s to the canary: %t", name, routed)
+		}
+		if routed != hasRequirement(selector, metav1.LabelSelectorOpNotIn) {
+			t.Errorf("Unexpected namespace selector of stable webhook %s: %v", name, selector)
+		}
+		if routed && !hasRequirement(canary[name], metav1.LabelSelectorOpIn) {
+			t.Errorf("Unexpected namespace selector of canary webhook %s: %v", name, canary[name])
+		}
+	}
+
+	// Removing the canary routes every namespace to the stable manager and deletes it.
+	updated, err := r.operatorClient.KueueV1alpha1().Kueues(ns).Get(ctx, kueue.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	updated.Spec.Canary = nil
+	if _, err := r.operatorClient.KueueV1alpha1().Kueues(ns).Update(ctx, updated, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.sync(queueItem{kind: "kueue"}); err != nil {
+		t.Fatalf("Unexpected sync error: %v", err)
+	}
+	for name, selector := range webhookSelectors(t, r, "kueue-validating-webhook-configuration") {
+		if hasRequirement(selector, metav1.LabelSelectorOpNotIn) {
+			t.Errorf("Stable webhook %s still excludes the canary namespaces", name)
+		}
+	}
+	if _, err := r.kubeClient.AppsV1().Deployments(ns).Get(ctx, canaryDeploymentName, metav1.GetOptions{}); !errors.IsNotFound(err) {
+		t.Errorf("Expected the canary deployment to be deleted, got %v", err)
+	}
+	if _, err := r.kubeClient.AdmissionregistrationV1().ValidatingWebhookConfigurations().Get(ctx, canaryValidatingWebhookName, metav1.GetOptions{}); !errors.IsNotFound(err) {
+		t.Errorf("Expected the canary webhooks to be deleted, got %v", err)
+	}
+}
+
+func TestSyncSharesLeaseWithCanary(t *testing.T) {
+	ns := namespace.GetNamespace()
+	ctx := context.Background()
+	kueue := newTestKueue()
+	kueue.Spec.Canary = newTestCanary()
+	r := newTestReconciler(t, kueue)
+	if err := r.sync(queueItem{kind: "kueue"}); err != nil {
+		t.Fatalf("Unexpected sync error: %v", err)
+	}
+
+	configurations := map[string]*configapi.Configuration{}
+	for _, name := range []string{KueueConfigMap, canaryConfigMapName} {
+		cfgMap, err := r.kubeClient.CoreV1().ConfigMaps(ns).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		configuration := &configapi.Configuration{}
+		if err := yaml.Unmarshal([]byte(cfgMap.Data[configmap.ConfigKey]), configuration); err != nil {
+			t.Fatal(err)
+		}
+		configurations[name] = configuration
+	}
+	stable, canary := configurations[KueueConfigMap], configurations[canaryConfigMapName]
+
+	// The scheduler and the controllers that admit workloads only run in the manager
+	// that holds the lease, so both managers must elect a leader on the same lease.
+	for name, configuration := range configurations {
+		leaderElection := configuration.LeaderElection
+		if leaderElection != nil && leaderElection.LeaderElect != nil && !*leaderElection.LeaderElect {
+			t.Errorf("Leader election is disabled in %s", name)
+		}
+		leaseName := configapi.DefaultLeaderElectionID
+		if leaderElection != nil && len(leaderElection.ResourceName) > 0 {
+			leaseName = leaderElection.ResourceName
+		}
+		if leaseName != configapi.DefaultLeaderElectionID {
+			t.Errorf("Expected %s to use the lease %s, got %s", name, configapi.DefaultLeaderElectionID, leaseName)
+		}
+	}
+	// The leader manages the jobs of every namespace, whichever manager it is.
+	if !equality.Semantic.DeepEqual(stable.ManagedJobsNamespaceSelector, canary.ManagedJobsNamespaceSelector) {
+		t.Errorf("The managers manage different namespaces: stable %v, canary %v", stable.ManagedJobsNamespaceSelector, canary.ManagedJobsNamespaceSelector)
+	}
+
+	deployment, err := r.kubeClient.AppsV1().Deployments(ns).Get(ctx, canaryDeploymentName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	mounted := false
+	for _, volume := range deployment.Spec.Template.Spec.Volumes {
+		mounted = mounted || volume.ConfigMap != nil && volume.ConfigMap.Name == canaryConfigMapName
+	}
+	if !mounted {
+		t.Errorf("Expected the canary to mount %s, got %v", canaryConfigMapName, deployment.Spec.Template.Spec.Volumes)
+	}
+	if _, ok := deployment.Spec.Template.Annotations[configMapAnnotation]; ok {
+		t.Errorf("The canary must not track the configuration of the stable manager")
+	}
+	if _, ok := deployment.Spec.Template.Annotations[canaryConfigMapAnnotation]; !ok {
+		t.Errorf("Expected the canary to track its configuration, got %v", deployment.Spec.Template.Annotations)
+	}
+
+	updated, err := r.operatorClient.KueueV1alpha1().Kueues(ns).Get(ctx, kueue.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	updated.Spec.Canary = nil
+	if _, err := r.operatorClient.KueueV1alpha1().Kueues(ns).Update(ctx, updated, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.sync(queueItem{kind: "kueue"}); err != nil {
+		t.Fatalf("Unexpected sync error: %v", err)
+	}
+	if _, err := r.kubeClient.CoreV1().ConfigMaps(ns).Get(ctx, canaryConfigMapName, metav1.GetOptions{}); !errors.IsNotFound(err) {
+		t.Errorf("Expected the canary configuration to be deleted, got %v", err)
+	}
+}
+
+func TestSyncRejectsCanarySelector(t *testing.T) {
+	ctx := context.Background()
+	kueue := newTestKueue()
+	kueue.Spec.Canary = newTestCanary()
+	kueue.Spec.Canary.NamespaceSelector.MatchLabels["team"] = "ml"
+	r := newTestReconciler(t, kueue)
+
+	if err := r.sync(queueItem{kind: "kueue"}); err == nil || !strings.Contains(err.Error(), "must have a single label or expression") {
+		t.Fatalf("Expected an error about the canary selector, got %v", err)
+	}
+	// The stable webhooks wait for the canary webhooks, so they are not changed.
+	if _, err := r.kubeClient.AdmissionregistrationV1().ValidatingWebhookConfigurations().Get(ctx, "kueue-validating-webhook-configuration", metav1.GetOptions{}); !errors.IsNotFound(err) {
+		t.Errorf("Expected the stable webhooks to wait for the canary, got %v", err)
+	}
+}
+
+func newTestCanaryController(t *testing.T, kueue *kueuev1alpha1.Kueue, objects ...interface{}) *CanaryController {
+	t.Helper()
+	defaults, _ := newTestDefaultsController(t, kueue)
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	for _, obj := range objects {
+		if err := indexer.Add(obj); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return &CanaryController{
+		ctx:               defaults.ctx,
+		operatorClient:    defaults.operatorClient,
+		deploymentLister:  appsv1listers.NewDeploymentLister(indexer),
+		podLister:         corev1listers.NewPodLister(indexer),
+		leaseLister:       coordinationv1listers.NewLeaseLister(indexer),
+		namespaceLister:   corev1listers.NewNamespaceLister(indexer),
+		eventRecorder:     defaults.eventRecorder,
+		operatorNamespace: defaults.operatorNamespace,
+	}
+}
+
+func newTestManagerPod(name, controlPlane string, restarts int32) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace.GetNamespace(), Labels: map[string]string{controlPlaneLabel: controlPlane}},
+		Status:     corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{Name: "manager", RestartCount: restarts}}},
+	}
+}
+
+func TestCanaryControllerComparesManagers(t *testing.T) {
+	kueue := newTestKueue()
+	kueue.Spec.Canary = newTestCanary()
+	stable := newTestKueueDeployment(kueue, 1)
+	canary := newTestKueueDeployment(kueue, 0)
+	canary.Name = canaryDeploymentName
+	canary.Spec.Template.Spec.Containers[0].Image = kueue.Spec.Canary.Image
+	c := newTestCanaryController(t, kueue,
+		stable,
+		canary,
+		newTestManagerPod("kueue-a", stableControlPlane, 0),
+		newTestManagerPod("kueue-canary-a", canaryControlPlane, 3),
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a", Labels: map[string]string{"kueue.openshift.io/canary": "true"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-b"}},
+	)
+
+	if err := c.sync(); err != nil {
+		t.Fatalf("Unexpected sync error: %v", err)
+	}
+	got, err := c.operatorClient.Kueues(namespace.GetNamespace()).Get(c.ctx, operatorclient.OperatorConfigName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	status := got.Status.Canary
+	if status == nil || status.State != kueuev1alpha1.CanaryStateRunning || status.Namespaces != 1 {
+		t.Fatalf("Unexpected canary status: %+v", status)
+	}
+	if status.Stable.Image != kueue.Spec.Image || status.Stable.AvailableReplicas != 1 || status.Stable.Restarts != 0 {
+		t.Errorf("Unexpected stable manager status: %+v", status.Stable)
+	}
+	if status.Canary.Image != kueue.Spec.Canary.Image || status.Canary.AvailableReplicas != 0 || status.Canary.Restarts != 3 {
+		t.Errorf("Unexpected canary manager status: %+v", status.Canary)
+	}
+}
+
+func TestCanaryControllerReportsCanaryLeader(t *testing.T) {
+	for _, tc := range []struct {
+		holder       string
+		wantDegraded bool
+	}{
+		{holder: "kueue-controller-manager-7d9fb8c6f-x2bkq_0f2c5a5e-7d1e-4b9e-9d0b-6f1d0c0b4f7a"},
+		{holder: canaryDeploymentName + "-5c8d7b9f4-qm4zt_3a7e1c2d-9b8f-4c6e-a5d4-2e1f0b9c8d7e", wantDegraded: true},
+	} {
+		t.Run(tc.holder, func(t *testing.T) {
+			kueue := newTestKueue()
+			kueue.Spec.Canary = newTestCanary()
+			lease := &coordinationv1.Lease{
+				ObjectMeta: metav1.ObjectMeta{Name: configapi.DefaultLeaderElectionID, Namespace: namespace.GetNamespace()},
+				Spec:       coordinationv1.LeaseSpec{HolderIdentity: ptr.To(tc.holder)},
+			}
+			c := newTestCanaryController(t, kueue, lease)
+
+			err := c.sync()
+			if tc.wantDegraded != (err != nil) {
+				t.Fatalf("Unexpected sync error: %v", err)
+			}
+			got, err := c.operatorClient.Kueues(namespace.GetNamespace()).Get(c.ctx, operatorclient.OperatorConfigName, metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if degraded := v1helpers.IsOperatorConditionTrue(got.Status.Conditions, "CanaryControllerDegraded"); degraded != tc.wantDegraded {
+				t.Errorf("Expected CanaryControllerDegraded to be %v, got %v", tc.wantDegraded, got.Status.Conditions)
+			}
+		})
+	}
+}
+
+func TestCanaryControllerActions(t *testing.T) {
+	for _, tc := range []struct {
+		action    kueuev1alpha1.CanaryAction
+		wantState kueuev1alpha1.CanaryState
+	}{
+		{action: kueuev1alpha1.CanaryActionPromote, wantState: kueuev1alpha1.CanaryStatePromoted},
+		{action: kueuev1alpha1.CanaryActionAbort, wantState: kueuev1alpha1.CanaryStateAborted},
+	} {
+		t.Run(string(tc.action), func(t *testing.T) {
+			kueue := newTestKueue()
+			kueue.Spec.Canary = newTestCanary()
+			kueue.Spec.Canary.Action = tc.action
+			c := newTestCanaryController(t, kueue)
+
+			if err := c.sync(); err != nil {
+				t.Fatalf("Unexpected sync error: %v", err)
+			}
+			got, err := c.operatorClient.Kueues(namespace.GetNamespace()).Get(c.ctx, operatorclient.OperatorConfigName, metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			// The spec is left to the user.
+			if !equality.Semantic.DeepEqual(got.Spec, kueue.Spec) {
+				t.Errorf("Unexpected spec change after %s: image=%s, canary=%+v", tc.action, got.Spec.Image, got.Spec.Canary)
+			}
+			if got.Status.Canary == nil || got.Status.Canary.State != tc.wantState || got.Status.Canary.Image != "registry.k8s.io/kueue/kueue:v0.11.0" {
+				t.Errorf("Unexpected canary status: %+v", got.Status.Canary)
+			}
+
+			// The outcome is kept once the user removes the canary.
+			got.Spec.Canary = nil
+			if _, err := c.operatorClient.Kueues(namespace.GetNamespace()).Update(c.ctx, got, metav1.UpdateOptions{}); err != nil {
+				t.Fatal(err)
+			}
+			if err := c.sync(); err != nil {
+				t.Fatalf("Unexpected sync error: %v", err)
+			}
+			got, err = c.operatorClient.Kueues(namespace.GetNamespace()).Get(c.ctx, operatorclient.OperatorConfigName, metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Status.Canary == nil || got.Status.Canary.State != tc.wantState {
+				t.Errorf("Unexpected canary status after the canary was removed: %+v", got.Status.Canary)
+			}
+			if !v1helpers.IsOperatorConditionFalse(got.Status.Conditions, "CanaryControllerDegraded") {
+				t.Errorf("Expected CanaryControllerDegraded to be false, got %v", got.Status.Conditions)
+			}
+		})
+	}
+}
+
+func TestSyncEndsCanaryOnAction(t *testing.T) {
+	for _, tc := range []struct {
+		action     kueuev1alpha1.CanaryAction
+		wantCanary bool
+	}{
+		// A promoted canary serves its namespaces until the user sets spec.image.
+		{action: kueuev1alpha1.CanaryActionPromote, wantCanary: true},
+		{action: kueuev1alpha1.CanaryActionAbort},
+	} {
+		t.Run(string(tc.action), func(t *testing.T) {
+			ns := namespace.GetNamespace()
+			ctx := context.Background()
+			kueue := newTestKueue()
+			kueue.Spec.Canary = newTestCanary()
+			r := newTestReconciler(t, kueue)
+			if err := r.sync(queueItem{kind: "kueue"}); err != nil {
+				t.Fatalf("Unexpected sync error: %v", err)
+			}
+
+			updated, err := r.operatorClient.KueueV1alpha1().Kueues(ns).Get(ctx, kueue.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			updated.Spec.Canary.Action = tc.action
+			if _, err := r.operatorClient.KueueV1alpha1().Kueues(ns).Update(ctx, updated, metav1.UpdateOptions{}); err != nil {
+				t.Fatal(err)
+			}
+			if err := r.sync(queueItem{kind: "kueue"}); err != nil {
+				t.Fatalf("Unexpected sync error: %v", err)
+			}
+
+			_, err = r.kubeClient.AppsV1().Deployments(ns).Get(ctx, canaryDeploymentName, metav1.GetOptions{})
+			if tc.wantCanary && err != nil || !tc.wantCanary && !errors.IsNotFound(err) {
+				t.Errorf("Unexpected canary deployment after %s: %v", tc.action, err)
+			}
+			for name, selector := range webhookSelectors(t, r, "kueue-validating-webhook-configuration") {
+				clusterScoped := name == "vclusterqueue.kb.io" || name == "vcohort.kb.io" || name == "vresourceflavor.kb.io"
+				if excluded := hasRequirement(selector, metav1.LabelSelectorOpNotIn); excluded != (tc.wantCanary && !clusterScoped) {
+					t.Errorf("Unexpected exclusion of the canary namespaces from stable webhook %s after %s: %t", name, tc.action, excluded)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package operator
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	kueuev1alpha1 "github.com/openshift/kueue-operator/pkg/apis/kueueoperator/v1alpha1"
+	"github.com/openshift/kueue-operator/pkg/builders/configmap"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// configRevisionLabel holds the revision of a ConfigMap that records a rendered Kueue
+	// configuration. Revisions are found by the existence of the label.
+	configRevisionLabel = "kueue.openshift.io/config-revision"
+	// configHashLabel holds the content hash of the recorded configuration.
+	configHashLabel = "kueue.openshift.io/config-hash"
+	// configGenerationLabel holds the generation of the Kueue the configuration was
+	// rendered from.
+	configGenerationLabel = "kueue.openshift.io/config-generation"
+
+	defaultRevisionHistoryLimit = 5
+)
+
+func configRevisionName(revision int64) string {
+	return fmt.Sprintf("%s-%d", KueueConfigMap, revision)
+}
+
+// configRevision is a recorded configuration and its revision.
+type configRevision struct {
+	revision  int64
+	configMap *v1.ConfigMap
+}
+
+// listConfigRevisions returns the recorded configurations, oldest first. The revision
+// ConfigMaps are not labelled as managed, so the drift informers do not see them.
+func (c *TargetConfigReconciler) listConfigRevisions() ([]configRevision, error) {
+	list, err := c.kubeClient.CoreV1().ConfigMaps(c.operatorNamespace).List(c.ctx, metav1.ListOptions{LabelSelector: configRevisionLabel})
+	if err != nil {
+		return nil, err
+	}
+	revisions := make([]configRevision, 0, len(list.Items))
+	for i := range list.Items {
+		revision, err := strconv.ParseInt(list.Items[i].Labels[configRevisionLabel], 10, 64)
+		if err != nil {
+			klog.InfoS("Ignoring ConfigMap with an invalid config revision", "configmap", list.Items[i].Name, "revision", list.Items[i].Labels[configRevisionLabel])
+			continue
+		}
+		revisions = append(revisions, configRevision{revision: revision, configMap: &list.Items[i]})
+	}
+	sort.Slice(revisions, func(i, j int) bool { return revisions[i].revision < revisions[j].revision })
+	return revisions, nil
+}
+
+// withConfigRollback replaces the configmap step so that it applies the configuration of
+// spec.config.rollbackTo instead of rendering spec.config. The step fails when the
+// revision is not recorded, which keeps the running configuration.
+func withConfigRollback(rollbackTo int64, steps []resourceApplier, revisions []configRevision) []resourceApplier {
+	var recorded *v1.ConfigMap
+	for _, revision := range revisions {
+		if revision.revision == rollbackTo {
+			recorded = revision.configMap
+		}
+	}
+	for i := range steps {
+		if steps[i].name != "configmap" {
+			continue
+		}
+		steps[i].required = func(kueue *kueuev1alpha1.Kueue, _ map[string]string) (map[string]runtime.Object, error) {
+			if recorded == nil {
+				return nil, fmt.Errorf("config revision %d to roll back to is not recorded", rollbackTo)
+			}
+			cfgMap := &v1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      KueueConfigMap,
+					Namespace: kueue.Namespace,
+				},
+				Data: make(map[string]string, len(recorded.Data)),
+			}
+			for key, value := range recorded.Data {
+				cfgMap.Data[key] = value
+			}
+			// Revisions recorded by an older operator may not pass the current validation.
+			if err := configmap.Validate(cfgMap, kueueFeatureGateMap(kueue)); err != nil {
+				return nil, err
+			}
+			return map[string]runtime.Object{configMapAnnotation: cfgMap}, nil
+		}
+	}
+	return steps
+}
+
+// recordConfigRevision records the applied configuration as a new revision unless a
+// revision with the same content exists, and prunes the revisions beyond
+// spec.config.revisionHistoryLimit. The revision in use is never pruned. It returns the
+// history for status.configRevisions.
+func (c *TargetConfigReconciler) recordConfigRevision(kueue *kueuev1alpha1.Kueue) ([]kueuev1alpha1.ConfigRevision, error) {
+	revisions, err := c.listConfigRevisions()
+	if err != nil {
+		return nil, err
+	}
+	applied, err := c.kubeClient.CoreV1().ConfigMaps(c.operatorNamespace).Get(c.ctx, KueueConfigMap, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	hash := configmap.Hash(applied.Data)
+
+	var current int64
+	var latest int64
+	for _, revision := range revisions {
+		if revision.configMap.Labels[configHashLabel] == hash {
+			current = revision.revision
+		}
+		latest = max(latest, revision.revision)
+	}
+	if current == 0 {
+		current = latest + 1
+		recorded := &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      configRevisionName(current),
+				Namespace: c.operatorNamespace,
+				Labels: map[string]string{
+					configRevisionLabel:   strconv.FormatInt(current, 10),
+					configHashLabel:       hash,
+					configGenerationLabel: strconv.FormatInt(kueue.Generation, 10),
+				},
+			},
+			Data: applied.Data,
+		}
+		setOwnerReference(recorded, kueue)
+		created, err := c.kubeClient.CoreV1().ConfigMaps(c.operatorNamespace).Create(c.ctx, recorded, metav1.CreateOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("unable to record config revision %d: %w", current, err)
+		}
+		c.eventRecorder.Eventf("ConfigRevisionRecorded", "Recorded the Kueue configuration as revision %d", current)
+		revisions = append(revisions, configRevision{revision: current, configMap: created})
+	} else if kueue.Spec.Config.RollbackTo != nil && *kueue.Spec.Config.RollbackTo == current && !isCurrentRevision(kueue.Status.ConfigRevisions, current) {
+		c.eventRecorder.Warningf("ConfigRolledBack", "Rolled the Kueue configuration back to revision %d", current)
+	}
+
+	limit := defaultRevisionHistoryLimit
+	if kueue.Spec.Config.RevisionHistoryLimit != nil {
+		limit = int(*kueue.Spec.Config.RevisionHistoryLimit)
+	}
+	var errs []error
+	history := make([]kueuev1alpha1.ConfigRevision, 0, len(revisions))
+	for i, revision := range revisions {
+		if revision.revision != current && len(revisions)-i > limit {
+			err := c.kubeClient.CoreV1().ConfigMaps(c.operatorNamespace).Delete(c.ctx, revision.configMap.Name, metav1.DeleteOptions{})
+			if err == nil || errors.IsNotFound(err) {
+				continue
+			}
+			errs = append(errs, fmt.Errorf("unable to prune config revision %d: %w", revision.revision, err))
+		}
+		generation, _ := strconv.ParseInt(revision.configMap.Labels[configGenerationLabel], 10, 64)
+		history = append(history, kueuev1alpha1.ConfigRevision{
+			Revision:   revision.revision,
+			Hash:       revision.configMap.Labels[configHashLabel],
+			Generation: generation,
+			CreatedAt:  revision.configMap.CreationTimestamp,
+			Current:    revision.revision == current,
+		})
+	}
+	return history, utilerrors.NewAggregate(errs)
+}
+
+func isCurrentRevision(history []kueuev1alpha1.ConfigRevision, revision int64) bool {
+	for _, recorded := range history {
+		if recorded.Revision == revision {
+			return recorded.Current
+		}
+	}
+	return false
+}
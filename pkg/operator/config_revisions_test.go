@@ -0,0 +1,116 @@
+package operator
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	configapi "sigs.k8s.io/kueue/apis/config/v1beta1"
+
+	kueuev1alpha1 "github.com/openshift/kueue-operator/pkg/apis/kueueoperator/v1alpha1"
+	"github.com/openshift/kueue-operator/pkg/builders/configmap"
+	"github.com/openshift/kueue-operator/pkg/namespace"
+	"github.com/openshift/kueue-operator/pkg/operator/operatorclient"
+)
+
+// updateConfig applies update to the Kueue as a new generation and reconciles it.
+func (r *testReconciler) updateConfig(t *testing.T, update func(config *kueuev1alpha1.KueueConfiguration)) error {
+	t.Helper()
+	kueue, err := r.operatorClient.KueueV1alpha1().Kueues(namespace.GetNamespace()).Get(context.Background(), operatorclient.OperatorConfigName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	update(&kueue.Spec.Config)
+	kueue.Generation++
+	if _, err := r.operatorClient.KueueV1alpha1().Kueues(namespace.GetNamespace()).Update(context.Background(), kueue, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	return r.sync(queueItem{kind: "kueue"})
+}
+
+func (r *testReconciler) configRevisions(t *testing.T) (revisions []string, current int64) {
+	t.Helper()
+	kueue, err := r.operatorClient.KueueV1alpha1().Kueues(namespace.GetNamespace()).Get(context.Background(), operatorclient.OperatorConfigName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, revision := range kueue.Status.ConfigRevisions {
+		revisions = append(revisions, configRevisionName(revision.Revision))
+		if revision.Current {
+			current = revision.Revision
+		}
+	}
+	return revisions, current
+}
+
+func (r *testReconciler) appliedConfigHash(t *testing.T) string {
+	t.Helper()
+	cfgMap, err := r.kubeClient.CoreV1().ConfigMaps(namespace.GetNamespace()).Get(context.Background(), KueueConfigMap, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return configmap.Hash(cfgMap.Data)
+}
+
+func TestConfigRevisions(t *testing.T) {
+	kueue := newTestKueue()
+	kueue.Spec.Config.RevisionHistoryLimit = ptr.To[int32](2)
+	r := newTestReconciler(t, kueue)
+	if err := r.sync(queueItem{kind: "kueue"}); err != nil {
+		t.Fatalf("Unexpected sync error: %v", err)
+	}
+
+	// Every new configuration is recorded, the oldest beyond the limit are pruned.
+	if err := r.updateConfig(t, func(config *kueuev1alpha1.KueueConfiguration) {
+		config.WaitForPodsReady = &configapi.WaitForPodsReady{Enable: true}
+	}); err != nil {
+		t.Fatalf("Unexpected sync error: %v", err)
+	}
+	secondHash := r.appliedConfigHash(t)
+	if err := r.updateConfig(t, func(config *kueuev1alpha1.KueueConfiguration) {
+		config.Integrations.Frameworks = append(config.Integrations.Frameworks, "kubeflow.org/mpijob")
+	}); err != nil {
+		t.Fatalf("Unexpected sync error: %v", err)
+	}
+	revisions, current := r.configRevisions(t)
+	if diff := cmp.Diff([]string{"kueue-manager-config-2", "kueue-manager-config-3"}, revisions); len(diff) != 0 {
+		t.Errorf("Unexpected config revisions (-want,+got):\n%s", diff)
+	}
+	if current != 3 {
+		t.Errorf("Expected revision 3 to be current, got %d", current)
+	}
+	if _, err := r.kubeClient.CoreV1().ConfigMaps(namespace.GetNamespace()).Get(context.Background(), configRevisionName(1), metav1.GetOptions{}); err == nil {
+		t.Errorf("Expected revision 1 to be pruned")
+	}
+
+	// Rolling back restores the recorded configuration without recording a new revision.
+	if err := r.updateConfig(t, func(config *kueuev1alpha1.KueueConfiguration) {
+		config.RollbackTo = ptr.To[int64](2)
+	}); err != nil {
+		t.Fatalf("Unexpected sync error: %v", err)
+	}
+	if got := r.appliedConfigHash(t); got != secondHash {
+		t.Errorf("Expected the configuration of revision 2 with hash %s, got %s", secondHash, got)
+	}
+	revisions, current = r.configRevisions(t)
+	if diff := cmp.Diff([]string{"kueue-manager-config-2", "kueue-manager-config-3"}, revisions); len(diff) != 0 {
+		t.Errorf("Unexpected config revisions (-want,+got):\n%s", diff)
+	}
+	if current != 2 {
+		t.Errorf("Expected revision 2 to be current, got %d", current)
+	}
+
+	// A revision that is not recorded fails the configmap step and keeps the running
+	// configuration.
+	if err := r.updateConfig(t, func(config *kueuev1alpha1.KueueConfiguration) {
+		config.RollbackTo = ptr.To[int64](1)
+	}); err == nil || !strings.Contains(err.Error(), "config revision 1 to roll back to is not recorded") {
+		t.Fatalf("Expected an error about the missing revision, got %v", err)
+	}
+	if got := r.appliedConfigHash(t); got != secondHash {
+		t.Errorf("Expected the configuration of revision 2 to keep running, got hash %s", got)
+	}
+}
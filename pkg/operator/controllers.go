@@ -0,0 +1,59 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
+)
+
+// queueHandler returns an event handler that queues a sync of the controller of syncCtx.
+// Updates are only queued when changed is nil or returns true for them, which lets the
+// controllers skip updates that factory event filters cannot tell apart, as they only see
+// the new object.
+func queueHandler(syncCtx factory.SyncContext, changed func(old, new interface{}) bool) cache.ResourceEventHandler {
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) { syncCtx.Queue().Add(factory.DefaultQueueKey) },
+		UpdateFunc: func(old, new interface{}) {
+			if changed == nil || changed(old, new) {
+				syncCtx.Queue().Add(factory.DefaultQueueKey)
+			}
+		},
+		DeleteFunc: func(obj interface{}) { syncCtx.Queue().Add(factory.DefaultQueueKey) },
+	}
+}
+
+// kueueSpecChanged passes the updates of the Kueue CR that change its spec. The
+// controllers each write their own status conditions, and would otherwise all sync again
+// after every status write of another one.
+func kueueSpecChanged(old, new interface{}) bool {
+	oldKueue, err := meta.Accessor(old)
+	if err != nil {
+		return true
+	}
+	newKueue, err := meta.Accessor(new)
+	if err != nil {
+		return true
+	}
+	return oldKueue.GetGeneration() != newKueue.GetGeneration()
+}
+
+// startDynamicInformers returns a post start hook that starts the informers of Kueue
+// resources and queues a sync once they are synced. They are not registered with the
+// factory, which exits the operator when caches do not sync within 10 minutes, while the
+// Kueue CRDs are only there once the operand is installed.
+func startDynamicInformers(informers dynamicinformer.DynamicSharedInformerFactory) factory.PostStartHook {
+	return func(ctx context.Context, syncCtx factory.SyncContext) error {
+		informers.Start(ctx.Done())
+		for gvr, synced := range informers.WaitForCacheSync(ctx.Done()) {
+			if !synced {
+				return fmt.Errorf("unable to sync the informer of %s", gvr.Resource)
+			}
+		}
+		syncCtx.Queue().Add(factory.DefaultQueueKey)
+		return nil
+	}
+}
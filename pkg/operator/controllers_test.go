@@ -0,0 +1,34 @@
+package operator
+
+import (
+	"testing"
+	"time"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clocktesting "k8s.io/utils/clock/testing"
+
+	kueuev1alpha1 "github.com/openshift/kueue-operator/pkg/apis/kueueoperator/v1alpha1"
+)
+
+func TestQueueHandlerSkipsKueueStatusUpdates(t *testing.T) {
+	syncCtx := factory.NewSyncContext("test", events.NewInMemoryRecorder("test", clocktesting.NewFakePassiveClock(time.Now())))
+	handler := queueHandler(syncCtx, kueueSpecChanged)
+
+	old := &kueuev1alpha1.Kueue{ObjectMeta: metav1.ObjectMeta{Name: "cluster", Generation: 1}}
+	statusUpdate := old.DeepCopy()
+	statusUpdate.Status.Conditions = []operatorv1.OperatorCondition{{Type: "QuotaControllerDegraded", Status: operatorv1.ConditionFalse}}
+	handler.OnUpdate(old, statusUpdate)
+	if syncCtx.Queue().Len() != 0 {
+		t.Fatalf("Expected a status update not to queue a sync, queue length is %d", syncCtx.Queue().Len())
+	}
+
+	specUpdate := statusUpdate.DeepCopy()
+	specUpdate.Generation = 2
+	handler.OnUpdate(statusUpdate, specUpdate)
+	if syncCtx.Queue().Len() != 1 {
+		t.Fatalf("Expected a spec update to queue a sync, queue length is %d", syncCtx.Queue().Len())
+	}
+}
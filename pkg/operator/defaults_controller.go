@@ -0,0 +1,272 @@
+package operator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	kueuev1alpha1 "github.com/openshift/kueue-operator/pkg/apis/kueueoperator/v1alpha1"
+	"github.com/openshift/kueue-operator/pkg/builders/configmap"
+	"github.com/openshift/kueue-operator/pkg/builders/queues"
+	kueueconfigclient "github.com/openshift/kueue-operator/pkg/generated/clientset/versioned/typed/kueueoperator/v1alpha1"
+	operatorclientinformers "github.com/openshift/kueue-operator/pkg/generated/informers/externalversions/kueueoperator/v1alpha1"
+	"github.com/openshift/kueue-operator/pkg/namespace"
+	"github.com/openshift/kueue-operator/pkg/operator/operatorclient"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/dynamic"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+const (
+	defaultResourceFlavorName = "default-flavor"
+	defaultClusterQueueName   = "cluster-queue"
+	defaultLocalQueueName     = "default"
+
+	defaultsResyncInterval = 10 * time.Minute
+
+	// defaultsHashAnnotation records the hash of the spec.defaults an object was created from.
+	defaultsHashAnnotation = "kueue.openshift.io/defaults-hash"
+
+	// defaultsOutdatedCondition is true while objects created from earlier defaults remain.
+	defaultsOutdatedCondition = "DefaultsOutdated"
+)
+
+// DefaultsController creates the ResourceFlavor, ClusterQueue and LocalQueues described
+// in spec.defaults of the Kueue CR. Objects are only created when they are missing, so
+// changes made to them by admins are never overwritten. Neither are changes made to
+// spec.defaults: the DefaultsOutdated condition lists the objects created from earlier
+// defaults, deleting them has them recreated from the current ones.
+type DefaultsController struct {
+	ctx               context.Context
+	operatorClient    kueueconfigclient.KueueV1alpha1Interface
+	dynamicClient     dynamic.Interface
+	namespaceLister   corev1listers.NamespaceLister
+	nodeLister        corev1listers.NodeLister
+	eventRecorder     events.Recorder
+	operatorNamespace string
+}
+
+func NewDefaultsController(
+	ctx context.Context,
+	operatorConfigClient kueueconfigclient.KueueV1alpha1Interface,
+	operatorClientInformer operatorclientinformers.KueueInformer,
+	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
+	dynamicClient dynamic.Interface,
+	eventRecorder events.Recorder,
+) (factory.Controller, error) {
+	clusterInformers := kubeInformersForNamespaces.InformersFor("")
+	c := &DefaultsController{
+		ctx:               ctx,
+		operatorClient:    operatorConfigClient,
+		dynamicClient:     dynamicClient,
+		namespaceLister:   clusterInformers.Core().V1().Namespaces().Lister(),
+		nodeLister:        clusterInformers.Core().V1().Nodes().Lister(),
+		eventRecorder:     eventRecorder.WithComponentSuffix("defaults-controller"),
+		operatorNamespace: namespace.GetNamespace(),
+	}
+
+	syncCtx := factory.NewSyncContext("DefaultsController", eventRecorder)
+	if _, err := operatorClientInformer.Informer().AddEventHandler(queueHandler(syncCtx, kueueSpecChanged)); err != nil {
+		return nil, err
+	}
+	// Nodes only matter for the quota of a ClusterQueue that does not exist yet.
+	if _, err := clusterInformers.Core().V1().Nodes().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) { syncCtx.Queue().Add(factory.DefaultQueueKey) },
+	}); err != nil {
+		return nil, err
+	}
+
+	return factory.New().
+		WithSyncContext(syncCtx).
+		WithInformers(clusterInformers.Core().V1().Namespaces().Informer()).
+		WithBareInformers(operatorClientInformer.Informer(), clusterInformers.Core().V1().Nodes().Informer()).
+		WithSync(func(ctx context.Context, _ factory.SyncContext) error { return c.sync() }).
+		// Recreate objects that were deleted, nothing watches them.
+		ResyncEvery(defaultsResyncInterval).
+		ToController("DefaultsController", c.eventRecorder), nil
+}
+
+func (c *DefaultsController) sync() error {
+	kueue, err := c.operatorClient.Kueues(c.operatorNamespace).Get(c.ctx, operatorclient.OperatorConfigName, metav1.GetOptions{})
+	if err != nil {
+		klog.ErrorS(err, "unable to get operator configuration", "namespace", c.operatorNamespace, "kueue", operatorclient.OperatorConfigName)
+		return err
+	}
+	if kueue.Spec.Defaults == nil {
+		return nil
+	}
+	defaults := withDefaults(kueue.Spec.Defaults)
+	hash, err := defaultsHash(defaults)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	var outdated []string
+	ensure := func(gvr schema.GroupVersionResource, obj metav1.Object) {
+		isOutdated, err := c.createIfMissing(gvr, obj, hash)
+		if err != nil {
+			errs = append(errs, err)
+		} else if isOutdated {
+			outdated = append(outdated, fmt.Sprintf("%s %s", obj.(runtime.Object).GetObjectKind().GroupVersionKind().Kind, objectName(obj)))
+		}
+	}
+	ensure(queues.ResourceFlavorsGVR, queues.BuildResourceFlavor(defaults.ResourceFlavorName))
+
+	nodes, err := c.nodeLister.List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	ensure(queues.ClusterQueuesGVR, queues.BuildClusterQueue(
+		defaults.ClusterQueueName,
+		defaults.ResourceFlavorName,
+		defaults.NamespaceSelector,
+		defaults.CoveredResources,
+		queues.AllocatableQuota(nodes, defaults.CoveredResources),
+	))
+
+	if defaults.NamespaceSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(defaults.NamespaceSelector)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid namespaceSelector: %w", err))
+		} else {
+			namespaces, err := c.namespaceLister.List(selector)
+			if err != nil {
+				return err
+			}
+			for _, ns := range namespaces {
+				if ns.Status.Phase == corev1.NamespaceTerminating {
+					continue
+				}
+				ensure(queues.LocalQueuesGVR, queues.BuildLocalQueue(ns.Name, defaults.LocalQueueName, defaults.ClusterQueueName))
+			}
+		}
+	}
+
+	degraded := operatorv1.OperatorCondition{
+		Type:   "DefaultsControllerDegraded",
+		Status: operatorv1.ConditionFalse,
+		Reason: "AsExpected",
+	}
+	if len(errs) > 0 {
+		degraded.Status = operatorv1.ConditionTrue
+		degraded.Reason = "CreateFailed"
+		degraded.Message = utilerrors.NewAggregate(errs).Error()
+	}
+	outdatedCondition := operatorv1.OperatorCondition{
+		Type:   defaultsOutdatedCondition,
+		Status: operatorv1.ConditionFalse,
+		Reason: "AsExpected",
+	}
+	if len(outdated) > 0 {
+		sort.Strings(outdated)
+		outdatedCondition.Status = operatorv1.ConditionTrue
+		outdatedCondition.Reason = "CreateOnly"
+		outdatedCondition.Message = fmt.Sprintf("%s were created from an earlier spec.defaults and are not updated, delete them to recreate them from the current defaults", strings.Join(outdated, ", "))
+	}
+	if err := updateKueueStatus(c.ctx, c.operatorClient, c.operatorNamespace, kueue.Name, func(status *kueuev1alpha1.KueueStatus) {
+		v1helpers.SetOperatorCondition(&status.Conditions, degraded)
+		v1helpers.SetOperatorCondition(&status.Conditions, outdatedCondition)
+	}); err != nil {
+		errs = append(errs, err)
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+// withDefaults returns a copy of defaults with unset fields defaulted. The API server
+// defaults them as well, this covers objects created before the fields existed.
+func withDefaults(defaults *kueuev1alpha1.KueueDefaults) *kueuev1alpha1.KueueDefaults {
+	defaults = defaults.DeepCopy()
+	if len(defaults.ResourceFlavorName) == 0 {
+		defaults.ResourceFlavorName = defaultResourceFlavorName
+	}
+	if len(defaults.ClusterQueueName) == 0 {
+		defaults.ClusterQueueName = defaultClusterQueueName
+	}
+	if len(defaults.LocalQueueName) == 0 {
+		defaults.LocalQueueName = defaultLocalQueueName
+	}
+	if len(defaults.CoveredResources) == 0 {
+		defaults.CoveredResources = []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory}
+	}
+	return defaults
+}
+
+// defaultsHash hashes defaults, so that objects created from other defaults can be told apart.
+func defaultsHash(defaults *kueuev1alpha1.KueueDefaults) (string, error) {
+	data, err := json.Marshal(defaults)
+	if err != nil {
+		return "", err
+	}
+	return configmap.Hash(map[string]string{"defaults": string(data)}), nil
+}
+
+// createIfMissing creates obj annotated with the hash of the defaults it was built from.
+// It reports whether an existing object created by the operator was built from other
+// defaults. Objects created before the annotation existed, or by users, are not reported.
+func (c *DefaultsController) createIfMissing(gvr schema.GroupVersionResource, obj metav1.Object, hash string) (bool, error) {
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[defaultsHashAnnotation] = hash
+	obj.SetAnnotations(annotations)
+	if err := createIfMissing(c.ctx, c.dynamicClient, c.eventRecorder, gvr, obj.(runtime.Object)); err != nil {
+		return false, err
+	}
+	existing, err := c.dynamicClient.Resource(gvr).Namespace(obj.GetNamespace()).Get(c.ctx, obj.GetName(), metav1.GetOptions{})
+	if err != nil {
+		return false, err
+	}
+	if existing.GetLabels()[managedByLabel] != managedByValue {
+		return false, nil
+	}
+	existingHash, ok := existing.GetAnnotations()[defaultsHashAnnotation]
+	return ok && existingHash != hash, nil
+}
+
+// createIfMissing creates obj, labelled as managed by the operator, unless an object with
+// the same name already exists.
+func createIfMissing(ctx context.Context, dynamicClient dynamic.Interface, recorder events.Recorder, gvr schema.GroupVersionResource, obj runtime.Object) error {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return err
+	}
+	required := &unstructured.Unstructured{Object: content}
+	unstructured.RemoveNestedField(required.Object, "status")
+	setManagedByLabel(required)
+
+	client := dynamicClient.Resource(gvr).Namespace(required.GetNamespace())
+	_, err = client.Get(ctx, required.GetName(), metav1.GetOptions{})
+	if err == nil {
+		return nil
+	}
+	if !errors.IsNotFound(err) {
+		return err
+	}
+	if _, err := client.Create(ctx, required, metav1.CreateOptions{FieldManager: fieldManager}); err != nil {
+		if errors.IsAlreadyExists(err) {
+			return nil
+		}
+		return fmt.Errorf("unable to create %s %s: %w", required.GetKind(), objectName(required), err)
+	}
+	recorder.Eventf(required.GetKind()+"Created", "Created %s %s", required.GetKind(), objectName(required))
+	return nil
+}
@@ -0,0 +1,236 @@
+package operator
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/informers"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	clocktesting "k8s.io/utils/clock/testing"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	kueuev1alpha1 "github.com/openshift/kueue-operator/pkg/apis/kueueoperator/v1alpha1"
+	"github.com/openshift/kueue-operator/pkg/builders/queues"
+	operatorfake "github.com/openshift/kueue-operator/pkg/generated/clientset/versioned/fake"
+	"github.com/openshift/kueue-operator/pkg/namespace"
+)
+
+func newTestDefaultsController(t *testing.T, kueue *kueuev1alpha1.Kueue, kubeObjects ...runtime.Object) (*DefaultsController, *dynamicfake.FakeDynamicClient) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	kubeInformers := informers.NewSharedInformerFactory(kubefake.NewClientset(), 0)
+	for _, obj := range kubeObjects {
+		var err error
+		switch obj.(type) {
+		case *corev1.Node:
+			err = kubeInformers.Core().V1().Nodes().Informer().GetIndexer().Add(obj)
+		case *corev1.Namespace:
+			err = kubeInformers.Core().V1().Namespaces().Informer().GetIndexer().Add(obj)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		queues.ResourceFlavorsGVR: "ResourceFlavorList",
+		queues.ClusterQueuesGVR:   "ClusterQueueList",
+		queues.LocalQueuesGVR:     "LocalQueueList",
+	})
+
+	return &DefaultsController{
+		ctx:               ctx,
+		operatorClient:    operatorfake.NewSimpleClientset(kueue).KueueV1alpha1(),
+		dynamicClient:     dynamicClient,
+		namespaceLister:   kubeInformers.Core().V1().Namespaces().Lister(),
+		nodeLister:        kubeInformers.Core().V1().Nodes().Lister(),
+		eventRecorder:     events.NewInMemoryRecorder("test", clocktesting.NewFakePassiveClock(time.Now())),
+		operatorNamespace: namespace.GetNamespace(),
+	}, dynamicClient
+}
+
+func newTestNode(name, cpu, memory string, unschedulable bool) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       corev1.NodeSpec{Unschedulable: unschedulable},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse(cpu),
+				corev1.ResourceMemory: resource.MustParse(memory),
+			},
+		},
+	}
+}
+
+func TestDefaultsControllerCreatesQueues(t *testing.T) {
+	kueue := newTestKueue()
+	kueue.Spec.Defaults = &kueuev1alpha1.KueueDefaults{
+		NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "ml"}},
+	}
+	c, dynamicClient := newTestDefaultsController(t, kueue,
+		newTestNode("worker-0", "4", "16Gi", false),
+		newTestNode("worker-1", "2", "8Gi", false),
+		newTestNode("cordoned", "8", "32Gi", true),
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ml-a", Labels: map[string]string{"team": "ml"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "web", Labels: map[string]string{"team": "web"}}},
+	)
+
+	if err := c.sync(); err != nil {
+		t.Fatalf("Unexpected sync error: %v", err)
+	}
+
+	ctx := context.Background()
+	if _, err := dynamicClient.Resource(queues.ResourceFlavorsGVR).Get(ctx, "default-flavor", metav1.GetOptions{}); err != nil {
+		t.Errorf("Expected default ResourceFlavor: %v", err)
+	}
+	clusterQueue, err := dynamicClient.Resource(queues.ClusterQueuesGVR).Get(ctx, "cluster-queue", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Expected default ClusterQueue: %v", err)
+	}
+	quotas, _, err := unstructured.NestedSlice(clusterQueue.Object, "spec", "resourceGroups")
+	if err != nil || len(quotas) != 1 {
+		t.Fatalf("Unexpected resource groups: %v", quotas)
+	}
+	flavors := quotas[0].(map[string]interface{})["flavors"].([]interface{})
+	got := map[string]string{}
+	for _, r := range flavors[0].(map[string]interface{})["resources"].([]interface{}) {
+		r := r.(map[string]interface{})
+		got[r["name"].(string)] = r["nominalQuota"].(string)
+	}
+	if diff := cmp.Diff(map[string]string{"cpu": "6", "memory": "24Gi"}, got); len(diff) != 0 {
+		t.Errorf("Unexpected nominal quota (-want,+got):\n%s", diff)
+	}
+
+	localQueues, err := dynamicClient.Resource(queues.LocalQueuesGVR).Namespace("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(localQueues.Items) != 1 || localQueues.Items[0].GetNamespace() != "ml-a" || localQueues.Items[0].GetName() != "default" {
+		t.Errorf("Expected a single LocalQueue default in ml-a, got %v", localQueues.Items)
+	}
+}
+
+func TestDefaultsControllerKeepsUserChanges(t *testing.T) {
+	kueue := newTestKueue()
+	kueue.Spec.Defaults = &kueuev1alpha1.KueueDefaults{}
+	c, dynamicClient := newTestDefaultsController(t, kueue, newTestNode("worker-0", "4", "16Gi", false))
+
+	if err := c.sync(); err != nil {
+		t.Fatalf("Unexpected sync error: %v", err)
+	}
+
+	ctx := context.Background()
+	clusterQueues := dynamicClient.Resource(queues.ClusterQueuesGVR)
+	clusterQueue, err := clusterQueues.Get(ctx, "cluster-queue", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := unstructured.SetNestedField(clusterQueue.Object, "research", "spec", "cohort"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := clusterQueues.Update(ctx, clusterQueue, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := dynamicClient.Resource(queues.ResourceFlavorsGVR).Delete(ctx, "default-flavor", metav1.DeleteOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.sync(); err != nil {
+		t.Fatalf("Unexpected sync error: %v", err)
+	}
+	clusterQueue, err = clusterQueues.Get(ctx, "cluster-queue", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cohort, _, _ := unstructured.NestedString(clusterQueue.Object, "spec", "cohort"); cohort != "research" {
+		t.Errorf("Expected the user's cohort to be kept, got %q", cohort)
+	}
+	if _, err := dynamicClient.Resource(queues.ResourceFlavorsGVR).Get(ctx, "default-flavor", metav1.GetOptions{}); err != nil {
+		t.Errorf("Expected the deleted ResourceFlavor to be recreated: %v", err)
+	}
+}
+
+func TestDefaultsControllerReportsOutdatedObjects(t *testing.T) {
+	kueue := newTestKueue()
+	kueue.Spec.Defaults = &kueuev1alpha1.KueueDefaults{}
+	c, dynamicClient := newTestDefaultsController(t, kueue, newTestNode("worker-0", "4", "16Gi", false))
+
+	if err := c.sync(); err != nil {
+		t.Fatalf("Unexpected sync error: %v", err)
+	}
+	ctx := context.Background()
+	outdated := func() *operatorv1.OperatorCondition {
+		t.Helper()
+		kueue, err := c.operatorClient.Kueues(c.operatorNamespace).Get(ctx, kueue.Name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v1helpers.FindOperatorCondition(kueue.Status.Conditions, defaultsOutdatedCondition)
+	}
+	if condition := outdated(); condition == nil || condition.Status != operatorv1.ConditionFalse {
+		t.Fatalf("Expected %s to be false, got %v", defaultsOutdatedCondition, condition)
+	}
+
+	kueue, err := c.operatorClient.Kueues(c.operatorNamespace).Get(ctx, kueue.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	kueue.Spec.Defaults.CoveredResources = []corev1.ResourceName{corev1.ResourceCPU}
+	if _, err := c.operatorClient.Kueues(c.operatorNamespace).Update(ctx, kueue, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.sync(); err != nil {
+		t.Fatalf("Unexpected sync error: %v", err)
+	}
+
+	// Existing objects are not updated, only reported.
+	clusterQueue, err := dynamicClient.Resource(queues.ClusterQueuesGVR).Get(ctx, "cluster-queue", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resourceGroups, _, _ := unstructured.NestedSlice(clusterQueue.Object, "spec", "resourceGroups")
+	if len(resourceGroups) != 1 {
+		t.Fatalf("Unexpected resource groups: %v", resourceGroups)
+	}
+	if got := resourceGroups[0].(map[string]interface{})["coveredResources"].([]interface{}); len(got) != 2 {
+		t.Errorf("Expected the ClusterQueue to keep covering cpu and memory, got %v", got)
+	}
+	condition := outdated()
+	if condition == nil || condition.Status != operatorv1.ConditionTrue {
+		t.Fatalf("Expected %s to be true, got %v", defaultsOutdatedCondition, condition)
+	}
+	for _, name := range []string{"ClusterQueue cluster-queue", "ResourceFlavor default-flavor"} {
+		if !strings.Contains(condition.Message, name) {
+			t.Errorf("Expected %q in the message: %s", name, condition.Message)
+		}
+	}
+
+	// Deleting an outdated object recreates it from the current defaults.
+	if err := dynamicClient.Resource(queues.ClusterQueuesGVR).Delete(ctx, "cluster-queue", metav1.DeleteOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := dynamicClient.Resource(queues.ResourceFlavorsGVR).Delete(ctx, "default-flavor", metav1.DeleteOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.sync(); err != nil {
+		t.Fatalf("Unexpected sync error: %v", err)
+	}
+	if condition := outdated(); condition == nil || condition.Status != operatorv1.ConditionFalse {
+		t.Errorf("Expected %s to be false after recreating the objects, got %v", defaultsOutdatedCondition, condition)
+	}
+}
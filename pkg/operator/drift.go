@@ -0,0 +1,300 @@
+package operator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	v1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// serviceCAOriginAnnotation is set by the service-ca operator on the serving
+	// certificate secrets it writes.
+	serviceCAOriginAnnotation = "service.beta.openshift.io/originating-service-name"
+
+	// managedByLabel marks every object the operator applies, so that drift informers
+	// only cache operand objects.
+	managedByLabel = "app.kubernetes.io/managed-by"
+	managedByValue = "kueue-operator"
+	// controllerManagedValue marks the objects that the controllers next to the
+	// TargetConfigReconciler create from the Kueue CR. They are not operand objects and
+	// stay out of the drift informers.
+	controllerManagedValue = "kueue-operator-controllers"
+
+	driftResync = 10 * time.Minute
+)
+
+// setManagedByLabel adds the managed-by label to a required object.
+func setManagedByLabel(obj runtime.Object) {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return
+	}
+	labels := accessor.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[managedByLabel] = managedByValue
+	accessor.SetLabels(labels)
+}
+
+// setControllerManagedLabel adds the managed-by label of controller-managed objects to a
+// required object.
+func setControllerManagedLabel(obj metav1.Object) {
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[managedByLabel] = controllerManagedValue
+	obj.SetLabels(labels)
+}
+
+// appliedVersions remembers the resourceVersion the operator last wrote for each object,
+// so that informer events caused by our own applies are not reported as drift.
+type appliedVersions struct {
+	lock     sync.Mutex
+	versions map[string]string
+}
+
+func appliedVersionKey(obj metav1.Object) string {
+	return fmt.Sprintf("%T/%s/%s", obj, obj.GetNamespace(), obj.GetName())
+}
+
+func (a *appliedVersions) record(obj metav1.Object) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	if a.versions == nil {
+		a.versions = map[string]string{}
+	}
+	a.versions[appliedVersionKey(obj)] = obj.GetResourceVersion()
+}
+
+// deletedVersion is recorded for objects the operator deleted.
+const deletedVersion = "deleted"
+
+func (a *appliedVersions) recordDeletion(obj metav1.Object) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	if a.versions == nil {
+		a.versions = map[string]string{}
+	}
+	a.versions[appliedVersionKey(obj)] = deletedVersion
+}
+
+func (a *appliedVersions) isDeleted(obj metav1.Object) bool {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	return a.versions[appliedVersionKey(obj)] == deletedVersion
+}
+
+func (a *appliedVersions) isApplied(obj metav1.Object) bool {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	version, ok := a.versions[appliedVersionKey(obj)]
+	return ok && version == obj.GetResourceVersion()
+}
+
+// newDriftInformers creates informers for every kind the pipeline applies, restricted to
+// objects carrying the managed-by label, and routes their events to the drift handler.
+func (c *TargetConfigReconciler) newDriftInformers() error {
+	tweakListOptions := func(options *metav1.ListOptions) {
+		options.LabelSelector = managedByLabel + "=" + managedByValue
+	}
+	c.managedInformers = informers.NewSharedInformerFactoryWithOptions(c.kubeClient, driftResync, informers.WithTweakListOptions(tweakListOptions))
+
+	for resource, informer := range map[string]cache.SharedIndexInformer{
+		"configmaps":                      c.managedInformers.Core().V1().ConfigMaps().Informer(),
+		"serviceaccounts":                 c.managedInformers.Core().V1().ServiceAccounts().Informer(),
+		"secrets":                         c.managedInformers.Core().V1().Secrets().Informer(),
+		"services":                        c.managedInformers.Core().V1().Services().Informer(),
+		"roles":                           c.managedInformers.Rbac().V1().Roles().Informer(),
+		"rolebindings":                    c.managedInformers.Rbac().V1().RoleBindings().Informer(),
+		"clusterroles":                    c.managedInformers.Rbac().V1().ClusterRoles().Informer(),
+		"clusterrolebindings":             c.managedInformers.Rbac().V1().ClusterRoleBindings().Informer(),
+		"deployments":                     c.managedInformers.Apps().V1().Deployments().Informer(),
+		"mutatingwebhookconfigurations":   c.managedInformers.Admissionregistration().V1().MutatingWebhookConfigurations().Informer(),
+		"validatingwebhookconfigurations": c.managedInformers.Admissionregistration().V1().ValidatingWebhookConfigurations().Informer(),
+	} {
+		if _, err := informer.AddEventHandler(c.driftHandler(resource)); err != nil {
+			return err
+		}
+	}
+
+	// There is no generated informer factory for the apiextensions client, so the CRD
+	// informer is built directly on top of it.
+	c.crdInformer = cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				tweakListOptions(&options)
+				return c.crdClient.CustomResourceDefinitions().List(c.ctx, options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				tweakListOptions(&options)
+				return c.crdClient.CustomResourceDefinitions().Watch(c.ctx, options)
+			},
+		},
+		&apiextensionsv1.CustomResourceDefinition{},
+		driftResync,
+		cache.Indexers{},
+	)
+	_, err := c.crdInformer.AddEventHandler(c.driftHandler("customresourcedefinitions"))
+	return err
+}
+
+// startDriftInformers starts the drift informers and waits for their caches.
+func (c *TargetConfigReconciler) startDriftInformers(stopCh <-chan struct{}) bool {
+	c.managedInformers.Start(stopCh)
+	go c.crdInformer.Run(stopCh)
+
+	synced := []cache.InformerSynced{c.crdInformer.HasSynced}
+	for _, ok := range c.managedInformers.WaitForCacheSync(stopCh) {
+		if !ok {
+			return false
+		}
+	}
+	return cache.WaitForCacheSync(stopCh, synced...)
+}
+
+// driftHandler enqueues a reconcile when an operand object is changed or deleted by
+// someone other than the operator, and records an event naming what changed. Reapplying
+// only reverts the fields the operator sets, changes of other fields are kept. Objects
+// that no pipeline step applies are ignored, even when they carry the managed-by label.
+func (c *TargetConfigReconciler) driftHandler(resource string) cache.ResourceEventHandler {
+	return cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(old, new interface{}) {
+			newObj, err := meta.Accessor(new)
+			if err != nil {
+				klog.Errorf("Unable to convert %s obj to metav1.Object", resource)
+				return
+			}
+			if !isPipelineObject(resource, newObj) || c.appliedVersions.isApplied(newObj) {
+				return
+			}
+			fields, err := driftedFields(old.(runtime.Object), new.(runtime.Object))
+			if err != nil {
+				klog.ErrorS(err, "Unable to compare operand object", "resource", resource, "name", newObj.GetName())
+				return
+			}
+			if len(fields) == 0 {
+				return
+			}
+			c.eventRecorder.Warningf("OperandDrifted", "%s %s: changed %s outside of the operator, reapplying it", resource, objectName(newObj), strings.Join(fields, ", "))
+			c.queue.Add(queueItem{kind: resource, name: newObj.GetName()})
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			deleted, err := meta.Accessor(obj)
+			if err != nil {
+				klog.Errorf("Unable to convert %s obj to metav1.Object", resource)
+				return
+			}
+			if !isPipelineObject(resource, deleted) || c.appliedVersions.isDeleted(deleted) {
+				return
+			}
+			c.eventRecorder.Warningf("OperandDeleted", "%s %s was deleted, recreating it", resource, objectName(deleted))
+			c.queue.Add(queueItem{kind: resource, name: deleted.GetName()})
+		},
+	}
+}
+
+// isPipelineObject tells whether a step of the pipeline applies obj.
+func isPipelineObject(resource string, obj metav1.Object) bool {
+	_, ok := pipelineStepRoutes()[stepRoute{kind: resource, name: obj.GetName()}]
+	return ok
+}
+
+func objectName(obj metav1.Object) string {
+	if len(obj.GetNamespace()) == 0 {
+		return obj.GetName()
+	}
+	return obj.GetNamespace() + "/" + obj.GetName()
+}
+
+// driftedFields returns the paths of the fields that differ between two versions of an
+// operand object. Metadata, status and fields that other controllers inject on purpose
+// are not considered drift.
+func driftedFields(old, new runtime.Object) ([]string, error) {
+	oldContent, err := runtime.DefaultUnstructuredConverter.ToUnstructured(ignoreInjectedFields(old))
+	if err != nil {
+		return nil, err
+	}
+	newContent, err := runtime.DefaultUnstructuredConverter.ToUnstructured(ignoreInjectedFields(new))
+	if err != nil {
+		return nil, err
+	}
+	delete(oldContent, "metadata")
+	delete(newContent, "metadata")
+	delete(oldContent, "status")
+	delete(newContent, "status")
+
+	var fields []string
+	diffFields("", oldContent, newContent, &fields)
+	sort.Strings(fields)
+	return fields, nil
+}
+
+// ignoreInjectedFields returns a copy of obj without the fields owned by other
+// controllers, such as the serving certificates written by the service-ca operator.
+func ignoreInjectedFields(obj runtime.Object) runtime.Object {
+	obj = obj.DeepCopyObject()
+	switch t := obj.(type) {
+	case *v1.Secret:
+		if _, ok := t.Annotations[serviceCAOriginAnnotation]; ok {
+			delete(t.Data, v1.TLSCertKey)
+			delete(t.Data, v1.TLSPrivateKeyKey)
+		}
+	case *admissionregistrationv1.MutatingWebhookConfiguration:
+		for i := range t.Webhooks {
+			t.Webhooks[i].ClientConfig.CABundle = nil
+		}
+	case *admissionregistrationv1.ValidatingWebhookConfiguration:
+		for i := range t.Webhooks {
+			t.Webhooks[i].ClientConfig.CABundle = nil
+		}
+	case *apiextensionsv1.CustomResourceDefinition:
+		if t.Spec.Conversion != nil && t.Spec.Conversion.Webhook != nil && t.Spec.Conversion.Webhook.ClientConfig != nil {
+			t.Spec.Conversion.Webhook.ClientConfig.CABundle = nil
+		}
+	}
+	return obj
+}
+
+func diffFields(path string, old, new map[string]interface{}, fields *[]string) {
+	keys := map[string]struct{}{}
+	for key := range old {
+		keys[key] = struct{}{}
+	}
+	for key := range new {
+		keys[key] = struct{}{}
+	}
+	for key := range keys {
+		fieldPath := key
+		if len(path) > 0 {
+			fieldPath = path + "." + key
+		}
+		oldMap, oldIsMap := old[key].(map[string]interface{})
+		newMap, newIsMap := new[key].(map[string]interface{})
+		if oldIsMap && newIsMap {
+			diffFields(fieldPath, oldMap, newMap, fields)
+			continue
+		}
+		if !equality.Semantic.DeepEqual(old[key], new[key]) {
+			*fields = append(*fields, fieldPath)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package operator
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clocktesting "k8s.io/utils/clock/testing"
+	"k8s.io/utils/ptr"
+
+	kueuev1alpha1 "github.com/openshift/kueue-operator/pkg/apis/kueueoperator/v1alpha1"
+	"github.com/openshift/kueue-operator/pkg/namespace"
+	"github.com/openshift/kueue-operator/pkg/operator/operatorclient"
+	"github.com/openshift/library-go/pkg/operator/events"
+)
+
+func TestDriftedFields(t *testing.T) {
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "kueue", ResourceVersion: "1"},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: ptr.To[int32](1),
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "manager", Image: "kueue:v1"}}},
+			},
+		},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "kueue-webhook-server-cert", Annotations: map[string]string{serviceCAOriginAnnotation: "kueue-webhook-service"}},
+		Data:       map[string][]byte{corev1.TLSCertKey: []byte("crt"), corev1.TLSPrivateKeyKey: []byte("key")},
+	}
+	webhook := &admissionregistrationv1.MutatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "kueue"},
+		Webhooks:   []admissionregistrationv1.MutatingWebhook{{Name: "mjob.kb.io"}},
+	}
+
+	testCases := []struct {
+		name   string
+		old    runtime.Object
+		update func(obj runtime.Object)
+		want   []string
+	}{
+		{
+			name: "spec field",
+			old:  deployment,
+			update: func(obj runtime.Object) {
+				obj.(*appsv1.Deployment).Spec.Replicas = ptr.To[int32](3)
+			},
+			want: []string{"spec.replicas"},
+		},
+		{
+			name: "container list",
+			old:  deployment,
+			update: func(obj runtime.Object) {
+				obj.(*appsv1.Deployment).Spec.Template.Spec.Containers[0].Image = "kueue:v2"
+			},
+			want: []string{"spec.template.spec.containers"},
+		},
+		{
+			name: "metadata and status",
+			old:  deployment,
+			update: func(obj runtime.Object) {
+				d := obj.(*appsv1.Deployment)
+				d.ResourceVersion = "2"
+				d.Annotations = map[string]string{"deployment.kubernetes.io/revision": "2"}
+				d.Status.ReadyReplicas = 1
+			},
+		},
+		{
+			name: "certificate rotated by service-ca",
+			old:  secret,
+			update: func(obj runtime.Object) {
+				obj.(*corev1.Secret).Data[corev1.TLSCertKey] = []byte("rotated")
+			},
+		},
+		{
+			name: "other secret data",
+			old:  secret,
+			update: func(obj runtime.Object) {
+				obj.(*corev1.Secret).Data["ca.crt"] = []byte("ca")
+			},
+			want: []string{"data"},
+		},
+		{
+			name: "certificate not written by service-ca",
+			old: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "kueue-webhook-server-cert"},
+				Data:       map[string][]byte{corev1.TLSCertKey: []byte("crt")},
+			},
+			update: func(obj runtime.Object) {
+				obj.(*corev1.Secret).Data[corev1.TLSCertKey] = []byte("replaced")
+			},
+			want: []string{"data.tls.crt"},
+		},
+		{
+			name: "injected CA bundle",
+			old:  webhook,
+			update: func(obj runtime.Object) {
+				obj.(*admissionregistrationv1.MutatingWebhookConfiguration).Webhooks[0].ClientConfig.CABundle = []byte("ca")
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			updated := tc.old.DeepCopyObject()
+			tc.update(updated)
+			got, err := driftedFields(tc.old, updated)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want, got); len(diff) != 0 {
+				t.Errorf("Unexpected drifted fields (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDriftHandler(t *testing.T) {
+	ns := namespace.GetNamespace()
+	r := newTestReconciler(t, newTestKueue())
+
+	if err := r.sync(queueItem{kind: "kueue"}); err != nil {
+		t.Fatalf("Unexpected sync error: %v", err)
+	}
+	recorder := events.NewInMemoryRecorder("test", clocktesting.NewFakePassiveClock(metav1.Now().Time))
+	r.eventRecorder = recorder
+	deployment, err := r.kubeClient.AppsV1().Deployments(ns).Get(context.Background(), operatorclient.OperandName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deployment.Labels[managedByLabel] != managedByValue {
+		t.Errorf("Expected deployment to be labelled %s=%s, got %v", managedByLabel, managedByValue, deployment.Labels)
+	}
+
+	handler := r.driftHandler("deployments")
+
+	// An update the operator made itself is not drift.
+	handler.OnUpdate(deployment, deployment)
+	if r.queue.Len() != 0 || len(recorder.Events()) != 0 {
+		t.Fatalf("Unexpected reaction to the operator's own update")
+	}
+
+	edited := deployment.DeepCopy()
+	edited.ResourceVersion = "edited"
+	edited.Spec.Replicas = ptr.To[int32](5)
+	handler.OnUpdate(deployment, edited)
+	if r.queue.Len() != 1 {
+		t.Fatalf("Expected drift to enqueue a reconcile, queue length is %d", r.queue.Len())
+	}
+	if len(recorder.Events()) != 1 || !strings.Contains(recorder.Events()[0].Message, "changed spec.replicas outside of the operator, reapplying") {
+		t.Errorf("Expected an event naming spec.replicas, got %v", recorder.Events())
+	}
+}
+
+func TestDriftHandlerIgnoresControllerObjects(t *testing.T) {
+	r := newTestReconciler(t, newTestKueue())
+	recorder := events.NewInMemoryRecorder("test", clocktesting.NewFakePassiveClock(metav1.Now().Time))
+	r.eventRecorder = recorder
+
+	binding := buildQueueAccessRoleBinding("team-a", kueuev1alpha1.QueueAccess{Group: "ml-users", Role: kueuev1alpha1.QueueAccessRoleUser})
+	if binding.Labels[managedByLabel] == managedByValue {
+		t.Errorf("Expected the queue access RoleBinding to stay out of the drift informers, got labels %v", binding.Labels)
+	}
+
+	// Objects of the controllers labelled before the controller-managed label are not
+	// operand objects either.
+	binding.Labels[managedByLabel] = managedByValue
+	edited := binding.DeepCopy()
+	edited.ResourceVersion = "edited"
+	edited.Subjects[0].Name = "someone-else"
+	handler := r.driftHandler("rolebindings")
+	handler.OnUpdate(binding, edited)
+	handler.OnDelete(edited)
+	if r.queue.Len() != 0 || len(recorder.Events()) != 0 {
+		t.Errorf("Unexpected reaction to a RoleBinding no pipeline step applies: queue length %d, events %v", r.queue.Len(), recorder.Events())
+	}
+}
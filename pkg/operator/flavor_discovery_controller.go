@@ -0,0 +1,257 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	kueuev1alpha1 "github.com/openshift/kueue-operator/pkg/apis/kueueoperator/v1alpha1"
+	"github.com/openshift/kueue-operator/pkg/builders/queues"
+	kueueconfigclient "github.com/openshift/kueue-operator/pkg/generated/clientset/versioned/typed/kueueoperator/v1alpha1"
+	operatorclientinformers "github.com/openshift/kueue-operator/pkg/generated/informers/externalversions/kueueoperator/v1alpha1"
+	"github.com/openshift/kueue-operator/pkg/namespace"
+	"github.com/openshift/kueue-operator/pkg/operator/operatorclient"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/dynamic"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/klog/v2"
+	kueuev1beta1 "sigs.k8s.io/kueue/apis/kueue/v1beta1"
+)
+
+const (
+	// discoveredFlavorLabel marks the ResourceFlavors derived from node labels. Only these
+	// are updated and deleted by the controller.
+	discoveredFlavorLabel = "kueue.openshift.io/discovered-flavor"
+
+	flavorDiscoveryResyncInterval = 10 * time.Minute
+)
+
+var defaultFlavorDiscoveryLabels = []string{"node.kubernetes.io/instance-type", "nvidia.com/gpu.product"}
+
+// FlavorDiscoveryController keeps a ResourceFlavor for every pool of nodes that share the
+// values of the node labels listed in spec.flavorDiscovery of the Kueue CR. The nodeLabels
+// and nodeTaints of the flavors follow the nodes, and flavors of pools that no longer
+// have nodes are deleted. Kueue keeps a flavor that a ClusterQueue still references
+// until the reference is removed.
+type FlavorDiscoveryController struct {
+	ctx               context.Context
+	operatorClient    kueueconfigclient.KueueV1alpha1Interface
+	dynamicClient     dynamic.Interface
+	nodeLister        corev1listers.NodeLister
+	eventRecorder     events.Recorder
+	operatorNamespace string
+}
+
+func NewFlavorDiscoveryController(
+	ctx context.Context,
+	operatorConfigClient kueueconfigclient.KueueV1alpha1Interface,
+	operatorClientInformer operatorclientinformers.KueueInformer,
+	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
+	dynamicClient dynamic.Interface,
+	eventRecorder events.Recorder,
+) (factory.Controller, error) {
+	nodeInformer := kubeInformersForNamespaces.InformersFor("").Core().V1().Nodes()
+	c := &FlavorDiscoveryController{
+		ctx:               ctx,
+		operatorClient:    operatorConfigClient,
+		dynamicClient:     dynamicClient,
+		nodeLister:        nodeInformer.Lister(),
+		eventRecorder:     eventRecorder.WithComponentSuffix("flavor-discovery-controller"),
+		operatorNamespace: namespace.GetNamespace(),
+	}
+
+	syncCtx := factory.NewSyncContext("FlavorDiscoveryController", eventRecorder)
+	if _, err := operatorClientInformer.Informer().AddEventHandler(queueHandler(syncCtx, kueueSpecChanged)); err != nil {
+		return nil, err
+	}
+	// Node status changes every few seconds, only labels and taints affect the flavors.
+	if _, err := nodeInformer.Informer().AddEventHandler(queueHandler(syncCtx, func(old, new interface{}) bool {
+		oldNode, ok := old.(*corev1.Node)
+		if !ok {
+			return false
+		}
+		newNode, ok := new.(*corev1.Node)
+		if !ok {
+			return false
+		}
+		return !equality.Semantic.DeepEqual(oldNode.Labels, newNode.Labels) || !equality.Semantic.DeepEqual(oldNode.Spec.Taints, newNode.Spec.Taints)
+	})); err != nil {
+		return nil, err
+	}
+
+	return factory.New().
+		WithSyncContext(syncCtx).
+		WithBareInformers(operatorClientInformer.Informer(), nodeInformer.Informer()).
+		WithSync(func(ctx context.Context, _ factory.SyncContext) error { return c.sync() }).
+		// Restore flavors that were edited or deleted, nothing watches them.
+		ResyncEvery(flavorDiscoveryResyncInterval).
+		ToController("FlavorDiscoveryController", c.eventRecorder), nil
+}
+
+func (c *FlavorDiscoveryController) sync() error {
+	kueue, err := c.operatorClient.Kueues(c.operatorNamespace).Get(c.ctx, operatorclient.OperatorConfigName, metav1.GetOptions{})
+	if err != nil {
+		klog.ErrorS(err, "unable to get operator configuration", "namespace", c.operatorNamespace, "kueue", operatorclient.OperatorConfigName)
+		return err
+	}
+
+	pools, err := c.nodePools(kueue.Spec.FlavorDiscovery)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	discovered := make([]kueuev1alpha1.DiscoveredFlavor, 0, len(pools))
+	wanted := sets.New[string]()
+	for _, pool := range pools {
+		wanted.Insert(pool.Name)
+		owned, err := c.applyFlavor(queues.BuildNodePoolResourceFlavor(pool))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if !owned {
+			continue
+		}
+		discovered = append(discovered, kueuev1alpha1.DiscoveredFlavor{
+			Name:       pool.Name,
+			NodeLabels: pool.NodeLabels,
+			NodeTaints: pool.NodeTaints,
+			Nodes:      int32(len(pool.Nodes)),
+		})
+	}
+
+	client := c.dynamicClient.Resource(queues.ResourceFlavorsGVR)
+	existing, err := client.List(c.ctx, metav1.ListOptions{LabelSelector: discoveredFlavorLabel})
+	if err != nil {
+		errs = append(errs, err)
+	} else {
+		for _, flavor := range existing.Items {
+			if wanted.Has(flavor.GetName()) || flavor.GetDeletionTimestamp() != nil {
+				continue
+			}
+			if err := client.Delete(c.ctx, flavor.GetName(), metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+				errs = append(errs, fmt.Errorf("unable to delete ResourceFlavor %s: %w", flavor.GetName(), err))
+				continue
+			}
+			c.eventRecorder.Eventf("ResourceFlavorDeleted", "Deleted ResourceFlavor %s, no node has its labels anymore", flavor.GetName())
+		}
+	}
+
+	degraded := operatorv1.OperatorCondition{
+		Type:   "FlavorDiscoveryControllerDegraded",
+		Status: operatorv1.ConditionFalse,
+		Reason: "AsExpected",
+	}
+	if len(errs) > 0 {
+		degraded.Status = operatorv1.ConditionTrue
+		degraded.Reason = "SyncFailed"
+		degraded.Message = utilerrors.NewAggregate(errs).Error()
+	}
+	if err := updateKueueStatus(c.ctx, c.operatorClient, c.operatorNamespace, kueue.Name, func(status *kueuev1alpha1.KueueStatus) {
+		status.DiscoveredFlavors = discovered
+		if len(discovered) == 0 {
+			status.DiscoveredFlavors = nil
+		}
+		v1helpers.SetOperatorCondition(&status.Conditions, degraded)
+	}); err != nil {
+		errs = append(errs, err)
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+// nodePools groups the selected nodes by the configured labels. There are no pools when
+// discovery is turned off, so that every discovered flavor is deleted.
+func (c *FlavorDiscoveryController) nodePools(discovery *kueuev1alpha1.FlavorDiscovery) ([]queues.NodePool, error) {
+	if discovery == nil {
+		return nil, nil
+	}
+	selector := labels.Everything()
+	if discovery.NodeSelector != nil {
+		var err error
+		selector, err = metav1.LabelSelectorAsSelector(discovery.NodeSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid nodeSelector: %w", err)
+		}
+	}
+	nodes, err := c.nodeLister.List(selector)
+	if err != nil {
+		return nil, err
+	}
+	labelKeys := discovery.NodeLabels
+	if len(labelKeys) == 0 {
+		labelKeys = defaultFlavorDiscoveryLabels
+	}
+	return queues.NodePools(nodes, labelKeys), nil
+}
+
+// applyFlavor creates the flavor or updates the nodeLabels and nodeTaints of a flavor it
+// created before. It returns false when a flavor of the same name was created by someone
+// else, which is left alone.
+func (c *FlavorDiscoveryController) applyFlavor(flavor *kueuev1beta1.ResourceFlavor) (bool, error) {
+	flavor.Labels = map[string]string{discoveredFlavorLabel: "true"}
+	client := c.dynamicClient.Resource(queues.ResourceFlavorsGVR)
+	existing, err := client.Get(c.ctx, flavor.Name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return true, createIfMissing(c.ctx, c.dynamicClient, c.eventRecorder, queues.ResourceFlavorsGVR, flavor)
+	}
+	if err != nil {
+		return false, err
+	}
+	if _, ok := existing.GetLabels()[discoveredFlavorLabel]; !ok {
+		klog.InfoS("Not updating ResourceFlavor that was not discovered by the operator", "resourceFlavor", flavor.Name)
+		return false, nil
+	}
+
+	current := &kueuev1beta1.ResourceFlavor{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(existing.Object, current); err != nil {
+		return false, err
+	}
+	if equality.Semantic.DeepEqual(current.Spec.NodeLabels, flavor.Spec.NodeLabels) &&
+		equality.Semantic.DeepEqual(current.Spec.NodeTaints, flavor.Spec.NodeTaints) {
+		return true, nil
+	}
+	if current.Spec.TopologyName != nil {
+		// The spec of a flavor that uses a topology is immutable.
+		klog.InfoS("Not updating ResourceFlavor that uses a topology", "resourceFlavor", flavor.Name, "topology", *current.Spec.TopologyName)
+		return true, nil
+	}
+
+	updated := existing.DeepCopy()
+	if err := unstructured.SetNestedStringMap(updated.Object, flavor.Spec.NodeLabels, "spec", "nodeLabels"); err != nil {
+		return false, err
+	}
+	if len(flavor.Spec.NodeTaints) == 0 {
+		unstructured.RemoveNestedField(updated.Object, "spec", "nodeTaints")
+	} else {
+		taints := make([]interface{}, 0, len(flavor.Spec.NodeTaints))
+		for i := range flavor.Spec.NodeTaints {
+			taint, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&flavor.Spec.NodeTaints[i])
+			if err != nil {
+				return false, err
+			}
+			taints = append(taints, taint)
+		}
+		if err := unstructured.SetNestedSlice(updated.Object, taints, "spec", "nodeTaints"); err != nil {
+			return false, err
+		}
+	}
+	if _, err := client.Update(c.ctx, updated, metav1.UpdateOptions{FieldManager: fieldManager}); err != nil {
+		return false, fmt.Errorf("unable to update ResourceFlavor %s: %w", flavor.Name, err)
+	}
+	c.eventRecorder.Eventf("ResourceFlavorUpdated", "Updated the node labels and taints of ResourceFlavor %s", flavor.Name)
+	return true, nil
+}
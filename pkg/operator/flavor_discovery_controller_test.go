@@ -0,0 +1,147 @@
+package operator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	kueuev1alpha1 "github.com/openshift/kueue-operator/pkg/apis/kueueoperator/v1alpha1"
+	"github.com/openshift/kueue-operator/pkg/builders/queues"
+	"github.com/openshift/kueue-operator/pkg/namespace"
+	"github.com/openshift/kueue-operator/pkg/operator/operatorclient"
+)
+
+func newTestFlavorDiscoveryController(t *testing.T, kueue *kueuev1alpha1.Kueue, nodes ...*corev1.Node) (*FlavorDiscoveryController, cache.Indexer) {
+	t.Helper()
+	defaults, _ := newTestDefaultsController(t, kueue)
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, node := range nodes {
+		if err := indexer.Add(node); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return &FlavorDiscoveryController{
+		ctx:               defaults.ctx,
+		operatorClient:    defaults.operatorClient,
+		dynamicClient:     defaults.dynamicClient,
+		nodeLister:        corev1listers.NewNodeLister(indexer),
+		eventRecorder:     defaults.eventRecorder,
+		operatorNamespace: defaults.operatorNamespace,
+	}, indexer
+}
+
+func labelledNode(name string, labels map[string]string, taints ...corev1.Taint) *corev1.Node {
+	node := newTestNode(name, "8", "32Gi", false)
+	node.Labels = labels
+	node.Spec.Taints = taints
+	return node
+}
+
+func (c *FlavorDiscoveryController) resourceFlavors(t *testing.T) map[string]map[string]interface{} {
+	t.Helper()
+	flavors, err := c.dynamicClient.Resource(queues.ResourceFlavorsGVR).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	specs := map[string]map[string]interface{}{}
+	for _, flavor := range flavors.Items {
+		spec, _, _ := unstructured.NestedMap(flavor.Object, "spec")
+		specs[flavor.GetName()] = spec
+	}
+	return specs
+}
+
+func TestFlavorDiscoveryController(t *testing.T) {
+	gpuTaint := corev1.Taint{Key: "nvidia.com/gpu", Effect: corev1.TaintEffectNoSchedule}
+	kueue := newTestKueue()
+	kueue.Spec.FlavorDiscovery = &kueuev1alpha1.FlavorDiscovery{}
+	c, indexer := newTestFlavorDiscoveryController(t, kueue,
+		labelledNode("cpu-0", map[string]string{"node.kubernetes.io/instance-type": "m5.xlarge"}),
+		labelledNode("gpu-0", map[string]string{"node.kubernetes.io/instance-type": "g5.xlarge", "nvidia.com/gpu.product": "NVIDIA-A10G"}, gpuTaint),
+		labelledNode("gpu-1", map[string]string{"node.kubernetes.io/instance-type": "g5.xlarge", "nvidia.com/gpu.product": "NVIDIA-A10G"}, gpuTaint),
+	)
+
+	// A flavor an admin created with the same name is not taken over.
+	if err := createIfMissing(c.ctx, c.dynamicClient, c.eventRecorder, queues.ResourceFlavorsGVR, queues.BuildResourceFlavor("m5.xlarge")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.sync(); err != nil {
+		t.Fatalf("Unexpected sync error: %v", err)
+	}
+
+	want := map[string]map[string]interface{}{
+		"m5.xlarge": {},
+		"g5.xlarge-nvidia-a10g": {
+			"nodeLabels": map[string]interface{}{
+				"node.kubernetes.io/instance-type": "g5.xlarge",
+				"nvidia.com/gpu.product":           "NVIDIA-A10G",
+			},
+			"nodeTaints": []interface{}{
+				map[string]interface{}{"key": "nvidia.com/gpu", "effect": "NoSchedule"},
+			},
+		},
+	}
+	if diff := cmp.Diff(want, c.resourceFlavors(t)); len(diff) != 0 {
+		t.Errorf("Unexpected ResourceFlavors (-want,+got):\n%s", diff)
+	}
+	kueue, err := c.operatorClient.Kueues(namespace.GetNamespace()).Get(c.ctx, operatorclient.OperatorConfigName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]kueuev1alpha1.DiscoveredFlavor{{
+		Name:       "g5.xlarge-nvidia-a10g",
+		NodeLabels: map[string]string{"node.kubernetes.io/instance-type": "g5.xlarge", "nvidia.com/gpu.product": "NVIDIA-A10G"},
+		NodeTaints: []corev1.Taint{gpuTaint},
+		Nodes:      2,
+	}}, kueue.Status.DiscoveredFlavors); len(diff) != 0 {
+		t.Errorf("Unexpected status.discoveredFlavors (-want,+got):\n%s", diff)
+	}
+
+	// gpu-1 leaves, gpu-0 loses its taint and a node of a new pool joins.
+	if err := indexer.Update(labelledNode("gpu-0", map[string]string{"node.kubernetes.io/instance-type": "g5.xlarge", "nvidia.com/gpu.product": "NVIDIA-A10G"})); err != nil {
+		t.Fatal(err)
+	}
+	if err := indexer.Delete(labelledNode("gpu-1", nil)); err != nil {
+		t.Fatal(err)
+	}
+	if err := indexer.Add(labelledNode("gpu-2", map[string]string{"node.kubernetes.io/instance-type": "p4d.24xlarge", "nvidia.com/gpu.product": "NVIDIA-A100-SXM4-40GB"})); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.sync(); err != nil {
+		t.Fatalf("Unexpected sync error: %v", err)
+	}
+	want["g5.xlarge-nvidia-a10g"] = map[string]interface{}{
+		"nodeLabels": map[string]interface{}{
+			"node.kubernetes.io/instance-type": "g5.xlarge",
+			"nvidia.com/gpu.product":           "NVIDIA-A10G",
+		},
+	}
+	want["p4d.24xlarge-nvidia-a100-sxm4-40gb"] = map[string]interface{}{
+		"nodeLabels": map[string]interface{}{
+			"node.kubernetes.io/instance-type": "p4d.24xlarge",
+			"nvidia.com/gpu.product":           "NVIDIA-A100-SXM4-40GB",
+		},
+	}
+	if diff := cmp.Diff(want, c.resourceFlavors(t)); len(diff) != 0 {
+		t.Errorf("Unexpected ResourceFlavors (-want,+got):\n%s", diff)
+	}
+
+	// Turning discovery off deletes the discovered flavors only.
+	kueue.Spec.FlavorDiscovery = nil
+	if _, err := c.operatorClient.Kueues(namespace.GetNamespace()).Update(c.ctx, kueue, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.sync(); err != nil {
+		t.Fatalf("Unexpected sync error: %v", err)
+	}
+	if diff := cmp.Diff(map[string]map[string]interface{}{"m5.xlarge": {}}, c.resourceFlavors(t)); len(diff) != 0 {
+		t.Errorf("Unexpected ResourceFlavors (-want,+got):\n%s", diff)
+	}
+}
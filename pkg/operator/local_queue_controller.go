@@ -0,0 +1,267 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	kueuev1alpha1 "github.com/openshift/kueue-operator/pkg/apis/kueueoperator/v1alpha1"
+	"github.com/openshift/kueue-operator/pkg/builders/queues"
+	kueueconfigclient "github.com/openshift/kueue-operator/pkg/generated/clientset/versioned/typed/kueueoperator/v1alpha1"
+	operatorclientinformers "github.com/openshift/kueue-operator/pkg/generated/informers/externalversions/kueueoperator/v1alpha1"
+	"github.com/openshift/kueue-operator/pkg/namespace"
+	"github.com/openshift/kueue-operator/pkg/operator/operatorclient"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+const (
+	defaultQueueLabelKey = "kueue.openshift.io/queue"
+
+	// provisionedLocalQueueLabel marks the LocalQueues created for labelled namespaces,
+	// so that they can be garbage collected without touching LocalQueues created by users.
+	provisionedLocalQueueLabel = "kueue.openshift.io/provisioned-for-namespace-label"
+
+	// defaultProvisionedLocalQueueName differs from the LocalQueue name of spec.defaults,
+	// so that both can be enabled without competing for the same LocalQueue.
+	defaultProvisionedLocalQueueName = "namespace-queue"
+
+	// localQueueNameConflictCondition is true while namespaces hold a LocalQueue of the
+	// provisioned name that the operator did not provision.
+	localQueueNameConflictCondition = "LocalQueueNameConflict"
+
+	localQueueResyncInterval = 10 * time.Minute
+)
+
+// LocalQueueController keeps a LocalQueue in every namespace labelled with the name of a
+// ClusterQueue, as configured by spec.localQueueProvisioning of the Kueue CR. LocalQueues
+// of namespaces that lose the label, or of all namespaces when provisioning is turned
+// off, are deleted. Since the ClusterQueue of a LocalQueue is immutable, relabelling a
+// namespace replaces its LocalQueue.
+type LocalQueueController struct {
+	ctx             context.Context
+	operatorClient  kueueconfigclient.KueueV1alpha1Interface
+	dynamicClient   dynamic.Interface
+	namespaceLister corev1listers.NamespaceLister
+	// localQueueLister only caches the LocalQueues provisioned by the controller.
+	localQueueLister  cache.GenericLister
+	localQueuesSynced cache.InformerSynced
+	eventRecorder     events.Recorder
+	operatorNamespace string
+	// conflicts holds the namespaces whose LocalQueue name conflict was already reported.
+	conflicts sets.Set[string]
+}
+
+func NewLocalQueueController(
+	ctx context.Context,
+	operatorConfigClient kueueconfigclient.KueueV1alpha1Interface,
+	operatorClientInformer operatorclientinformers.KueueInformer,
+	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
+	dynamicClient dynamic.Interface,
+	eventRecorder events.Recorder,
+) (factory.Controller, error) {
+	namespaceInformer := kubeInformersForNamespaces.InformersFor("").Core().V1().Namespaces()
+	localQueueInformer := dynamicinformer.NewFilteredDynamicSharedInformerFactory(dynamicClient, localQueueResyncInterval, metav1.NamespaceAll, func(options *metav1.ListOptions) {
+		options.LabelSelector = provisionedLocalQueueLabel
+	})
+	localQueues := localQueueInformer.ForResource(queues.LocalQueuesGVR)
+	c := &LocalQueueController{
+		ctx:               ctx,
+		operatorClient:    operatorConfigClient,
+		dynamicClient:     dynamicClient,
+		namespaceLister:   namespaceInformer.Lister(),
+		localQueueLister:  localQueues.Lister(),
+		localQueuesSynced: localQueues.Informer().HasSynced,
+		eventRecorder:     eventRecorder.WithComponentSuffix("local-queue-controller"),
+		operatorNamespace: namespace.GetNamespace(),
+	}
+
+	syncCtx := factory.NewSyncContext("LocalQueueController", eventRecorder)
+	if _, err := operatorClientInformer.Informer().AddEventHandler(queueHandler(syncCtx, kueueSpecChanged)); err != nil {
+		return nil, err
+	}
+	if _, err := localQueues.Informer().AddEventHandler(queueHandler(syncCtx, nil)); err != nil {
+		return nil, err
+	}
+
+	return factory.New().
+		WithSyncContext(syncCtx).
+		WithInformers(namespaceInformer.Informer()).
+		WithBareInformers(operatorClientInformer.Informer()).
+		WithPostStartHooks(startDynamicInformers(localQueueInformer)).
+		WithSync(func(ctx context.Context, _ factory.SyncContext) error { return c.sync() }).
+		ToController("LocalQueueController", c.eventRecorder), nil
+}
+
+func (c *LocalQueueController) sync() error {
+	if !c.localQueuesSynced() {
+		// The post start hook queues a sync once the LocalQueues are cached.
+		return nil
+	}
+
+	kueue, err := c.operatorClient.Kueues(c.operatorNamespace).Get(c.ctx, operatorclient.OperatorConfigName, metav1.GetOptions{})
+	if err != nil {
+		klog.ErrorS(err, "unable to get operator configuration", "namespace", c.operatorNamespace, "kueue", operatorclient.OperatorConfigName)
+		return err
+	}
+
+	desired, err := c.desiredLocalQueues(kueue.Spec.LocalQueueProvisioning)
+	if err != nil {
+		return err
+	}
+
+	client := c.dynamicClient.Resource(queues.LocalQueuesGVR)
+	existing, err := c.localQueueLister.List(labels.Everything())
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	current := map[string]kueuev1alpha1.LocalQueueMapping{}
+	conflicts := sets.New[string]()
+	for _, obj := range existing {
+		localQueue, ok := obj.(*unstructured.Unstructured)
+		if !ok {
+			continue
+		}
+		clusterQueue, _, _ := unstructured.NestedString(localQueue.Object, "spec", "clusterQueue")
+		want, ok := desired[localQueue.GetNamespace()]
+		if ok && want.Name == localQueue.GetName() && want.ClusterQueue == clusterQueue {
+			current[localQueue.GetNamespace()] = want
+			continue
+		}
+		if err := client.Namespace(localQueue.GetNamespace()).Delete(c.ctx, localQueue.GetName(), metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+			errs = append(errs, fmt.Errorf("unable to delete LocalQueue %s: %w", objectName(localQueue), err))
+			continue
+		}
+		c.eventRecorder.Eventf("LocalQueueDeleted", "Deleted LocalQueue %s of ClusterQueue %s", objectName(localQueue), clusterQueue)
+	}
+
+	for ns, want := range desired {
+		if _, ok := current[ns]; ok {
+			continue
+		}
+		localQueue := queues.BuildLocalQueue(ns, want.Name, want.ClusterQueue)
+		localQueue.Labels = map[string]string{provisionedLocalQueueLabel: "true"}
+		if err := createIfMissing(c.ctx, c.dynamicClient, c.eventRecorder, queues.LocalQueuesGVR, localQueue); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		// A LocalQueue of the same name created by a user or by spec.defaults is left
+		// alone and reported.
+		created, err := client.Namespace(ns).Get(c.ctx, want.Name, metav1.GetOptions{})
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if _, ok := created.GetLabels()[provisionedLocalQueueLabel]; ok {
+			current[ns] = want
+			continue
+		}
+		conflicts.Insert(ns)
+		if !c.conflicts.Has(ns) {
+			c.eventRecorder.Warningf("LocalQueueNameConflict", "Namespace %s already has a LocalQueue %s that was not provisioned for its label", ns, want.Name)
+		}
+	}
+	c.conflicts = conflicts
+
+	mappings := make([]kueuev1alpha1.LocalQueueMapping, 0, len(current))
+	for _, mapping := range current {
+		mappings = append(mappings, mapping)
+	}
+	sort.Slice(mappings, func(i, j int) bool { return mappings[i].Namespace < mappings[j].Namespace })
+
+	degraded := operatorv1.OperatorCondition{
+		Type:   "LocalQueueControllerDegraded",
+		Status: operatorv1.ConditionFalse,
+		Reason: "AsExpected",
+	}
+	if len(errs) > 0 {
+		degraded.Status = operatorv1.ConditionTrue
+		degraded.Reason = "SyncFailed"
+		degraded.Message = utilerrors.NewAggregate(errs).Error()
+	}
+	conflict := operatorv1.OperatorCondition{
+		Type:   localQueueNameConflictCondition,
+		Status: operatorv1.ConditionFalse,
+		Reason: "AsExpected",
+	}
+	if conflicts.Len() > 0 {
+		conflict.Status = operatorv1.ConditionTrue
+		conflict.Reason = "LocalQueueExists"
+		conflict.Message = fmt.Sprintf("namespaces %s already have a LocalQueue that was not provisioned for their label, set spec.localQueueProvisioning.localQueueName to another name",
+			strings.Join(sets.List(conflicts), ", "))
+	}
+	if err := updateKueueStatus(c.ctx, c.operatorClient, c.operatorNamespace, kueue.Name, func(status *kueuev1alpha1.KueueStatus) {
+		status.LocalQueues = mappings
+		if len(mappings) == 0 {
+			status.LocalQueues = nil
+		}
+		v1helpers.SetOperatorCondition(&status.Conditions, degraded)
+		v1helpers.SetOperatorCondition(&status.Conditions, conflict)
+	}); err != nil {
+		errs = append(errs, err)
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+// desiredLocalQueues returns the LocalQueue every labelled namespace should have, keyed by
+// namespace. It is empty when provisioning is turned off.
+func (c *LocalQueueController) desiredLocalQueues(provisioning *kueuev1alpha1.LocalQueueProvisioning) (map[string]kueuev1alpha1.LocalQueueMapping, error) {
+	desired := map[string]kueuev1alpha1.LocalQueueMapping{}
+	if provisioning == nil {
+		return desired, nil
+	}
+	labelKey := provisioning.LabelKey
+	if len(labelKey) == 0 {
+		labelKey = defaultQueueLabelKey
+	}
+	localQueueName := provisioning.LocalQueueName
+	if len(localQueueName) == 0 {
+		localQueueName = defaultProvisionedLocalQueueName
+	}
+
+	requirement, err := labels.NewRequirement(labelKey, selection.Exists, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid labelKey %q: %w", labelKey, err)
+	}
+	namespaces, err := c.namespaceLister.List(labels.NewSelector().Add(*requirement))
+	if err != nil {
+		return nil, err
+	}
+	for _, ns := range namespaces {
+		if ns.Status.Phase == corev1.NamespaceTerminating {
+			continue
+		}
+		clusterQueue := ns.Labels[labelKey]
+		if msgs := validation.IsDNS1123Subdomain(clusterQueue); len(msgs) > 0 {
+			klog.InfoS("Ignoring namespace label that is not a ClusterQueue name", "namespace", ns.Name, "label", labelKey, "value", clusterQueue)
+			continue
+		}
+		desired[ns.Name] = kueuev1alpha1.LocalQueueMapping{
+			Namespace:    ns.Name,
+			Name:         localQueueName,
+			ClusterQueue: clusterQueue,
+		}
+	}
+	return desired, nil
+}
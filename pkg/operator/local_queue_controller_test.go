@@ -0,0 +1,238 @@
+package operator
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	kueuev1alpha1 "github.com/openshift/kueue-operator/pkg/apis/kueueoperator/v1alpha1"
+	"github.com/openshift/kueue-operator/pkg/builders/queues"
+	"github.com/openshift/kueue-operator/pkg/namespace"
+	"github.com/openshift/kueue-operator/pkg/operator/operatorclient"
+)
+
+func newTestLocalQueueController(t *testing.T, kueue *kueuev1alpha1.Kueue, namespaces ...*corev1.Namespace) (*LocalQueueController, cache.Indexer) {
+	t.Helper()
+	defaults, _ := newTestDefaultsController(t, kueue)
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, ns := range namespaces {
+		if err := indexer.Add(ns); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return &LocalQueueController{
+		ctx:               defaults.ctx,
+		operatorClient:    defaults.operatorClient,
+		dynamicClient:     defaults.dynamicClient,
+		namespaceLister:   corev1listers.NewNamespaceLister(indexer),
+		localQueueLister:  provisionedLocalQueueLister{client: defaults.dynamicClient.Resource(queues.LocalQueuesGVR)},
+		localQueuesSynced: func() bool { return true },
+		eventRecorder:     defaults.eventRecorder,
+		operatorNamespace: defaults.operatorNamespace,
+	}, indexer
+}
+
+// provisionedLocalQueueLister reads through to the fake dynamic client, so that a sync
+// sees the LocalQueues created by the previous one without waiting for an informer.
+type provisionedLocalQueueLister struct {
+	cache.GenericLister
+	client dynamic.NamespaceableResourceInterface
+}
+
+func (l provisionedLocalQueueLister) List(selector labels.Selector) ([]runtime.Object, error) {
+	list, err := l.client.Namespace(metav1.NamespaceAll).List(context.Background(), metav1.ListOptions{LabelSelector: provisionedLocalQueueLabel})
+	if err != nil {
+		return nil, err
+	}
+	var objects []runtime.Object
+	for i := range list.Items {
+		if selector.Matches(labels.Set(list.Items[i].GetLabels())) {
+			objects = append(objects, &list.Items[i])
+		}
+	}
+	return objects, nil
+}
+
+func labelledNamespace(name, clusterQueue string) *corev1.Namespace {
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	if len(clusterQueue) > 0 {
+		ns.Labels = map[string]string{defaultQueueLabelKey: clusterQueue}
+	}
+	return ns
+}
+
+func (c *LocalQueueController) localQueueMappings(t *testing.T) (fromObjects, fromStatus []kueuev1alpha1.LocalQueueMapping) {
+	t.Helper()
+	ctx := context.Background()
+	localQueues, err := c.dynamicClient.Resource(queues.LocalQueuesGVR).Namespace("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, lq := range localQueues.Items {
+		clusterQueue, _, _ := unstructured.NestedString(lq.Object, "spec", "clusterQueue")
+		fromObjects = append(fromObjects, kueuev1alpha1.LocalQueueMapping{Namespace: lq.GetNamespace(), Name: lq.GetName(), ClusterQueue: clusterQueue})
+	}
+	kueue, err := c.operatorClient.Kueues(namespace.GetNamespace()).Get(ctx, operatorclient.OperatorConfigName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fromObjects, kueue.Status.LocalQueues
+}
+
+func TestLocalQueueControllerProvisionsLabelledNamespaces(t *testing.T) {
+	kueue := newTestKueue()
+	kueue.Spec.LocalQueueProvisioning = &kueuev1alpha1.LocalQueueProvisioning{}
+	c, _ := newTestLocalQueueController(t, kueue,
+		labelledNamespace("team-a", "gpu-queue"),
+		labelledNamespace("team-b", "cpu-queue"),
+		labelledNamespace("unlabelled", ""),
+	)
+
+	if err := c.sync(); err != nil {
+		t.Fatalf("Unexpected sync error: %v", err)
+	}
+
+	want := []kueuev1alpha1.LocalQueueMapping{
+		{Namespace: "team-a", Name: "namespace-queue", ClusterQueue: "gpu-queue"},
+		{Namespace: "team-b", Name: "namespace-queue", ClusterQueue: "cpu-queue"},
+	}
+	objects, status := c.localQueueMappings(t)
+	if diff := cmp.Diff(want, objects); len(diff) != 0 {
+		t.Errorf("Unexpected LocalQueues (-want,+got):\n%s", diff)
+	}
+	if diff := cmp.Diff(want, status); len(diff) != 0 {
+		t.Errorf("Unexpected status.localQueues (-want,+got):\n%s", diff)
+	}
+}
+
+func TestLocalQueueControllerFollowsLabelChanges(t *testing.T) {
+	kueue := newTestKueue()
+	kueue.Spec.LocalQueueProvisioning = &kueuev1alpha1.LocalQueueProvisioning{}
+	c, indexer := newTestLocalQueueController(t, kueue,
+		labelledNamespace("team-a", "gpu-queue"),
+		labelledNamespace("team-b", "cpu-queue"),
+	)
+	if err := c.sync(); err != nil {
+		t.Fatalf("Unexpected sync error: %v", err)
+	}
+
+	// A LocalQueue created by a user is never garbage collected.
+	userQueue := queues.BuildLocalQueue("team-c", "mine", "cpu-queue")
+	if err := createIfMissing(c.ctx, c.dynamicClient, c.eventRecorder, queues.LocalQueuesGVR, userQueue); err != nil {
+		t.Fatal(err)
+	}
+
+	// team-a moves to another ClusterQueue and team-b drops the label.
+	if err := indexer.Update(labelledNamespace("team-a", "cpu-queue")); err != nil {
+		t.Fatal(err)
+	}
+	if err := indexer.Update(labelledNamespace("team-b", "")); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.sync(); err != nil {
+		t.Fatalf("Unexpected sync error: %v", err)
+	}
+
+	objects, status := c.localQueueMappings(t)
+	if diff := cmp.Diff([]kueuev1alpha1.LocalQueueMapping{
+		{Namespace: "team-a", Name: "namespace-queue", ClusterQueue: "cpu-queue"},
+		{Namespace: "team-c", Name: "mine", ClusterQueue: "cpu-queue"},
+	}, objects); len(diff) != 0 {
+		t.Errorf("Unexpected LocalQueues (-want,+got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]kueuev1alpha1.LocalQueueMapping{
+		{Namespace: "team-a", Name: "namespace-queue", ClusterQueue: "cpu-queue"},
+	}, status); len(diff) != 0 {
+		t.Errorf("Unexpected status.localQueues (-want,+got):\n%s", diff)
+	}
+
+	// Turning provisioning off removes every provisioned LocalQueue.
+	kueue, err := c.operatorClient.Kueues(namespace.GetNamespace()).Get(c.ctx, operatorclient.OperatorConfigName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	kueue.Spec.LocalQueueProvisioning = nil
+	if _, err := c.operatorClient.Kueues(namespace.GetNamespace()).Update(c.ctx, kueue, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.sync(); err != nil {
+		t.Fatalf("Unexpected sync error: %v", err)
+	}
+	objects, status = c.localQueueMappings(t)
+	if diff := cmp.Diff([]kueuev1alpha1.LocalQueueMapping{
+		{Namespace: "team-c", Name: "mine", ClusterQueue: "cpu-queue"},
+	}, objects); len(diff) != 0 {
+		t.Errorf("Unexpected LocalQueues (-want,+got):\n%s", diff)
+	}
+	if len(status) != 0 {
+		t.Errorf("Expected empty status.localQueues, got %v", status)
+	}
+}
+
+func TestLocalQueueControllerReportsNameConflict(t *testing.T) {
+	kueue := newTestKueue()
+	kueue.Spec.LocalQueueProvisioning = &kueuev1alpha1.LocalQueueProvisioning{}
+	c, _ := newTestLocalQueueController(t, kueue,
+		labelledNamespace("team-a", "gpu-queue"),
+		labelledNamespace("team-b", "cpu-queue"),
+	)
+	userQueue := queues.BuildLocalQueue("team-a", defaultProvisionedLocalQueueName, "other-queue")
+	if err := createIfMissing(c.ctx, c.dynamicClient, c.eventRecorder, queues.LocalQueuesGVR, userQueue); err != nil {
+		t.Fatal(err)
+	}
+
+	conflict := func() *operatorv1.OperatorCondition {
+		t.Helper()
+		kueue, err := c.operatorClient.Kueues(namespace.GetNamespace()).Get(c.ctx, operatorclient.OperatorConfigName, metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v1helpers.FindOperatorCondition(kueue.Status.Conditions, localQueueNameConflictCondition)
+	}
+
+	if err := c.sync(); err != nil {
+		t.Fatalf("Unexpected sync error: %v", err)
+	}
+	// The LocalQueue of the user is kept and team-a is reported.
+	objects, status := c.localQueueMappings(t)
+	if diff := cmp.Diff([]kueuev1alpha1.LocalQueueMapping{
+		{Namespace: "team-a", Name: defaultProvisionedLocalQueueName, ClusterQueue: "other-queue"},
+		{Namespace: "team-b", Name: defaultProvisionedLocalQueueName, ClusterQueue: "cpu-queue"},
+	}, objects); len(diff) != 0 {
+		t.Errorf("Unexpected LocalQueues (-want,+got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]kueuev1alpha1.LocalQueueMapping{
+		{Namespace: "team-b", Name: defaultProvisionedLocalQueueName, ClusterQueue: "cpu-queue"},
+	}, status); len(diff) != 0 {
+		t.Errorf("Unexpected status.localQueues (-want,+got):\n%s", diff)
+	}
+	if condition := conflict(); condition == nil || condition.Status != operatorv1.ConditionTrue || !strings.Contains(condition.Message, "team-a") {
+		t.Errorf("Expected %s to report team-a, got %v", localQueueNameConflictCondition, condition)
+	}
+
+	// Once the user removes the LocalQueue, the namespace is provisioned.
+	if err := c.dynamicClient.Resource(queues.LocalQueuesGVR).Namespace("team-a").Delete(c.ctx, defaultProvisionedLocalQueueName, metav1.DeleteOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.sync(); err != nil {
+		t.Fatalf("Unexpected sync error: %v", err)
+	}
+	if _, status := c.localQueueMappings(t); len(status) != 2 {
+		t.Errorf("Expected both namespaces in status.localQueues, got %v", status)
+	}
+	if condition := conflict(); condition == nil || condition.Status != operatorv1.ConditionFalse {
+		t.Errorf("Expected %s to be false, got %v", localQueueNameConflictCondition, condition)
+	}
+}
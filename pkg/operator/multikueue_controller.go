@@ -0,0 +1,501 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	kueuev1alpha1 "github.com/openshift/kueue-operator/pkg/apis/kueueoperator/v1alpha1"
+	"github.com/openshift/kueue-operator/pkg/builders/multikueue"
+	kueueconfigclient "github.com/openshift/kueue-operator/pkg/generated/clientset/versioned/typed/kueueoperator/v1alpha1"
+	operatorclientinformers "github.com/openshift/kueue-operator/pkg/generated/informers/externalversions/kueueoperator/v1alpha1"
+	"github.com/openshift/kueue-operator/pkg/namespace"
+	"github.com/openshift/kueue-operator/pkg/operator/operatorclient"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/klog/v2"
+	kueuev1beta1 "sigs.k8s.io/kueue/apis/kueue/v1beta1"
+)
+
+const (
+	// multiKueueLabel marks the objects created for spec.multiKueue. Only these are
+	// updated and deleted by the controller.
+	multiKueueLabel = "kueue.openshift.io/multikueue"
+
+	// Kueue reports the connection to a worker in the MultiKueueCluster status, which
+	// nothing watches.
+	multiKueueResyncInterval = time.Minute
+)
+
+// MultiKueueController sets the cluster up for the MultiKueue role of spec.multiKueue.
+// For a manager it validates the kubeconfig Secret of every worker, creates the
+// MultiKueueClusters, the MultiKueueConfig and the AdmissionCheck, and reports the
+// connection to each worker in status.multiKueueWorkers. For a worker it creates the
+// ServiceAccount, with its permissions and token, that managers connect with.
+type MultiKueueController struct {
+	ctx               context.Context
+	operatorClient    kueueconfigclient.KueueV1alpha1Interface
+	kubeClient        kubernetes.Interface
+	dynamicClient     dynamic.Interface
+	secretLister      corev1listers.SecretLister
+	eventRecorder     events.Recorder
+	operatorNamespace string
+}
+
+func NewMultiKueueController(
+	ctx context.Context,
+	operatorConfigClient kueueconfigclient.KueueV1alpha1Interface,
+	operatorClientInformer operatorclientinformers.KueueInformer,
+	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
+	kubeClient kubernetes.Interface,
+	dynamicClient dynamic.Interface,
+	eventRecorder events.Recorder,
+) (factory.Controller, error) {
+	secretInformer := kubeInformersForNamespaces.InformersFor(namespace.GetNamespace()).Core().V1().Secrets()
+	c := &MultiKueueController{
+		ctx:               ctx,
+		operatorClient:    operatorConfigClient,
+		kubeClient:        kubeClient,
+		dynamicClient:     dynamicClient,
+		secretLister:      secretInformer.Lister(),
+		eventRecorder:     eventRecorder.WithComponentSuffix("multikueue-controller"),
+		operatorNamespace: namespace.GetNamespace(),
+	}
+
+	syncCtx := factory.NewSyncContext("MultiKueueController", eventRecorder)
+	if _, err := operatorClientInformer.Informer().AddEventHandler(queueHandler(syncCtx, kueueSpecChanged)); err != nil {
+		return nil, err
+	}
+
+	return factory.New().
+		WithSyncContext(syncCtx).
+		WithInformers(secretInformer.Informer()).
+		WithBareInformers(operatorClientInformer.Informer()).
+		WithSync(func(ctx context.Context, _ factory.SyncContext) error { return c.sync() }).
+		ResyncEvery(multiKueueResyncInterval).
+		ToController("MultiKueueController", c.eventRecorder), nil
+}
+
+func (c *MultiKueueController) sync() error {
+	kueue, err := c.operatorClient.Kueues(c.operatorNamespace).Get(c.ctx, operatorclient.OperatorConfigName, metav1.GetOptions{})
+	if err != nil {
+		klog.ErrorS(err, "unable to get operator configuration", "namespace", c.operatorNamespace, "kueue", operatorclient.OperatorConfigName)
+		return err
+	}
+
+	var errs []error
+	var workers []kueuev1alpha1.MultiKueueWorkerStatus
+	wanted := sets.New[string]()
+	if mk := kueue.Spec.MultiKueue; mk != nil {
+		switch mk.Role {
+		case kueuev1alpha1.MultiKueueRoleManager:
+			workers, errs = c.syncManager(mk, wanted)
+		case kueuev1alpha1.MultiKueueRoleWorker:
+			errs = c.syncWorker(wanted)
+		}
+	}
+	errs = append(errs, c.pruneObjects(wanted)...)
+
+	workersConnected := operatorv1.OperatorCondition{
+		Type:   "MultiKueueWorkersConnected",
+		Status: operatorv1.ConditionTrue,
+		Reason: "AsExpected",
+	}
+	var notConnected []string
+	for _, worker := range workers {
+		if worker.State != kueuev1alpha1.MultiKueueWorkerConnected {
+			notConnected = append(notConnected, fmt.Sprintf("%s is %s", worker.Name, worker.State))
+		}
+	}
+	if len(notConnected) > 0 {
+		workersConnected.Status = operatorv1.ConditionFalse
+		workersConnected.Reason = "WorkersNotConnected"
+		workersConnected.Message = strings.Join(notConnected, ", ")
+	}
+
+	degraded := operatorv1.OperatorCondition{
+		Type:   "MultiKueueControllerDegraded",
+		Status: operatorv1.ConditionFalse,
+		Reason: "AsExpected",
+	}
+	if len(errs) > 0 {
+		degraded.Status = operatorv1.ConditionTrue
+		degraded.Reason = "SyncFailed"
+		degraded.Message = utilerrors.NewAggregate(errs).Error()
+	}
+	if err := updateKueueStatus(c.ctx, c.operatorClient, c.operatorNamespace, kueue.Name, func(status *kueuev1alpha1.KueueStatus) {
+		status.MultiKueueWorkers = mergeMultiKueueWorkerStatus(status.MultiKueueWorkers, workers)
+		if len(workers) == 0 {
+			v1helpers.RemoveOperatorCondition(&status.Conditions, workersConnected.Type)
+		} else {
+			v1helpers.SetOperatorCondition(&status.Conditions, workersConnected)
+		}
+		v1helpers.SetOperatorCondition(&status.Conditions, degraded)
+	}); err != nil {
+		errs = append(errs, err)
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+// syncManager creates the MultiKueue objects of a manager and returns the state of the
+// workers. The MultiKueueCluster of a worker with an invalid kubeconfig is created too,
+// Kueue connects to it as soon as the Secret is fixed.
+func (c *MultiKueueController) syncManager(mk *kueuev1alpha1.MultiKueue, wanted sets.Set[string]) ([]kueuev1alpha1.MultiKueueWorkerStatus, []error) {
+	var errs []error
+	workers := make([]kueuev1alpha1.MultiKueueWorkerStatus, 0, len(mk.Workers))
+	names := make([]string, 0, len(mk.Workers))
+	for _, worker := range mk.Workers {
+		names = append(names, worker.Name)
+		status := kueuev1alpha1.MultiKueueWorkerStatus{Name: worker.Name}
+		if err := c.validateKubeconfigSecret(worker.KubeconfigSecret); err != nil {
+			status.State = kueuev1alpha1.MultiKueueWorkerInvalidKubeconfig
+			status.Message = err.Error()
+		}
+
+		cluster, err := c.applyKueueObject(multikueue.MultiKueueClustersGVR, multikueue.BuildMultiKueueCluster(worker.Name, worker.KubeconfigSecret), wanted)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		if len(status.State) == 0 {
+			status.State, status.Message = multiKueueClusterState(cluster)
+		}
+		workers = append(workers, status)
+	}
+
+	if len(names) > 0 {
+		if _, err := c.applyKueueObject(multikueue.MultiKueueConfigsGVR, multikueue.BuildMultiKueueConfig(names), wanted); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if _, err := c.applyKueueObject(multikueue.AdmissionChecksGVR, multikueue.BuildAdmissionCheck(), wanted); err != nil {
+		errs = append(errs, err)
+	}
+	return workers, errs
+}
+
+func (c *MultiKueueController) validateKubeconfigSecret(name string) error {
+	secret, err := c.secretLister.Secrets(c.operatorNamespace).Get(name)
+	if errors.IsNotFound(err) {
+		return fmt.Errorf("secret %s/%s not found", c.operatorNamespace, name)
+	}
+	if err != nil {
+		return err
+	}
+	data, ok := secret.Data[multikueue.KubeconfigKey]
+	if !ok {
+		return fmt.Errorf("secret %s/%s has no %s key", c.operatorNamespace, name, multikueue.KubeconfigKey)
+	}
+	if err := multikueue.ValidateKubeconfig(data); err != nil {
+		return fmt.Errorf("secret %s/%s: %w", c.operatorNamespace, name, err)
+	}
+	return nil
+}
+
+// multiKueueClusterState maps the Active condition Kueue sets on a MultiKueueCluster to
+// the state of the worker.
+func multiKueueClusterState(cluster *unstructured.Unstructured) (kueuev1alpha1.MultiKueueWorkerState, string) {
+	if cluster == nil {
+		return kueuev1alpha1.MultiKueueWorkerPending, ""
+	}
+	status := kueuev1beta1.MultiKueueClusterStatus{}
+	content, _, _ := unstructured.NestedMap(cluster.Object, "status")
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(content, &status); err != nil {
+		return kueuev1alpha1.MultiKueueWorkerPending, ""
+	}
+	active := meta.FindStatusCondition(status.Conditions, kueuev1beta1.MultiKueueClusterActive)
+	switch {
+	case active == nil:
+		return kueuev1alpha1.MultiKueueWorkerPending, "Kueue has not connected to the worker yet"
+	case active.Status == metav1.ConditionTrue:
+		return kueuev1alpha1.MultiKueueWorkerConnected, active.Message
+	default:
+		return kueuev1alpha1.MultiKueueWorkerDisconnected, active.Message
+	}
+}
+
+// mergeMultiKueueWorkerStatus keeps the last transition time of the workers whose state
+// did not change.
+func mergeMultiKueueWorkerStatus(existing, workers []kueuev1alpha1.MultiKueueWorkerStatus) []kueuev1alpha1.MultiKueueWorkerStatus {
+	if len(workers) == 0 {
+		return nil
+	}
+	previous := make(map[string]kueuev1alpha1.MultiKueueWorkerStatus, len(existing))
+	for _, worker := range existing {
+		previous[worker.Name] = worker
+	}
+	merged := make([]kueuev1alpha1.MultiKueueWorkerStatus, 0, len(workers))
+	for _, worker := range workers {
+		if prev, ok := previous[worker.Name]; ok && prev.State == worker.State {
+			worker.LastTransitionTime = prev.LastTransitionTime
+		} else {
+			worker.LastTransitionTime = metav1.Now()
+		}
+		merged = append(merged, worker)
+	}
+	return merged
+}
+
+// applyKueueObject creates the object or replaces the spec of an object it created
+// before, and returns the object in the cluster. Objects of the same name created by
+// someone else are left alone and reported.
+func (c *MultiKueueController) applyKueueObject(gvr schema.GroupVersionResource, obj runtime.Object, wanted sets.Set[string]) (*unstructured.Unstructured, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+	required := &unstructured.Unstructured{Object: content}
+	unstructured.RemoveNestedField(required.Object, "status")
+	required.SetLabels(map[string]string{multiKueueLabel: "true"})
+	setControllerManagedLabel(required)
+	wanted.Insert(multiKueueObjectKey(gvr.Resource, "", required.GetName()))
+
+	client := c.dynamicClient.Resource(gvr)
+	existing, err := client.Get(c.ctx, required.GetName(), metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		created, err := client.Create(c.ctx, required, metav1.CreateOptions{FieldManager: fieldManager})
+		if err != nil {
+			return nil, fmt.Errorf("unable to create %s %s: %w", required.GetKind(), required.GetName(), err)
+		}
+		c.eventRecorder.Eventf(required.GetKind()+"Created", "Created %s %s", required.GetKind(), required.GetName())
+		return created, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := existing.GetLabels()[multiKueueLabel]; !ok {
+		return existing, fmt.Errorf("%s %s exists and is not managed by the operator", required.GetKind(), required.GetName())
+	}
+	if equality.Semantic.DeepEqual(existing.Object["spec"], required.Object["spec"]) {
+		return existing, nil
+	}
+	updated := existing.DeepCopy()
+	updated.Object["spec"] = required.Object["spec"]
+	updated, err = client.Update(c.ctx, updated, metav1.UpdateOptions{FieldManager: fieldManager})
+	if err != nil {
+		return nil, fmt.Errorf("unable to update %s %s: %w", required.GetKind(), required.GetName(), err)
+	}
+	c.eventRecorder.Eventf(required.GetKind()+"Updated", "Updated %s %s", required.GetKind(), required.GetName())
+	return updated, nil
+}
+
+// syncWorker creates the ServiceAccount managers connect with. The kubeconfig of the
+// worker is built from the token and CA of its token Secret.
+func (c *MultiKueueController) syncWorker(wanted sets.Set[string]) []error {
+	var errs []error
+	serviceAccount := multikueue.BuildWorkerServiceAccount(c.operatorNamespace)
+	markMultiKueueObject(serviceAccount)
+	wanted.Insert(multiKueueObjectKey("serviceaccounts", serviceAccount.Namespace, serviceAccount.Name))
+	if _, err := c.kubeClient.CoreV1().ServiceAccounts(c.operatorNamespace).Create(c.ctx, serviceAccount, metav1.CreateOptions{FieldManager: fieldManager}); err == nil {
+		c.eventRecorder.Eventf("ServiceAccountCreated", "Created ServiceAccount %s/%s", serviceAccount.Namespace, serviceAccount.Name)
+	} else if !errors.IsAlreadyExists(err) {
+		errs = append(errs, fmt.Errorf("unable to create ServiceAccount %s/%s: %w", serviceAccount.Namespace, serviceAccount.Name, err))
+	}
+
+	secret := multikueue.BuildWorkerTokenSecret(c.operatorNamespace)
+	markMultiKueueObject(secret)
+	wanted.Insert(multiKueueObjectKey("secrets", secret.Namespace, secret.Name))
+	if _, err := c.kubeClient.CoreV1().Secrets(c.operatorNamespace).Create(c.ctx, secret, metav1.CreateOptions{FieldManager: fieldManager}); err == nil {
+		c.eventRecorder.Eventf("SecretCreated", "Created Secret %s/%s", secret.Namespace, secret.Name)
+	} else if !errors.IsAlreadyExists(err) {
+		errs = append(errs, fmt.Errorf("unable to create Secret %s/%s: %w", secret.Namespace, secret.Name, err))
+	}
+
+	if err := c.applyWorkerClusterRole(multikueue.BuildWorkerClusterRole(), wanted); err != nil {
+		errs = append(errs, err)
+	}
+	if err := c.applyWorkerClusterRoleBinding(multikueue.BuildWorkerClusterRoleBinding(c.operatorNamespace), wanted); err != nil {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+func (c *MultiKueueController) applyWorkerClusterRole(required *rbacv1.ClusterRole, wanted sets.Set[string]) error {
+	markMultiKueueObject(required)
+	wanted.Insert(multiKueueObjectKey("clusterroles", "", required.Name))
+	client := c.kubeClient.RbacV1().ClusterRoles()
+	existing, err := client.Get(c.ctx, required.Name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		if _, err := client.Create(c.ctx, required, metav1.CreateOptions{FieldManager: fieldManager}); err != nil {
+			return fmt.Errorf("unable to create ClusterRole %s: %w", required.Name, err)
+		}
+		c.eventRecorder.Eventf("ClusterRoleCreated", "Created ClusterRole %s", required.Name)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if _, ok := existing.Labels[multiKueueLabel]; !ok {
+		return fmt.Errorf("ClusterRole %s exists and is not managed by the operator", required.Name)
+	}
+	// ClusterRoles created before the controller-managed label carry the one of the
+	// operand, which is updated along with the rules.
+	if equality.Semantic.DeepEqual(existing.Rules, required.Rules) && existing.Labels[managedByLabel] == required.Labels[managedByLabel] {
+		return nil
+	}
+	updated := existing.DeepCopy()
+	updated.Rules = required.Rules
+	updated.Labels[managedByLabel] = required.Labels[managedByLabel]
+	if _, err := client.Update(c.ctx, updated, metav1.UpdateOptions{FieldManager: fieldManager}); err != nil {
+		return fmt.Errorf("unable to update ClusterRole %s: %w", required.Name, err)
+	}
+	return nil
+}
+
+func (c *MultiKueueController) applyWorkerClusterRoleBinding(required *rbacv1.ClusterRoleBinding, wanted sets.Set[string]) error {
+	markMultiKueueObject(required)
+	wanted.Insert(multiKueueObjectKey("clusterrolebindings", "", required.Name))
+	client := c.kubeClient.RbacV1().ClusterRoleBindings()
+	existing, err := client.Get(c.ctx, required.Name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		if _, err := client.Create(c.ctx, required, metav1.CreateOptions{FieldManager: fieldManager}); err != nil {
+			return fmt.Errorf("unable to create ClusterRoleBinding %s: %w", required.Name, err)
+		}
+		c.eventRecorder.Eventf("ClusterRoleBindingCreated", "Created ClusterRoleBinding %s", required.Name)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if _, ok := existing.Labels[multiKueueLabel]; !ok {
+		return fmt.Errorf("ClusterRoleBinding %s exists and is not managed by the operator", required.Name)
+	}
+	if equality.Semantic.DeepEqual(existing.Subjects, required.Subjects) && existing.RoleRef == required.RoleRef && existing.Labels[managedByLabel] == required.Labels[managedByLabel] {
+		return nil
+	}
+	if existing.RoleRef != required.RoleRef {
+		// The roleRef of a binding is immutable.
+		if err := client.Delete(c.ctx, existing.Name, metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		return c.applyWorkerClusterRoleBinding(required, wanted)
+	}
+	updated := existing.DeepCopy()
+	updated.Subjects = required.Subjects
+	updated.Labels[managedByLabel] = required.Labels[managedByLabel]
+	if _, err := client.Update(c.ctx, updated, metav1.UpdateOptions{FieldManager: fieldManager}); err != nil {
+		return fmt.Errorf("unable to update ClusterRoleBinding %s: %w", required.Name, err)
+	}
+	return nil
+}
+
+// pruneObjects deletes the objects created for spec.multiKueue that are no longer
+// wanted, after a change of role or of the workers.
+func (c *MultiKueueController) pruneObjects(wanted sets.Set[string]) []error {
+	var errs []error
+	listOptions := metav1.ListOptions{LabelSelector: multiKueueLabel}
+	for _, gvr := range []schema.GroupVersionResource{
+		multikueue.AdmissionChecksGVR,
+		multikueue.MultiKueueConfigsGVR,
+		multikueue.MultiKueueClustersGVR,
+	} {
+		list, err := c.dynamicClient.Resource(gvr).List(c.ctx, listOptions)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, item := range list.Items {
+			if wanted.Has(multiKueueObjectKey(gvr.Resource, "", item.GetName())) {
+				continue
+			}
+			errs = append(errs, c.deleteObject(item.GetKind(), "", item.GetName(), func() error {
+				return c.dynamicClient.Resource(gvr).Delete(c.ctx, item.GetName(), metav1.DeleteOptions{})
+			}))
+		}
+	}
+
+	if bindings, err := c.kubeClient.RbacV1().ClusterRoleBindings().List(c.ctx, listOptions); err != nil {
+		errs = append(errs, err)
+	} else {
+		for _, item := range bindings.Items {
+			if !wanted.Has(multiKueueObjectKey("clusterrolebindings", "", item.Name)) {
+				errs = append(errs, c.deleteObject("ClusterRoleBinding", "", item.Name, func() error {
+					return c.kubeClient.RbacV1().ClusterRoleBindings().Delete(c.ctx, item.Name, metav1.DeleteOptions{})
+				}))
+			}
+		}
+	}
+	if roles, err := c.kubeClient.RbacV1().ClusterRoles().List(c.ctx, listOptions); err != nil {
+		errs = append(errs, err)
+	} else {
+		for _, item := range roles.Items {
+			if !wanted.Has(multiKueueObjectKey("clusterroles", "", item.Name)) {
+				errs = append(errs, c.deleteObject("ClusterRole", "", item.Name, func() error {
+					return c.kubeClient.RbacV1().ClusterRoles().Delete(c.ctx, item.Name, metav1.DeleteOptions{})
+				}))
+			}
+		}
+	}
+	if secrets, err := c.kubeClient.CoreV1().Secrets(c.operatorNamespace).List(c.ctx, listOptions); err != nil {
+		errs = append(errs, err)
+	} else {
+		for _, item := range secrets.Items {
+			if !wanted.Has(multiKueueObjectKey("secrets", item.Namespace, item.Name)) {
+				errs = append(errs, c.deleteObject("Secret", item.Namespace, item.Name, func() error {
+					return c.kubeClient.CoreV1().Secrets(item.Namespace).Delete(c.ctx, item.Name, metav1.DeleteOptions{})
+				}))
+			}
+		}
+	}
+	if serviceAccounts, err := c.kubeClient.CoreV1().ServiceAccounts(c.operatorNamespace).List(c.ctx, listOptions); err != nil {
+		errs = append(errs, err)
+	} else {
+		for _, item := range serviceAccounts.Items {
+			if !wanted.Has(multiKueueObjectKey("serviceaccounts", item.Namespace, item.Name)) {
+				errs = append(errs, c.deleteObject("ServiceAccount", item.Namespace, item.Name, func() error {
+					return c.kubeClient.CoreV1().ServiceAccounts(item.Namespace).Delete(c.ctx, item.Name, metav1.DeleteOptions{})
+				}))
+			}
+		}
+	}
+
+	var failed []error
+	for _, err := range errs {
+		if err != nil {
+			failed = append(failed, err)
+		}
+	}
+	return failed
+}
+
+func (c *MultiKueueController) deleteObject(kind, ns, name string, deleteFunc func() error) error {
+	objName := name
+	if len(ns) > 0 {
+		objName = ns + "/" + name
+	}
+	if err := deleteFunc(); err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("unable to delete %s %s: %w", kind, objName, err)
+	}
+	c.eventRecorder.Eventf(kind+"Deleted", "Deleted %s %s", kind, objName)
+	return nil
+}
+
+func markMultiKueueObject(obj metav1.Object) {
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[multiKueueLabel] = "true"
+	obj.SetLabels(labels)
+	setControllerManagedLabel(obj)
+}
+
+func multiKueueObjectKey(resource, ns, name string) string {
+	return resource + "/" + ns + "/" + name
+}
@@ -0,0 +1,223 @@
+package operator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	kueuev1alpha1 "github.com/openshift/kueue-operator/pkg/apis/kueueoperator/v1alpha1"
+	"github.com/openshift/kueue-operator/pkg/builders/multikueue"
+	"github.com/openshift/kueue-operator/pkg/namespace"
+	"github.com/openshift/kueue-operator/pkg/operator/operatorclient"
+)
+
+const testWorkerKubeconfig = `apiVersion: v1
+kind: Config
+current-context: east
+contexts:
+- name: east
+  context:
+    cluster: east
+    user: east
+clusters:
+- name: east
+  cluster:
+    server: https://api.east.example.com:6443
+users:
+- name: east
+  user:
+    token: secret-token
+`
+
+func newTestMultiKueueController(t *testing.T, kueue *kueuev1alpha1.Kueue, secrets ...*corev1.Secret) *MultiKueueController {
+	t.Helper()
+	defaults, _ := newTestDefaultsController(t, kueue)
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	for _, secret := range secrets {
+		if err := indexer.Add(secret); err != nil {
+			t.Fatal(err)
+		}
+	}
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		multikueue.MultiKueueClustersGVR: "MultiKueueClusterList",
+		multikueue.MultiKueueConfigsGVR:  "MultiKueueConfigList",
+		multikueue.AdmissionChecksGVR:    "AdmissionCheckList",
+	})
+	return &MultiKueueController{
+		ctx:               defaults.ctx,
+		operatorClient:    defaults.operatorClient,
+		kubeClient:        kubefake.NewClientset(),
+		dynamicClient:     dynamicClient,
+		secretLister:      corev1listers.NewSecretLister(indexer),
+		eventRecorder:     defaults.eventRecorder,
+		operatorNamespace: defaults.operatorNamespace,
+	}
+}
+
+func (c *MultiKueueController) objectNames(t *testing.T, gvr schema.GroupVersionResource) []string {
+	t.Helper()
+	list, err := c.dynamicClient.Resource(gvr).List(c.ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, item := range list.Items {
+		names = append(names, item.GetName())
+	}
+	return names
+}
+
+func (c *MultiKueueController) getKueue(t *testing.T) *kueuev1alpha1.Kueue {
+	t.Helper()
+	kueue, err := c.operatorClient.Kueues(namespace.GetNamespace()).Get(c.ctx, operatorclient.OperatorConfigName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return kueue
+}
+
+func TestMultiKueueController(t *testing.T) {
+	kueue := newTestKueue()
+	kueue.Spec.MultiKueue = &kueuev1alpha1.MultiKueue{
+		Role: kueuev1alpha1.MultiKueueRoleManager,
+		Workers: []kueuev1alpha1.MultiKueueWorker{
+			{Name: "east", KubeconfigSecret: "east-kubeconfig"},
+			{Name: "west", KubeconfigSecret: "west-kubeconfig"},
+		},
+	}
+	c := newTestMultiKueueController(t, kueue, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "east-kubeconfig", Namespace: namespace.GetNamespace()},
+		Data:       map[string][]byte{multikueue.KubeconfigKey: []byte(testWorkerKubeconfig)},
+	})
+
+	if err := c.sync(); err != nil {
+		t.Fatalf("Unexpected sync error: %v", err)
+	}
+
+	if diff := cmp.Diff([]string{"east", "west"}, c.objectNames(t, multikueue.MultiKueueClustersGVR)); len(diff) != 0 {
+		t.Errorf("Unexpected MultiKueueClusters (-want,+got):\n%s", diff)
+	}
+	config, err := c.dynamicClient.Resource(multikueue.MultiKueueConfigsGVR).Get(c.ctx, multikueue.ConfigName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	clusters, _, _ := unstructured.NestedStringSlice(config.Object, "spec", "clusters")
+	if diff := cmp.Diff([]string{"east", "west"}, clusters); len(diff) != 0 {
+		t.Errorf("Unexpected clusters of the MultiKueueConfig (-want,+got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{multikueue.ConfigName}, c.objectNames(t, multikueue.AdmissionChecksGVR)); len(diff) != 0 {
+		t.Errorf("Unexpected AdmissionChecks (-want,+got):\n%s", diff)
+	}
+
+	workers := c.getKueue(t).Status.MultiKueueWorkers
+	if len(workers) != 2 ||
+		workers[0].State != kueuev1alpha1.MultiKueueWorkerPending ||
+		workers[1].State != kueuev1alpha1.MultiKueueWorkerInvalidKubeconfig ||
+		!strings.Contains(workers[1].Message, "west-kubeconfig not found") {
+		t.Fatalf("Unexpected status.multiKueueWorkers: %+v", workers)
+	}
+
+	// Kueue reports the connection in the MultiKueueCluster status.
+	east, err := c.dynamicClient.Resource(multikueue.MultiKueueClustersGVR).Get(c.ctx, "east", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := unstructured.SetNestedSlice(east.Object, []interface{}{map[string]interface{}{
+		"type":               "Active",
+		"status":             "True",
+		"reason":             "Active",
+		"message":            "Connected",
+		"lastTransitionTime": "2024-01-01T00:00:00Z",
+	}}, "status", "conditions"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.dynamicClient.Resource(multikueue.MultiKueueClustersGVR).Update(c.ctx, east, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.sync(); err != nil {
+		t.Fatalf("Unexpected sync error: %v", err)
+	}
+	kueue = c.getKueue(t)
+	if state := kueue.Status.MultiKueueWorkers[0].State; state != kueuev1alpha1.MultiKueueWorkerConnected {
+		t.Errorf("Expected east to be Connected, got %s", state)
+	}
+	if !v1helpers.IsOperatorConditionFalse(kueue.Status.Conditions, "MultiKueueWorkersConnected") {
+		t.Errorf("Expected MultiKueueWorkersConnected to be false while west is not connected, got %v", kueue.Status.Conditions)
+	}
+
+	// Switching to the Worker role removes the manager objects.
+	kueue.Spec.MultiKueue = &kueuev1alpha1.MultiKueue{Role: kueuev1alpha1.MultiKueueRoleWorker}
+	if _, err := c.operatorClient.Kueues(namespace.GetNamespace()).Update(c.ctx, kueue, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.sync(); err != nil {
+		t.Fatalf("Unexpected sync error: %v", err)
+	}
+	for _, gvr := range []schema.GroupVersionResource{multikueue.MultiKueueClustersGVR, multikueue.MultiKueueConfigsGVR, multikueue.AdmissionChecksGVR} {
+		if names := c.objectNames(t, gvr); len(names) != 0 {
+			t.Errorf("Expected the %s to be deleted, got %v", gvr.Resource, names)
+		}
+	}
+	if _, err := c.kubeClient.CoreV1().ServiceAccounts(namespace.GetNamespace()).Get(c.ctx, multikueue.WorkerServiceAccountName, metav1.GetOptions{}); err != nil {
+		t.Errorf("Expected the worker ServiceAccount: %v", err)
+	}
+	if _, err := c.kubeClient.CoreV1().Secrets(namespace.GetNamespace()).Get(c.ctx, multikueue.WorkerTokenSecretName, metav1.GetOptions{}); err != nil {
+		t.Errorf("Expected the worker token Secret: %v", err)
+	}
+	binding, err := c.kubeClient.RbacV1().ClusterRoleBindings().Get(c.ctx, multikueue.WorkerClusterRoleName+"-binding", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if binding.Subjects[0].Namespace != namespace.GetNamespace() {
+		t.Errorf("Unexpected subjects %v", binding.Subjects)
+	}
+	// The worker objects are not operand objects and stay out of the drift informers.
+	workerRole, err := c.kubeClient.RbacV1().ClusterRoles().Get(c.ctx, multikueue.WorkerClusterRoleName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	serviceAccount, err := c.kubeClient.CoreV1().ServiceAccounts(namespace.GetNamespace()).Get(c.ctx, multikueue.WorkerServiceAccountName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokenSecret, err := c.kubeClient.CoreV1().Secrets(namespace.GetNamespace()).Get(c.ctx, multikueue.WorkerTokenSecretName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, obj := range []metav1.Object{binding, workerRole, serviceAccount, tokenSecret} {
+		if got := obj.GetLabels()[managedByLabel]; got != controllerManagedValue {
+			t.Errorf("Expected %s to be labelled %s=%s, got %q", obj.GetName(), managedByLabel, controllerManagedValue, got)
+		}
+	}
+	if workers := c.getKueue(t).Status.MultiKueueWorkers; len(workers) != 0 {
+		t.Errorf("Expected no worker status for a worker, got %v", workers)
+	}
+
+	// Removing spec.multiKueue removes everything.
+	kueue = c.getKueue(t)
+	kueue.Spec.MultiKueue = nil
+	if _, err := c.operatorClient.Kueues(namespace.GetNamespace()).Update(c.ctx, kueue, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.sync(); err != nil {
+		t.Fatalf("Unexpected sync error: %v", err)
+	}
+	roles, err := c.kubeClient.RbacV1().ClusterRoles().List(c.ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(roles.Items) != 0 {
+		t.Errorf("Expected the worker ClusterRole to be deleted, got %v", roles.Items)
+	}
+}
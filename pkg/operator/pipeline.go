@@ -0,0 +1,512 @@
+package operator
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	kueuev1alpha1 "github.com/openshift/kueue-operator/pkg/apis/kueueoperator/v1alpha1"
+	"github.com/openshift/kueue-operator/pkg/builders/configmap"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// configMapAnnotation tracks the Kueue configuration on the operand deployment.
+	configMapAnnotation = "kueue/configmap"
+	// webhookCertAnnotation tracks the webhook serving certificate on the operand deployment.
+	webhookCertAnnotation = "secret/kueue-webhook-server-cert"
+	// canaryConfigMapAnnotation tracks the Kueue configuration on the canary deployment.
+	canaryConfigMapAnnotation = "canary/configmap"
+)
+
+// contentHashAnnotations hold a hash of the content the manager reads instead of the
+// resourceVersion, so that changes to the metadata of these objects do not restart it.
+// spec.config.changePolicy decides whether a change of the hash is rolled out.
+var contentHashAnnotations = sets.New(configMapAnnotation, webhookCertAnnotation, canaryConfigMapAnnotation)
+
+// resourceApplier is one step of the reconcile pipeline.
+type resourceApplier struct {
+	// name identifies the step in status.resources and in dependsOn of later steps.
+	name string
+	// dependsOn lists the steps that must be applied before this one runs.
+	// A step is skipped when one of them failed or was skipped.
+	dependsOn []string
+	// required reads the objects of the step from bindata and mutates them for the Kueue CR.
+	// The objects are keyed by the pod template annotation that tracks them on the operand
+	// deployment. specAnnotations holds the annotations of the steps applied so far.
+	required func(kueue *kueuev1alpha1.Kueue, specAnnotations map[string]string) (map[string]runtime.Object, error)
+}
+
+// resourcePipeline returns the reconcile steps in apply order. Every step only depends on
+// steps listed before it.
+func resourcePipeline() []resourceApplier {
+	return []resourceApplier{
+		{
+			name: "configmap",
+			required: func(kueue *kueuev1alpha1.Kueue, _ map[string]string) (map[string]runtime.Object, error) {
+				cfgMap, err := configmap.BuildConfigMap(kueue.Namespace, kueue.Spec.Config, kueue.Spec.MultiKueue)
+				if err != nil {
+					return nil, err
+				}
+				// An invalid configuration fails the step, so that the deployment keeps
+				// running with the configuration it has.
+				if err := configmap.Validate(cfgMap, kueueFeatureGateMap(kueue)); err != nil {
+					return nil, err
+				}
+				return map[string]runtime.Object{configMapAnnotation: cfgMap}, nil
+			},
+		},
+		{
+			name: "customresourcedefinitions",
+			required: func(kueue *kueuev1alpha1.Kueue, _ map[string]string) (map[string]runtime.Object, error) {
+				objects := map[string]runtime.Object{}
+				for fileName, crd := range requiredCustomResourceDefinitions(kueue) {
+					objects["crd/"+fileName] = crd
+				}
+				return objects, nil
+			},
+		},
+		{
+			name:     "serviceaccount",
+			required: single("serviceaccounts/kueue-operator", requiredServiceAccount),
+		},
+		{
+			name:     "webhook-server-cert",
+			required: single(webhookCertAnnotation, requiredSecret),
+		},
+		{
+			name:     "leader-election-role",
+			required: single("role/leader-election", assetRequired(requiredRole, "assets/kueue-operator/role-leader-election.yaml")),
+		},
+		{
+			name:      "leader-election-rolebinding",
+			dependsOn: []string{"serviceaccount", "leader-election-role"},
+			required:  single("rolebindings/leader-election", assetRequired(requiredRoleBinding, "assets/kueue-operator/rolebinding-leader-election.yaml")),
+		},
+		{
+			name:     "metrics-service",
+			required: single("service/metrics-service", assetRequired(requiredService, "assets/kueue-operator/metrics-service.yaml")),
+		},
+		{
+			name:     "visibility-service",
+			required: single("service/visibility-service", assetRequired(requiredService, "assets/kueue-operator/visibility-service.yaml")),
+		},
+		{
+			name:     "webhook-service",
+			required: single("service/webhook-service", assetRequired(requiredService, "assets/kueue-operator/webhook-service.yaml")),
+		},
+		{
+			name: "clusterroles",
+			required: func(kueue *kueuev1alpha1.Kueue, _ map[string]string) (map[string]runtime.Object, error) {
+				objects := map[string]runtime.Object{}
+				for fileName, clusterRole := range requiredClusterRoles(kueue) {
+					objects["clusterrole/"+fileName] = clusterRole
+				}
+				return objects, nil
+			},
+		},
+		{
+			name:     "openshift-clusterrole",
+			required: single("clusterrole/openshift-roles", requiredOpenshiftClusterRoleForKueue),
+		},
+		{
+			name:      "openshift-clusterrolebinding",
+			dependsOn: []string{"serviceaccount", "openshift-clusterrole"},
+			required:  single("clusterrolebinding/openshift-roles", requiredOpenshiftClusterRoleBindingForKueue),
+		},
+		{
+			name:      "kube-proxy-clusterrolebinding",
+			dependsOn: []string{"serviceaccount", "clusterroles"},
+			required:  single("clusterrolebinding/kube-proxy", assetRequired(requiredClusterRoleBinding, "assets/kueue-operator/clusterrolebinding-kube-proxy.yaml")),
+		},
+		{
+			name:      "manager-clusterrolebinding",
+			dependsOn: []string{"serviceaccount", "clusterroles"},
+			required:  single("clusterrolebinding/kueue-manager-role", assetRequired(requiredClusterRoleBinding, "assets/kueue-operator/clusterrolebinding-kueue-manager-role.yaml")),
+		},
+		{
+			// The deployment tracks every object it consumes through pod template
+			// annotations, so it waits for all of them.
+			name: "deployment",
+			dependsOn: []string{
+				"configmap",
+				"customresourcedefinitions",
+				"serviceaccount",
+				"webhook-server-cert",
+				"leader-election-role",
+				"leader-election-rolebinding",
+				"metrics-service",
+				"visibility-service",
+				"webhook-service",
+				"clusterroles",
+				"openshift-clusterrole",
+				"openshift-clusterrolebinding",
+				"kube-proxy-clusterrolebinding",
+				"manager-clusterrolebinding",
+			},
+			required: func(kueue *kueuev1alpha1.Kueue, specAnnotations map[string]string) (map[string]runtime.Object, error) {
+				return map[string]runtime.Object{"deployment": requiredDeployment(kueue, specAnnotations)}, nil
+			},
+		},
+		{
+			// The canary steps apply nothing without a running canary. Their objects are
+			// deleted by the reconciler once spec.canary is removed or aborted.
+			name: "canary-webhook-service",
+			required: func(kueue *kueuev1alpha1.Kueue, _ map[string]string) (map[string]runtime.Object, error) {
+				return optional("canary/webhook-service", requiredCanaryService(kueue)), nil
+			},
+		},
+		{
+			name: "canary-configmap",
+			required: func(kueue *kueuev1alpha1.Kueue, _ map[string]string) (map[string]runtime.Object, error) {
+				if activeCanary(kueue) == nil {
+					return map[string]runtime.Object{}, nil
+				}
+				cfgMap, err := configmap.BuildCanaryConfigMap(kueue.Namespace, kueue.Spec.Config, kueue.Spec.MultiKueue)
+				if err != nil {
+					return nil, err
+				}
+				if err := configmap.Validate(cfgMap, kueueFeatureGateMap(kueue)); err != nil {
+					return nil, err
+				}
+				return map[string]runtime.Object{canaryConfigMapAnnotation: cfgMap}, nil
+			},
+		},
+		{
+			// The canary restarts along with the stable manager, so it tracks the same
+			// objects, but its own configuration.
+			name:      "canary-deployment",
+			dependsOn: []string{"deployment", "canary-webhook-service", "canary-configmap"},
+			required: func(kueue *kueuev1alpha1.Kueue, specAnnotations map[string]string) (map[string]runtime.Object, error) {
+				annotations := make(map[string]string, len(specAnnotations))
+				for key, value := range specAnnotations {
+					if key == canaryConfigMapAnnotation || key != "deployment" && key != configMapAnnotation && !strings.HasPrefix(key, "canary/") {
+						annotations[key] = value
+					}
+				}
+				return optional("canary/deployment", requiredCanaryDeployment(kueue, annotations)), nil
+			},
+		},
+		{
+			name:      "canary-mutating-webhook",
+			dependsOn: []string{"customresourcedefinitions", "canary-webhook-service"},
+			required: func(kueue *kueuev1alpha1.Kueue, _ map[string]string) (map[string]runtime.Object, error) {
+				required, err := requiredCanaryMutatingWebhook(kueue)
+				return optional("canary/mutatingwebhook", required), err
+			},
+		},
+		{
+			name:      "canary-validating-webhook",
+			dependsOn: []string{"customresourcedefinitions", "canary-webhook-service"},
+			required: func(kueue *kueuev1alpha1.Kueue, _ map[string]string) (map[string]runtime.Object, error) {
+				required, err := requiredCanaryValidatingWebhook(kueue)
+				return optional("canary/validatingwebhook", required), err
+			},
+		},
+		{
+			// The stable webhooks only exclude the namespaces of the canary once the
+			// canary webhooks are in place, so that no namespace is left without them.
+			name:      "mutating-webhook",
+			dependsOn: []string{"customresourcedefinitions", "webhook-service", "canary-mutating-webhook"},
+			required:  single("mutatingwebhook", requiredMutatingWebhook),
+		},
+		{
+			name:      "validating-webhook",
+			dependsOn: []string{"customresourcedefinitions", "webhook-service", "canary-validating-webhook"},
+			required:  single("validatingwebhook", requiredValidatingWebhook),
+		},
+	}
+}
+
+// single adapts a builder of one object to resourceApplier.required.
+func single[T runtime.Object](annotationKey string, build func(kueue *kueuev1alpha1.Kueue) T) func(*kueuev1alpha1.Kueue, map[string]string) (map[string]runtime.Object, error) {
+	return func(kueue *kueuev1alpha1.Kueue, _ map[string]string) (map[string]runtime.Object, error) {
+		return map[string]runtime.Object{annotationKey: build(kueue)}, nil
+	}
+}
+
+// optional returns the object keyed by its annotation key, or no objects when it is nil.
+func optional[T any, PT interface {
+	*T
+	runtime.Object
+}](annotationKey string, obj PT) map[string]runtime.Object {
+	if obj == nil {
+		return map[string]runtime.Object{}
+	}
+	return map[string]runtime.Object{annotationKey: obj}
+}
+
+// assetRequired binds the bindata path of a builder that reads its object from an asset.
+func assetRequired[T runtime.Object](build func(kueue *kueuev1alpha1.Kueue, assetPath string) T, assetPath string) func(*kueuev1alpha1.Kueue) T {
+	return func(kueue *kueuev1alpha1.Kueue) T {
+		return build(kueue, assetPath)
+	}
+}
+
+// pipelineResult is the outcome of running the pipeline once.
+type pipelineResult struct {
+	resources  []kueuev1alpha1.ResourceStatus
+	deployment *appsv1.Deployment
+	errors     []error
+	// pendingRestart lists the annotations whose content changed but are kept on the
+	// deployment because of spec.config.changePolicy.
+	pendingRestart []string
+}
+
+// runPipeline applies the selected steps in order, or every step when selected is nil.
+// Failures do not stop the pipeline, only the steps that depend on a failed step are skipped.
+// Steps that are not selected keep their previous status. specAnnotations must hold the
+// annotations of the steps that are not selected.
+func (c *TargetConfigReconciler) runPipeline(kueue *kueuev1alpha1.Kueue, steps []resourceApplier, selected sets.Set[string], specAnnotations map[string]string) *pipelineResult {
+	result := &pipelineResult{}
+	states := map[string]kueuev1alpha1.ResourceState{}
+	previous := map[string]kueuev1alpha1.ResourceStatus{}
+	for _, status := range kueue.Status.Resources {
+		previous[status.Name] = status
+	}
+
+	for _, step := range steps {
+		if selected != nil && !selected.Has(step.name) {
+			states[step.name] = previous[step.name].State
+			result.resources = append(result.resources, previous[step.name])
+			continue
+		}
+
+		status := kueuev1alpha1.ResourceStatus{Name: step.name, State: kueuev1alpha1.ResourceStateApplied}
+
+		var pending []string
+		for _, dependency := range step.dependsOn {
+			if states[dependency] != kueuev1alpha1.ResourceStateApplied {
+				pending = append(pending, dependency)
+			}
+		}
+		if len(pending) > 0 {
+			status.State = kueuev1alpha1.ResourceStateSkipped
+			status.Message = fmt.Sprintf("waiting for %s", strings.Join(pending, ", "))
+		} else if conflicts, err := c.applyStep(kueue, step, specAnnotations, result); err != nil {
+			status.State = kueuev1alpha1.ResourceStateFailed
+			status.Message = err.Error()
+			result.errors = append(result.errors, fmt.Errorf("%s: %w", step.name, err))
+		} else if len(conflicts) > 0 {
+			status.Message = fmt.Sprintf("took ownership of fields from other managers: %s", strings.Join(conflicts, "; "))
+		}
+
+		states[step.name] = status.State
+		result.resources = append(result.resources, status)
+	}
+	return result
+}
+
+// stepRoute identifies an object applied by the pipeline, like a queue item does.
+type stepRoute struct {
+	kind string
+	name string
+}
+
+var (
+	stepRoutesOnce sync.Once
+	stepRoutes     map[stepRoute]string
+	routedObjects  map[string]map[string]runtime.Object
+)
+
+// routingKueue enables every optional step of the pipeline.
+var routingKueue = &kueuev1alpha1.Kueue{
+	Spec: kueuev1alpha1.KueueOperandSpec{
+		Canary: &kueuev1alpha1.Canary{
+			NamespaceSelector: metav1.LabelSelector{MatchLabels: map[string]string{"canary": "true"}},
+		},
+	},
+}
+
+// renderRoutingPipeline renders the pipeline for routingKueue once. The names of the
+// objects do not depend on the Kueue CR.
+func renderRoutingPipeline() {
+	stepRoutesOnce.Do(func() {
+		stepRoutes = map[stepRoute]string{}
+		routedObjects = map[string]map[string]runtime.Object{}
+		for _, step := range resourcePipeline() {
+			objects, err := step.required(routingKueue, map[string]string{})
+			if err != nil {
+				klog.ErrorS(err, "Unable to route the objects of a pipeline step", "step", step.name)
+				continue
+			}
+			routedObjects[step.name] = objects
+			for _, obj := range objects {
+				accessor, err := meta.Accessor(obj)
+				if err != nil {
+					continue
+				}
+				stepRoutes[stepRoute{kind: resourceName(obj), name: accessor.GetName()}] = step.name
+			}
+		}
+	})
+}
+
+// pipelineStepRoutes maps every object the pipeline applies to the step applying it,
+// from the pipeline rendered for routingKueue. Objects of a step that cannot be rendered
+// are not routed and call for a full reconcile.
+func pipelineStepRoutes() map[stepRoute]string {
+	renderRoutingPipeline()
+	return stepRoutes
+}
+
+// pipelineRoutedObjects returns the objects of every step rendered for routingKueue,
+// keyed by step name.
+func pipelineRoutedObjects() map[string]map[string]runtime.Object {
+	renderRoutingPipeline()
+	return routedObjects
+}
+
+// selectSteps returns the step that applies the object of a queue item, together with
+// every step that depends on it. It returns nil when the item calls for a full reconcile:
+// for the Kueue CR itself, for objects no step applies, and as long as some step has
+// never run.
+func selectSteps(kueue *kueuev1alpha1.Kueue, steps []resourceApplier, item queueItem) sets.Set[string] {
+	if item.kind == "kueue" {
+		return nil
+	}
+	for _, step := range steps {
+		if !slices.ContainsFunc(kueue.Status.Resources, func(status kueuev1alpha1.ResourceStatus) bool { return status.Name == step.name }) {
+			return nil
+		}
+	}
+
+	stepName, ok := pipelineStepRoutes()[stepRoute{kind: item.kind, name: item.name}]
+	if !ok {
+		return nil
+	}
+	selected := sets.New(stepName)
+	for _, step := range steps {
+		if selected.HasAny(step.dependsOn...) {
+			selected.Insert(step.name)
+		}
+	}
+	return selected
+}
+
+// resourceName returns the resource of an object applied by the pipeline, as used in
+// queue items.
+func resourceName(obj runtime.Object) string {
+	switch obj.(type) {
+	case *v1.ConfigMap:
+		return "configmaps"
+	case *v1.ServiceAccount:
+		return "serviceaccounts"
+	case *v1.Secret:
+		return "secrets"
+	case *v1.Service:
+		return "services"
+	case *rbacv1.Role:
+		return "roles"
+	case *rbacv1.RoleBinding:
+		return "rolebindings"
+	case *rbacv1.ClusterRole:
+		return "clusterroles"
+	case *rbacv1.ClusterRoleBinding:
+		return "clusterrolebindings"
+	case *apiextensionsv1.CustomResourceDefinition:
+		return "customresourcedefinitions"
+	case *appsv1.Deployment:
+		return "deployments"
+	case *admissionregistrationv1.MutatingWebhookConfiguration:
+		return "mutatingwebhookconfigurations"
+	case *admissionregistrationv1.ValidatingWebhookConfiguration:
+		return "validatingwebhookconfigurations"
+	default:
+		return ""
+	}
+}
+
+// requiredObjects returns the objects of a step labelled as managed by the operator.
+func (step resourceApplier) requiredObjects(kueue *kueuev1alpha1.Kueue, specAnnotations map[string]string) (map[string]runtime.Object, error) {
+	objects, err := step.required(kueue, specAnnotations)
+	if err != nil {
+		return nil, err
+	}
+	for _, obj := range objects {
+		setManagedByLabel(obj)
+	}
+	return objects, nil
+}
+
+// applyStep applies the objects of a step and returns the conflicts that had to be forced.
+func (c *TargetConfigReconciler) applyStep(kueue *kueuev1alpha1.Kueue, step resourceApplier, specAnnotations map[string]string, result *pipelineResult) ([]string, error) {
+	objects, err := step.requiredObjects(kueue, specAnnotations)
+	if err != nil {
+		return nil, err
+	}
+	var conflicts []string
+	// Apply in a stable order so that events and errors are reproducible.
+	for _, key := range sets.List(sets.KeySet(objects)) {
+		applied, conflict, err := c.applyObject(objects[key])
+		if err != nil {
+			return conflicts, err
+		}
+		if len(conflict) > 0 {
+			conflicts = append(conflicts, conflict)
+		}
+		if deployment, ok := applied.(*appsv1.Deployment); ok {
+			result.deployment = deployment
+		}
+		c.appliedVersions.record(applied)
+		if !contentHashAnnotations.Has(key) {
+			specAnnotations[key] = applied.GetResourceVersion()
+			continue
+		}
+		hash := contentHash(applied)
+		if previous, ok := specAnnotations[key]; ok && previous != hash && kueue.Spec.Config.ChangePolicy == kueuev1alpha1.ConfigChangePolicyOnNextRestart {
+			result.pendingRestart = append(result.pendingRestart, key)
+			continue
+		}
+		specAnnotations[key] = hash
+	}
+	return conflicts, nil
+}
+
+// contentHash hashes the data of a ConfigMap or Secret.
+func contentHash(obj metav1.Object) string {
+	data := map[string]string{}
+	switch obj := obj.(type) {
+	case *v1.ConfigMap:
+		for key, value := range obj.Data {
+			data[key] = value
+		}
+		for key, value := range obj.BinaryData {
+			data[key] = string(value)
+		}
+	case *v1.Secret:
+		for key, value := range obj.Data {
+			data[key] = string(value)
+		}
+	default:
+		return obj.GetResourceVersion()
+	}
+	return configmap.Hash(data)
+}
+
+// mergeResourceStatuses keeps the lastTransitionTime of steps whose state did not change.
+func mergeResourceStatuses(existing, updated []kueuev1alpha1.ResourceStatus, now metav1.Time) []kueuev1alpha1.ResourceStatus {
+	previous := map[string]kueuev1alpha1.ResourceStatus{}
+	for _, status := range existing {
+		previous[status.Name] = status
+	}
+	merged := make([]kueuev1alpha1.ResourceStatus, 0, len(updated))
+	for _, status := range updated {
+		status.LastTransitionTime = now
+		if old, ok := previous[status.Name]; ok && old.State == status.State {
+			status.LastTransitionTime = old.LastTransitionTime
+		}
+		merged = append(merged, status)
+	}
+	return merged
+}
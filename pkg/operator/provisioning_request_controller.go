@@ -0,0 +1,172 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	kueuev1alpha1 "github.com/openshift/kueue-operator/pkg/apis/kueueoperator/v1alpha1"
+	kueueconfigclient "github.com/openshift/kueue-operator/pkg/generated/clientset/versioned/typed/kueueoperator/v1alpha1"
+	operatorclientinformers "github.com/openshift/kueue-operator/pkg/generated/informers/externalversions/kueueoperator/v1alpha1"
+	"github.com/openshift/kueue-operator/pkg/namespace"
+	"github.com/openshift/kueue-operator/pkg/operator/operatorclient"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// provisioningACCFeatureGate enables the ProvisioningRequest admission check controller of Kueue.
+	provisioningACCFeatureGate = "ProvisioningACC"
+
+	provisioningRequestReadyCondition = "ProvisioningRequestReady"
+
+	// The cluster autoscaler can be installed at any time, nothing watches its CRD.
+	provisioningRequestResyncInterval = time.Minute
+)
+
+var (
+	// provisioningRequestsGVR is the version of ProvisioningRequests Kueue creates.
+	provisioningRequestsGVR = schema.GroupVersionResource{Group: "autoscaling.x-k8s.io", Version: "v1", Resource: "provisioningrequests"}
+
+	customResourceDefinitionsGVR = apiextensionsv1.SchemeGroupVersion.WithResource("customresourcedefinitions")
+)
+
+// ProvisioningRequestController reports whether the ProvisioningRequest admission check
+// enabled by spec.provisioningRequest can work, that is whether the cluster autoscaler
+// serves the ProvisioningRequest API Kueue uses. The feature gate and the RBAC of Kueue
+// are rendered by the TargetConfigReconciler.
+type ProvisioningRequestController struct {
+	ctx               context.Context
+	operatorClient    kueueconfigclient.KueueV1alpha1Interface
+	dynamicClient     dynamic.Interface
+	eventRecorder     events.Recorder
+	operatorNamespace string
+}
+
+func NewProvisioningRequestController(
+	ctx context.Context,
+	operatorConfigClient kueueconfigclient.KueueV1alpha1Interface,
+	operatorClientInformer operatorclientinformers.KueueInformer,
+	dynamicClient dynamic.Interface,
+	eventRecorder events.Recorder,
+) (factory.Controller, error) {
+	c := &ProvisioningRequestController{
+		ctx:               ctx,
+		operatorClient:    operatorConfigClient,
+		dynamicClient:     dynamicClient,
+		eventRecorder:     eventRecorder.WithComponentSuffix("provisioning-request-controller"),
+		operatorNamespace: namespace.GetNamespace(),
+	}
+
+	syncCtx := factory.NewSyncContext("ProvisioningRequestController", eventRecorder)
+	if _, err := operatorClientInformer.Informer().AddEventHandler(queueHandler(syncCtx, kueueSpecChanged)); err != nil {
+		return nil, err
+	}
+
+	return factory.New().
+		WithSyncContext(syncCtx).
+		WithBareInformers(operatorClientInformer.Informer()).
+		WithSync(func(ctx context.Context, _ factory.SyncContext) error { return c.sync() }).
+		ResyncEvery(provisioningRequestResyncInterval).
+		ToController("ProvisioningRequestController", c.eventRecorder), nil
+}
+
+func (c *ProvisioningRequestController) sync() error {
+	kueue, err := c.operatorClient.Kueues(c.operatorNamespace).Get(c.ctx, operatorclient.OperatorConfigName, metav1.GetOptions{})
+	if err != nil {
+		klog.ErrorS(err, "unable to get operator configuration", "namespace", c.operatorNamespace, "kueue", operatorclient.OperatorConfigName)
+		return err
+	}
+
+	enabled := kueue.Spec.ProvisioningRequest != nil && kueue.Spec.ProvisioningRequest.Enabled
+	var ready operatorv1.OperatorCondition
+	var syncErr error
+	if enabled {
+		ready, syncErr = c.readiness()
+	}
+
+	degraded := operatorv1.OperatorCondition{
+		Type:   "ProvisioningRequestControllerDegraded",
+		Status: operatorv1.ConditionFalse,
+		Reason: "AsExpected",
+	}
+	if syncErr != nil {
+		degraded.Status = operatorv1.ConditionTrue
+		degraded.Reason = "SyncFailed"
+		degraded.Message = syncErr.Error()
+	}
+	if err := updateKueueStatus(c.ctx, c.operatorClient, c.operatorNamespace, kueue.Name, func(status *kueuev1alpha1.KueueStatus) {
+		if !enabled {
+			v1helpers.RemoveOperatorCondition(&status.Conditions, provisioningRequestReadyCondition)
+		} else if syncErr == nil {
+			v1helpers.SetOperatorCondition(&status.Conditions, ready)
+		}
+		v1helpers.SetOperatorCondition(&status.Conditions, degraded)
+	}); err != nil {
+		return err
+	}
+	if enabled && syncErr == nil && ready.Status == operatorv1.ConditionTrue &&
+		!v1helpers.IsOperatorConditionTrue(kueue.Status.Conditions, provisioningRequestReadyCondition) {
+		c.eventRecorder.Eventf("ProvisioningRequestReady", "The cluster autoscaler serves %s, ProvisioningRequest admission checks are ready", provisioningRequestsGVR.GroupVersion())
+	}
+	return syncErr
+}
+
+// readiness checks that the ProvisioningRequest CRD is installed, established and serves
+// the version Kueue uses.
+func (c *ProvisioningRequestController) readiness() (operatorv1.OperatorCondition, error) {
+	ready := operatorv1.OperatorCondition{
+		Type:   provisioningRequestReadyCondition,
+		Status: operatorv1.ConditionFalse,
+	}
+	crdName := provisioningRequestsGVR.GroupResource().String()
+	obj, err := c.dynamicClient.Resource(customResourceDefinitionsGVR).Get(c.ctx, crdName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		ready.Reason = "CRDNotFound"
+		ready.Message = fmt.Sprintf("CustomResourceDefinition %s not found, install a cluster autoscaler that supports ProvisioningRequests", crdName)
+		return ready, nil
+	}
+	if err != nil {
+		return ready, err
+	}
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, crd); err != nil {
+		return ready, err
+	}
+
+	established := false
+	for _, condition := range crd.Status.Conditions {
+		if condition.Type == apiextensionsv1.Established && condition.Status == apiextensionsv1.ConditionTrue {
+			established = true
+		}
+	}
+	if !established {
+		ready.Reason = "CRDNotEstablished"
+		ready.Message = fmt.Sprintf("CustomResourceDefinition %s is not established", crdName)
+		return ready, nil
+	}
+	served := false
+	for _, version := range crd.Spec.Versions {
+		if version.Name == provisioningRequestsGVR.Version && version.Served {
+			served = true
+		}
+	}
+	if !served {
+		ready.Reason = "VersionNotServed"
+		ready.Message = fmt.Sprintf("CustomResourceDefinition %s does not serve %s, which Kueue requires", crdName, provisioningRequestsGVR.GroupVersion())
+		return ready, nil
+	}
+
+	ready.Status = operatorv1.ConditionTrue
+	ready.Reason = "AsExpected"
+	return ready, nil
+}
@@ -0,0 +1,156 @@
+package operator
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/utils/ptr"
+
+	kueuev1alpha1 "github.com/openshift/kueue-operator/pkg/apis/kueueoperator/v1alpha1"
+	"github.com/openshift/kueue-operator/pkg/namespace"
+	"github.com/openshift/kueue-operator/pkg/operator/operatorclient"
+)
+
+func newTestProvisioningRequestCRD(t *testing.T, established bool, versions ...string) *unstructured.Unstructured {
+	t.Helper()
+	crd := &apiextensionsv1.CustomResourceDefinition{
+		TypeMeta:   metav1.TypeMeta{APIVersion: apiextensionsv1.SchemeGroupVersion.String(), Kind: "CustomResourceDefinition"},
+		ObjectMeta: metav1.ObjectMeta{Name: provisioningRequestsGVR.GroupResource().String()},
+	}
+	for _, version := range versions {
+		crd.Spec.Versions = append(crd.Spec.Versions, apiextensionsv1.CustomResourceDefinitionVersion{Name: version, Served: true})
+	}
+	if established {
+		crd.Status.Conditions = []apiextensionsv1.CustomResourceDefinitionCondition{{Type: apiextensionsv1.Established, Status: apiextensionsv1.ConditionTrue}}
+	}
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(crd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &unstructured.Unstructured{Object: content}
+}
+
+func TestProvisioningRequestController(t *testing.T) {
+	tests := []struct {
+		name       string
+		enabled    *bool
+		crd        *unstructured.Unstructured
+		wantStatus operatorv1.ConditionStatus
+		wantReason string
+	}{
+		{
+			name: "not configured",
+		},
+		{
+			name:    "disabled",
+			enabled: ptr.To(false),
+			crd:     newTestProvisioningRequestCRD(t, true, "v1"),
+		},
+		{
+			name:       "ready",
+			enabled:    ptr.To(true),
+			crd:        newTestProvisioningRequestCRD(t, true, "v1beta1", "v1"),
+			wantStatus: operatorv1.ConditionTrue,
+			wantReason: "AsExpected",
+		},
+		{
+			name:       "no cluster autoscaler",
+			enabled:    ptr.To(true),
+			wantStatus: operatorv1.ConditionFalse,
+			wantReason: "CRDNotFound",
+		},
+		{
+			name:       "not established",
+			enabled:    ptr.To(true),
+			crd:        newTestProvisioningRequestCRD(t, false, "v1"),
+			wantStatus: operatorv1.ConditionFalse,
+			wantReason: "CRDNotEstablished",
+		},
+		{
+			name:       "old cluster autoscaler",
+			enabled:    ptr.To(true),
+			crd:        newTestProvisioningRequestCRD(t, true, "v1beta1"),
+			wantStatus: operatorv1.ConditionFalse,
+			wantReason: "VersionNotServed",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			kueue := newTestKueue()
+			if tc.enabled != nil {
+				kueue.Spec.ProvisioningRequest = &kueuev1alpha1.ProvisioningRequest{Enabled: *tc.enabled}
+			}
+			defaults, _ := newTestDefaultsController(t, kueue)
+			var objects []runtime.Object
+			if tc.crd != nil {
+				objects = append(objects, tc.crd)
+			}
+			c := &ProvisioningRequestController{
+				ctx:               defaults.ctx,
+				operatorClient:    defaults.operatorClient,
+				dynamicClient:     dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), objects...),
+				eventRecorder:     defaults.eventRecorder,
+				operatorNamespace: defaults.operatorNamespace,
+			}
+
+			if err := c.sync(); err != nil {
+				t.Fatalf("Unexpected sync error: %v", err)
+			}
+
+			kueue, err := c.operatorClient.Kueues(namespace.GetNamespace()).Get(c.ctx, operatorclient.OperatorConfigName, metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			condition := v1helpers.FindOperatorCondition(kueue.Status.Conditions, provisioningRequestReadyCondition)
+			if len(tc.wantStatus) == 0 {
+				if condition != nil {
+					t.Errorf("Unexpected condition %v", condition)
+				}
+				return
+			}
+			if condition == nil || condition.Status != tc.wantStatus || condition.Reason != tc.wantReason {
+				t.Errorf("Expected %s %s/%s, got %v", provisioningRequestReadyCondition, tc.wantStatus, tc.wantReason, condition)
+			}
+		})
+	}
+}
+
+func TestProvisioningRequestRendering(t *testing.T) {
+	kueue := newTestKueue()
+	kueue.Spec.Config.FeatureGates = map[string]bool{"ProvisioningACC": true, "PartialAdmission": false}
+
+	hasAutoscalingRules := func() bool {
+		role := requiredClusterRoles(kueue)["clusterrole_10.yml"]
+		return slices.ContainsFunc(role.Rules, func(rule rbacv1.PolicyRule) bool {
+			return slices.Contains(rule.APIGroups, provisioningRequestsGVR.Group)
+		})
+	}
+
+	if got := kueueFeatureGates(kueue); got != "PartialAdmission=false,ProvisioningACC=true" {
+		t.Errorf("Unexpected feature gates %q", got)
+	}
+	if !hasAutoscalingRules() {
+		t.Errorf("Expected the kueue-manager-role to keep its ProvisioningRequest rules")
+	}
+
+	kueue.Spec.ProvisioningRequest = &kueuev1alpha1.ProvisioningRequest{Enabled: false}
+	if got := kueueFeatureGates(kueue); got != "PartialAdmission=false,ProvisioningACC=false" {
+		t.Errorf("Unexpected feature gates %q", got)
+	}
+	if hasAutoscalingRules() {
+		t.Errorf("Expected the ProvisioningRequest rules to be removed from the kueue-manager-role")
+	}
+	args := requiredDeployment(kueue, nil).Spec.Template.Spec.Containers[0].Args
+	if diff := cmp.Diff("--feature-gates=PartialAdmission=false,ProvisioningACC=false", args[len(args)-1]); len(diff) != 0 {
+		t.Errorf("Unexpected args (-want,+got):\n%s", diff)
+	}
+}
@@ -0,0 +1,311 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"time"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	userv1 "github.com/openshift/api/user/v1"
+	kueuev1alpha1 "github.com/openshift/kueue-operator/pkg/apis/kueueoperator/v1alpha1"
+	kueueconfigclient "github.com/openshift/kueue-operator/pkg/generated/clientset/versioned/typed/kueueoperator/v1alpha1"
+	operatorclientinformers "github.com/openshift/kueue-operator/pkg/generated/informers/externalversions/kueueoperator/v1alpha1"
+	"github.com/openshift/kueue-operator/pkg/namespace"
+	"github.com/openshift/kueue-operator/pkg/operator/operatorclient"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// queueAccessLabel marks the RoleBindings created for spec.queueAccess. Only these
+	// are updated and deleted by the controller.
+	queueAccessLabel = "kueue.openshift.io/queue-access"
+
+	queueAccessResyncInterval = 10 * time.Minute
+)
+
+var (
+	groupsGVR = userv1.GroupVersion.WithResource("groups")
+
+	queueAccessClusterRoles = map[kueuev1alpha1.QueueAccessRole]string{
+		kueuev1alpha1.QueueAccessRoleAdmin: "kueue-batch-admin-role",
+		kueuev1alpha1.QueueAccessRoleUser:  "kueue-batch-user-role",
+	}
+)
+
+// QueueAccessController binds the kueue-batch-admin-role and kueue-batch-user-role
+// ClusterRoles to groups in the namespaces listed in spec.queueAccess of the Kueue CR.
+// RoleBindings of entries or namespaces that are removed from the CR are deleted.
+type QueueAccessController struct {
+	ctx               context.Context
+	operatorClient    kueueconfigclient.KueueV1alpha1Interface
+	kubeClient        kubernetes.Interface
+	dynamicClient     dynamic.Interface
+	namespaceLister   corev1listers.NamespaceLister
+	eventRecorder     events.Recorder
+	operatorNamespace string
+}
+
+func NewQueueAccessController(
+	ctx context.Context,
+	operatorConfigClient kueueconfigclient.KueueV1alpha1Interface,
+	operatorClientInformer operatorclientinformers.KueueInformer,
+	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
+	kubeClient kubernetes.Interface,
+	dynamicClient dynamic.Interface,
+	eventRecorder events.Recorder,
+) (factory.Controller, error) {
+	namespaceInformer := kubeInformersForNamespaces.InformersFor("").Core().V1().Namespaces()
+	c := &QueueAccessController{
+		ctx:               ctx,
+		operatorClient:    operatorConfigClient,
+		kubeClient:        kubeClient,
+		dynamicClient:     dynamicClient,
+		namespaceLister:   namespaceInformer.Lister(),
+		eventRecorder:     eventRecorder.WithComponentSuffix("queue-access-controller"),
+		operatorNamespace: namespace.GetNamespace(),
+	}
+
+	syncCtx := factory.NewSyncContext("QueueAccessController", eventRecorder)
+	if _, err := operatorClientInformer.Informer().AddEventHandler(queueHandler(syncCtx, kueueSpecChanged)); err != nil {
+		return nil, err
+	}
+
+	return factory.New().
+		WithSyncContext(syncCtx).
+		WithInformers(namespaceInformer.Informer()).
+		WithBareInformers(operatorClientInformer.Informer()).
+		WithSync(func(ctx context.Context, _ factory.SyncContext) error { return c.sync() }).
+		// Restore RoleBindings that were edited or deleted, nothing watches them.
+		ResyncEvery(queueAccessResyncInterval).
+		ToController("QueueAccessController", c.eventRecorder), nil
+}
+
+func (c *QueueAccessController) sync() error {
+	kueue, err := c.operatorClient.Kueues(c.operatorNamespace).Get(c.ctx, operatorclient.OperatorConfigName, metav1.GetOptions{})
+	if err != nil {
+		klog.ErrorS(err, "unable to get operator configuration", "namespace", c.operatorNamespace, "kueue", operatorclient.OperatorConfigName)
+		return err
+	}
+
+	var errs []error
+	statuses := make([]kueuev1alpha1.QueueAccessStatus, 0, len(kueue.Spec.QueueAccess))
+	wanted := sets.New[string]()
+	for _, access := range kueue.Spec.QueueAccess {
+		status := kueuev1alpha1.QueueAccessStatus{Group: access.Group, Role: access.Role}
+		var messages []string
+		if message := c.checkGroup(access.Group); len(message) > 0 {
+			messages = append(messages, message)
+		}
+
+		namespaces, missing, err := c.accessNamespaces(access)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("queueAccess %s/%s: %w", access.Group, access.Role, err))
+			messages = append(messages, err.Error())
+		}
+		if len(missing) > 0 {
+			messages = append(messages, fmt.Sprintf("namespaces not found: %s", strings.Join(missing, ", ")))
+		}
+
+		for _, ns := range namespaces {
+			binding := buildQueueAccessRoleBinding(ns, access)
+			wanted.Insert(ns + "/" + binding.Name)
+			bound, err := c.applyRoleBinding(binding)
+			if err != nil {
+				errs = append(errs, err)
+				messages = append(messages, err.Error())
+				continue
+			}
+			if !bound {
+				messages = append(messages, fmt.Sprintf("RoleBinding %s/%s exists and is not managed by the operator", ns, binding.Name))
+				continue
+			}
+			status.Namespaces = append(status.Namespaces, ns)
+		}
+		status.Message = strings.Join(messages, "; ")
+		statuses = append(statuses, status)
+	}
+
+	existing, err := c.kubeClient.RbacV1().RoleBindings("").List(c.ctx, metav1.ListOptions{LabelSelector: queueAccessLabel})
+	if err != nil {
+		errs = append(errs, err)
+	} else {
+		for _, binding := range existing.Items {
+			if wanted.Has(binding.Namespace + "/" + binding.Name) {
+				continue
+			}
+			if err := c.kubeClient.RbacV1().RoleBindings(binding.Namespace).Delete(c.ctx, binding.Name, metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+				errs = append(errs, fmt.Errorf("unable to delete RoleBinding %s/%s: %w", binding.Namespace, binding.Name, err))
+				continue
+			}
+			c.eventRecorder.Eventf("RoleBindingDeleted", "Deleted RoleBinding %s/%s", binding.Namespace, binding.Name)
+		}
+	}
+
+	degraded := operatorv1.OperatorCondition{
+		Type:   "QueueAccessControllerDegraded",
+		Status: operatorv1.ConditionFalse,
+		Reason: "AsExpected",
+	}
+	if len(errs) > 0 {
+		degraded.Status = operatorv1.ConditionTrue
+		degraded.Reason = "SyncFailed"
+		degraded.Message = utilerrors.NewAggregate(errs).Error()
+	}
+	if err := updateKueueStatus(c.ctx, c.operatorClient, c.operatorNamespace, kueue.Name, func(status *kueuev1alpha1.KueueStatus) {
+		status.QueueAccess = statuses
+		if len(statuses) == 0 {
+			status.QueueAccess = nil
+		}
+		v1helpers.SetOperatorCondition(&status.Conditions, degraded)
+	}); err != nil {
+		errs = append(errs, err)
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+// checkGroup returns a message when the group is not an OpenShift Group. The binding is
+// still created, groups can also come from the identity provider and a Group created
+// later takes effect right away.
+func (c *QueueAccessController) checkGroup(name string) string {
+	_, err := c.dynamicClient.Resource(groupsGVR).Get(c.ctx, name, metav1.GetOptions{})
+	switch {
+	case err == nil:
+		return ""
+	case errors.IsNotFound(err):
+		return fmt.Sprintf("group %s not found", name)
+	default:
+		klog.V(2).InfoS("Unable to look up group", "group", name, "err", err)
+		return ""
+	}
+}
+
+// accessNamespaces returns the sorted namespaces the entry grants access in and the
+// listed namespaces that do not exist.
+func (c *QueueAccessController) accessNamespaces(access kueuev1alpha1.QueueAccess) ([]string, []string, error) {
+	namespaces := sets.New[string]()
+	var missing []string
+	for _, name := range access.Namespaces {
+		ns, err := c.namespaceLister.Get(name)
+		if errors.IsNotFound(err) {
+			missing = append(missing, name)
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		if ns.Status.Phase != corev1.NamespaceTerminating {
+			namespaces.Insert(name)
+		}
+	}
+	if access.NamespaceSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(access.NamespaceSelector)
+		if err != nil {
+			return sets.List(namespaces), missing, fmt.Errorf("invalid namespaceSelector: %w", err)
+		}
+		selected, err := c.namespaceLister.List(selector)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, ns := range selected {
+			if ns.Status.Phase != corev1.NamespaceTerminating {
+				namespaces.Insert(ns.Name)
+			}
+		}
+	}
+	return sets.List(namespaces), missing, nil
+}
+
+// applyRoleBinding creates the binding or updates the subjects of a binding it created
+// before. It returns false when a binding of the same name was created by someone else,
+// which is left alone.
+func (c *QueueAccessController) applyRoleBinding(required *rbacv1.RoleBinding) (bool, error) {
+	client := c.kubeClient.RbacV1().RoleBindings(required.Namespace)
+	existing, err := client.Get(c.ctx, required.Name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		if _, err := client.Create(c.ctx, required, metav1.CreateOptions{FieldManager: fieldManager}); err != nil {
+			return false, fmt.Errorf("unable to create RoleBinding %s/%s: %w", required.Namespace, required.Name, err)
+		}
+		c.eventRecorder.Eventf("RoleBindingCreated", "Created RoleBinding %s/%s granting %s to group %s", required.Namespace, required.Name, required.RoleRef.Name, required.Subjects[0].Name)
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if _, ok := existing.Labels[queueAccessLabel]; !ok {
+		return false, nil
+	}
+	// Bindings created before the controller-managed label carry the one of the operand,
+	// which is updated along with the subjects.
+	if equality.Semantic.DeepEqual(existing.Subjects, required.Subjects) && existing.RoleRef == required.RoleRef && existing.Labels[managedByLabel] == required.Labels[managedByLabel] {
+		return true, nil
+	}
+	if existing.RoleRef != required.RoleRef {
+		// The roleRef of a binding is immutable.
+		if err := client.Delete(c.ctx, existing.Name, metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+			return false, err
+		}
+		return c.applyRoleBinding(required)
+	}
+	updated := existing.DeepCopy()
+	updated.Subjects = required.Subjects
+	updated.Labels[managedByLabel] = required.Labels[managedByLabel]
+	if _, err := client.Update(c.ctx, updated, metav1.UpdateOptions{FieldManager: fieldManager}); err != nil {
+		return false, fmt.Errorf("unable to update RoleBinding %s/%s: %w", required.Namespace, required.Name, err)
+	}
+	return true, nil
+}
+
+func buildQueueAccessRoleBinding(ns string, access kueuev1alpha1.QueueAccess) *rbacv1.RoleBinding {
+	clusterRole := queueAccessClusterRoles[access.Role]
+	if len(clusterRole) == 0 {
+		clusterRole = queueAccessClusterRoles[kueuev1alpha1.QueueAccessRoleUser]
+	}
+	binding := &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      queueAccessRoleBindingName(clusterRole, access.Group),
+			Namespace: ns,
+			Labels:    map[string]string{queueAccessLabel: "true"},
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "ClusterRole",
+			Name:     clusterRole,
+		},
+		Subjects: []rbacv1.Subject{{
+			APIGroup: rbacv1.GroupName,
+			Kind:     rbacv1.GroupKind,
+			Name:     access.Group,
+		}},
+	}
+	setControllerManagedLabel(binding)
+	return binding
+}
+
+// queueAccessRoleBindingName is "<cluster role>-<group>", with a hash instead of the group
+// when the group name is not valid in an object name.
+func queueAccessRoleBindingName(clusterRole, group string) string {
+	name := strings.TrimSuffix(clusterRole, "-role") + "-" + group
+	if len(validation.IsDNS1123Subdomain(name)) == 0 {
+		return name
+	}
+	h := fnv.New32a()
+	h.Write([]byte(group))
+	return fmt.Sprintf("%s-%08x", strings.TrimSuffix(clusterRole, "-role"), h.Sum32())
+}
@@ -0,0 +1,150 @@
+package operator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	userv1 "github.com/openshift/api/user/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	kueuev1alpha1 "github.com/openshift/kueue-operator/pkg/apis/kueueoperator/v1alpha1"
+	"github.com/openshift/kueue-operator/pkg/namespace"
+	"github.com/openshift/kueue-operator/pkg/operator/operatorclient"
+)
+
+func newTestQueueAccessController(t *testing.T, kueue *kueuev1alpha1.Kueue, groups []string, namespaces ...*corev1.Namespace) *QueueAccessController {
+	t.Helper()
+	defaults, _ := newTestDefaultsController(t, kueue)
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, ns := range namespaces {
+		if err := indexer.Add(ns); err != nil {
+			t.Fatal(err)
+		}
+	}
+	scheme := runtime.NewScheme()
+	if err := userv1.Install(scheme); err != nil {
+		t.Fatal(err)
+	}
+	var objects []runtime.Object
+	for _, group := range groups {
+		objects = append(objects, &userv1.Group{ObjectMeta: metav1.ObjectMeta{Name: group}})
+	}
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(scheme, map[schema.GroupVersionResource]string{
+		groupsGVR: "GroupList",
+	}, objects...)
+	return &QueueAccessController{
+		ctx:               defaults.ctx,
+		operatorClient:    defaults.operatorClient,
+		kubeClient:        kubefake.NewClientset(),
+		dynamicClient:     dynamicClient,
+		namespaceLister:   corev1listers.NewNamespaceLister(indexer),
+		eventRecorder:     defaults.eventRecorder,
+		operatorNamespace: defaults.operatorNamespace,
+	}
+}
+
+func (c *QueueAccessController) roleBindings(t *testing.T) []string {
+	t.Helper()
+	bindings, err := c.kubeClient.RbacV1().RoleBindings("").List(c.ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, binding := range bindings.Items {
+		got = append(got, binding.Namespace+"/"+binding.Name+" "+binding.RoleRef.Name+" "+binding.Subjects[0].Name)
+	}
+	return got
+}
+
+func TestQueueAccessController(t *testing.T) {
+	kueue := newTestKueue()
+	kueue.Spec.QueueAccess = []kueuev1alpha1.QueueAccess{
+		{
+			Group:      "ml-admins",
+			Role:       kueuev1alpha1.QueueAccessRoleAdmin,
+			Namespaces: []string{"team-a", "missing"},
+		},
+		{
+			Group:             "ml-users",
+			Role:              kueuev1alpha1.QueueAccessRoleUser,
+			NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "ml"}},
+		},
+	}
+	c := newTestQueueAccessController(t, kueue, []string{"ml-admins"},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a", Labels: map[string]string{"team": "ml"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-b", Labels: map[string]string{"team": "ml"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "web"}},
+	)
+
+	// A binding of the same name created by an admin is not taken over.
+	if _, err := c.kubeClient.RbacV1().RoleBindings("team-b").Create(c.ctx, &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: "kueue-batch-user-ml-users", Namespace: "team-b"},
+		RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: "view"},
+		Subjects:   []rbacv1.Subject{{APIGroup: rbacv1.GroupName, Kind: rbacv1.GroupKind, Name: "someone-else"}},
+	}, metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.sync(); err != nil {
+		t.Fatalf("Unexpected sync error: %v", err)
+	}
+
+	if diff := cmp.Diff([]string{
+		"team-a/kueue-batch-admin-ml-admins kueue-batch-admin-role ml-admins",
+		"team-a/kueue-batch-user-ml-users kueue-batch-user-role ml-users",
+		"team-b/kueue-batch-user-ml-users view someone-else",
+	}, c.roleBindings(t)); len(diff) != 0 {
+		t.Errorf("Unexpected RoleBindings (-want,+got):\n%s", diff)
+	}
+
+	kueue, err := c.operatorClient.Kueues(namespace.GetNamespace()).Get(c.ctx, operatorclient.OperatorConfigName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(kueue.Status.QueueAccess) != 2 {
+		t.Fatalf("Expected the status of both entries, got %v", kueue.Status.QueueAccess)
+	}
+	admins, users := kueue.Status.QueueAccess[0], kueue.Status.QueueAccess[1]
+	if diff := cmp.Diff([]string{"team-a"}, admins.Namespaces); len(diff) != 0 || !strings.Contains(admins.Message, "namespaces not found: missing") {
+		t.Errorf("Unexpected status of ml-admins: %+v", admins)
+	}
+	if diff := cmp.Diff([]string{"team-a"}, users.Namespaces); len(diff) != 0 ||
+		!strings.Contains(users.Message, "group ml-users not found") ||
+		!strings.Contains(users.Message, "team-b/kueue-batch-user-ml-users exists") {
+		t.Errorf("Unexpected status of ml-users: %+v", users)
+	}
+
+	// Removing an entry deletes its RoleBindings.
+	kueue.Spec.QueueAccess = kueue.Spec.QueueAccess[1:]
+	if _, err := c.operatorClient.Kueues(namespace.GetNamespace()).Update(c.ctx, kueue, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.sync(); err != nil {
+		t.Fatalf("Unexpected sync error: %v", err)
+	}
+	if diff := cmp.Diff([]string{
+		"team-a/kueue-batch-user-ml-users kueue-batch-user-role ml-users",
+		"team-b/kueue-batch-user-ml-users view someone-else",
+	}, c.roleBindings(t)); len(diff) != 0 {
+		t.Errorf("Unexpected RoleBindings (-want,+got):\n%s", diff)
+	}
+}
+
+func TestQueueAccessRoleBindingName(t *testing.T) {
+	if got := queueAccessRoleBindingName("kueue-batch-user-role", "data-science"); got != "kueue-batch-user-data-science" {
+		t.Errorf("Unexpected name %q", got)
+	}
+	got := queueAccessRoleBindingName("kueue-batch-admin-role", "CN=Data Science,OU=Groups")
+	if !strings.HasPrefix(got, "kueue-batch-admin-") || strings.ContainsAny(got, "=, ") {
+		t.Errorf("Unexpected name %q", got)
+	}
+}
@@ -0,0 +1,420 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	kueuev1alpha1 "github.com/openshift/kueue-operator/pkg/apis/kueueoperator/v1alpha1"
+	"github.com/openshift/kueue-operator/pkg/builders/queues"
+	kueueconfigclient "github.com/openshift/kueue-operator/pkg/generated/clientset/versioned/typed/kueueoperator/v1alpha1"
+	operatorclientinformers "github.com/openshift/kueue-operator/pkg/generated/informers/externalversions/kueueoperator/v1alpha1"
+	"github.com/openshift/kueue-operator/pkg/namespace"
+	"github.com/openshift/kueue-operator/pkg/operator/operatorclient"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+	kueuev1beta1 "sigs.k8s.io/kueue/apis/kueue/v1beta1"
+)
+
+const (
+	// quotaManagementAnnotation set to quotaManagementManaged lets the operator scale the
+	// nominal quotas of a ClusterQueue when spec.quotaManagement.autoScale is true.
+	quotaManagementAnnotation = "kueue.openshift.io/quota-management"
+	quotaManagementManaged    = "managed"
+
+	quotaExceedsCapacityCondition = "QuotaExceedsCapacity"
+
+	quotaResyncInterval = 10 * time.Minute
+)
+
+// QuotaController compares the nominal quotas of the ClusterQueues with the allocatable
+// capacity of the nodes of each ResourceFlavor, as configured by spec.quotaManagement of
+// the Kueue CR. It reports the capacity and recommended quotas in the status, sets the
+// QuotaExceedsCapacity condition when the quotas of a flavor add up to more than its
+// nodes provide, and optionally applies the recommendations to managed ClusterQueues.
+type QuotaController struct {
+	ctx            context.Context
+	operatorClient kueueconfigclient.KueueV1alpha1Interface
+	dynamicClient  dynamic.Interface
+	nodeLister     corev1listers.NodeLister
+	// clusterQueueLister and flavorLister are synced once the Kueue CRDs are installed.
+	clusterQueueLister cache.GenericLister
+	flavorLister       cache.GenericLister
+	queuesSynced       cache.InformerSynced
+	eventRecorder      events.Recorder
+	operatorNamespace  string
+}
+
+func NewQuotaController(
+	ctx context.Context,
+	operatorConfigClient kueueconfigclient.KueueV1alpha1Interface,
+	operatorClientInformer operatorclientinformers.KueueInformer,
+	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
+	dynamicClient dynamic.Interface,
+	eventRecorder events.Recorder,
+) (factory.Controller, error) {
+	nodeInformer := kubeInformersForNamespaces.InformersFor("").Core().V1().Nodes()
+	queueInformer := dynamicinformer.NewDynamicSharedInformerFactory(dynamicClient, quotaResyncInterval)
+	clusterQueues := queueInformer.ForResource(queues.ClusterQueuesGVR)
+	flavors := queueInformer.ForResource(queues.ResourceFlavorsGVR)
+	c := &QuotaController{
+		ctx:                ctx,
+		operatorClient:     operatorConfigClient,
+		dynamicClient:      dynamicClient,
+		nodeLister:         nodeInformer.Lister(),
+		clusterQueueLister: clusterQueues.Lister(),
+		flavorLister:       flavors.Lister(),
+		queuesSynced: func() bool {
+			return clusterQueues.Informer().HasSynced() && flavors.Informer().HasSynced()
+		},
+		eventRecorder:     eventRecorder.WithComponentSuffix("quota-controller"),
+		operatorNamespace: namespace.GetNamespace(),
+	}
+
+	syncCtx := factory.NewSyncContext("QuotaController", eventRecorder)
+	if _, err := operatorClientInformer.Informer().AddEventHandler(queueHandler(syncCtx, kueueSpecChanged)); err != nil {
+		return nil, err
+	}
+	if _, err := nodeInformer.Informer().AddEventHandler(queueHandler(syncCtx, func(old, new interface{}) bool {
+		oldNode, ok := old.(*corev1.Node)
+		if !ok {
+			return false
+		}
+		newNode, ok := new.(*corev1.Node)
+		if !ok {
+			return false
+		}
+		return oldNode.Spec.Unschedulable != newNode.Spec.Unschedulable ||
+			!equality.Semantic.DeepEqual(oldNode.Labels, newNode.Labels) ||
+			!equality.Semantic.DeepEqual(oldNode.Status.Allocatable, newNode.Status.Allocatable)
+	})); err != nil {
+		return nil, err
+	}
+	// The usage in the status of ClusterQueues changes all the time, only quotas and the
+	// quota management annotation matter.
+	for _, informer := range []cache.SharedIndexInformer{clusterQueues.Informer(), flavors.Informer()} {
+		if _, err := informer.AddEventHandler(queueHandler(syncCtx, quotaInputChanged)); err != nil {
+			return nil, err
+		}
+	}
+
+	return factory.New().
+		WithSyncContext(syncCtx).
+		WithBareInformers(operatorClientInformer.Informer(), nodeInformer.Informer()).
+		WithPostStartHooks(startDynamicInformers(queueInformer)).
+		WithSync(func(ctx context.Context, _ factory.SyncContext) error { return c.sync() }).
+		ResyncEvery(quotaResyncInterval).
+		ToController("QuotaController", c.eventRecorder), nil
+}
+
+func (c *QuotaController) sync() error {
+	kueue, err := c.operatorClient.Kueues(c.operatorNamespace).Get(c.ctx, operatorclient.OperatorConfigName, metav1.GetOptions{})
+	if err != nil {
+		klog.ErrorS(err, "unable to get operator configuration", "namespace", c.operatorNamespace, "kueue", operatorclient.OperatorConfigName)
+		return err
+	}
+	if kueue.Spec.QuotaManagement == nil {
+		return updateKueueStatus(c.ctx, c.operatorClient, c.operatorNamespace, kueue.Name, func(status *kueuev1alpha1.KueueStatus) {
+			status.FlavorCapacities = nil
+			status.QuotaRecommendations = nil
+			v1helpers.RemoveOperatorCondition(&status.Conditions, quotaExceedsCapacityCondition)
+			v1helpers.RemoveOperatorCondition(&status.Conditions, "QuotaControllerDegraded")
+		})
+	}
+	if !c.queuesSynced() {
+		// Until the operand installs the Kueue CRDs there is nothing to compare. The post
+		// start hook queues a sync once the ClusterQueues and ResourceFlavors are cached.
+		return updateKueueStatus(c.ctx, c.operatorClient, c.operatorNamespace, kueue.Name, func(status *kueuev1alpha1.KueueStatus) {
+			v1helpers.SetOperatorCondition(&status.Conditions, operatorv1.OperatorCondition{
+				Type:    quotaExceedsCapacityCondition,
+				Status:  operatorv1.ConditionUnknown,
+				Reason:  "KueueNotReady",
+				Message: "waiting for the ClusterQueues and ResourceFlavors of Kueue",
+			})
+		})
+	}
+
+	nodes, err := c.nodeLister.List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	flavors, err := c.flavorLister.List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	clusterQueues, err := c.clusterQueueLister.List(labels.Everything())
+	if err != nil {
+		return err
+	}
+
+	// Nominal quotas by flavor and ClusterQueue.
+	quotas := map[string]map[string]corev1.ResourceList{}
+	managed := map[string]*unstructured.Unstructured{}
+	for _, obj := range clusterQueues {
+		item, ok := obj.(*unstructured.Unstructured)
+		if !ok {
+			continue
+		}
+		clusterQueue := &kueuev1beta1.ClusterQueue{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, clusterQueue); err != nil {
+			return fmt.Errorf("unable to decode ClusterQueue %s: %w", item.GetName(), err)
+		}
+		if clusterQueue.Annotations[quotaManagementAnnotation] == quotaManagementManaged {
+			managed[clusterQueue.Name] = item
+		}
+		for _, group := range clusterQueue.Spec.ResourceGroups {
+			for _, flavor := range group.Flavors {
+				if quotas[string(flavor.Name)] == nil {
+					quotas[string(flavor.Name)] = map[string]corev1.ResourceList{}
+				}
+				quota := corev1.ResourceList{}
+				for _, r := range flavor.Resources {
+					quota[r.Name] = r.NominalQuota
+				}
+				quotas[string(flavor.Name)][clusterQueue.Name] = quota
+			}
+		}
+	}
+
+	var (
+		capacities      []kueuev1alpha1.FlavorCapacity
+		recommendations []kueuev1alpha1.QuotaRecommendation
+		exceeded        []string
+		errs            []error
+		// Recommendations to apply, by managed ClusterQueue and flavor.
+		scale = map[string]map[string]corev1.ResourceList{}
+	)
+	for _, obj := range flavors {
+		item, ok := obj.(*unstructured.Unstructured)
+		if !ok {
+			continue
+		}
+		flavor := &kueuev1beta1.ResourceFlavor{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, flavor); err != nil {
+			return fmt.Errorf("unable to decode ResourceFlavor %s: %w", item.GetName(), err)
+		}
+		flavorNodes := queues.FlavorNodes(nodes, flavor.Spec.NodeLabels)
+		allocatable := queues.TotalAllocatable(flavorNodes)
+
+		nominalQuota := corev1.ResourceList{}
+		for _, quota := range quotas[flavor.Name] {
+			for name, q := range quota {
+				sum := nominalQuota[name]
+				sum.Add(q)
+				nominalQuota[name] = sum
+			}
+		}
+		reported := corev1.ResourceList{}
+		for name, q := range allocatable {
+			if _, covered := nominalQuota[name]; covered || name == corev1.ResourceCPU || name == corev1.ResourceMemory {
+				reported[name] = q
+			}
+		}
+		capacities = append(capacities, kueuev1alpha1.FlavorCapacity{
+			Name:         flavor.Name,
+			Nodes:        int32(len(flavorNodes)),
+			Allocatable:  reported,
+			NominalQuota: nominalQuota,
+		})
+		for _, name := range sortedResourceNames(nominalQuota) {
+			available, ok := allocatable[name]
+			if q := nominalQuota[name]; ok && q.Cmp(available) > 0 {
+				exceeded = append(exceeded, fmt.Sprintf("%s %s: nominal quota %s, allocatable %s", flavor.Name, name, q.String(), available.String()))
+			}
+		}
+
+		// Unmanaged queues are recommended a share of the whole capacity. Their quotas are
+		// fixed though, so managed queues share what they leave.
+		managedQuotas := map[string]corev1.ResourceList{}
+		remaining := allocatable.DeepCopy()
+		for clusterQueue, quota := range quotas[flavor.Name] {
+			if _, ok := managed[clusterQueue]; ok {
+				managedQuotas[clusterQueue] = quota
+				continue
+			}
+			for name, q := range quota {
+				if available, ok := remaining[name]; ok {
+					available.Sub(q)
+					if available.Sign() < 0 {
+						available = resource.MustParse("0")
+					}
+					remaining[name] = available
+				}
+			}
+		}
+		for clusterQueue, recommended := range queues.RecommendQuotas(allocatable, quotas[flavor.Name]) {
+			if _, ok := managedQuotas[clusterQueue]; ok {
+				continue
+			}
+			recommendations = append(recommendations, kueuev1alpha1.QuotaRecommendation{
+				ClusterQueue: clusterQueue,
+				Flavor:       flavor.Name,
+				NominalQuota: recommended,
+			})
+		}
+		for clusterQueue, recommended := range queues.RecommendQuotas(remaining, managedQuotas) {
+			recommendations = append(recommendations, kueuev1alpha1.QuotaRecommendation{
+				ClusterQueue: clusterQueue,
+				Flavor:       flavor.Name,
+				NominalQuota: recommended,
+				Managed:      true,
+			})
+			if kueue.Spec.QuotaManagement.AutoScale && len(recommended) > 0 {
+				if scale[clusterQueue] == nil {
+					scale[clusterQueue] = map[string]corev1.ResourceList{}
+				}
+				scale[clusterQueue][flavor.Name] = recommended
+			}
+		}
+	}
+	for clusterQueue, recommended := range scale {
+		if err := c.scaleClusterQueue(managed[clusterQueue], recommended); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	sort.Slice(capacities, func(i, j int) bool { return capacities[i].Name < capacities[j].Name })
+	sort.Slice(recommendations, func(i, j int) bool {
+		if recommendations[i].ClusterQueue != recommendations[j].ClusterQueue {
+			return recommendations[i].ClusterQueue < recommendations[j].ClusterQueue
+		}
+		return recommendations[i].Flavor < recommendations[j].Flavor
+	})
+
+	exceedsCapacity := operatorv1.OperatorCondition{
+		Type:   quotaExceedsCapacityCondition,
+		Status: operatorv1.ConditionFalse,
+		Reason: "AsExpected",
+	}
+	if len(exceeded) > 0 {
+		exceedsCapacity.Status = operatorv1.ConditionTrue
+		exceedsCapacity.Reason = "NominalQuotaExceedsAllocatable"
+		exceedsCapacity.Message = strings.Join(exceeded, "; ")
+	}
+	degraded := operatorv1.OperatorCondition{
+		Type:   "QuotaControllerDegraded",
+		Status: operatorv1.ConditionFalse,
+		Reason: "AsExpected",
+	}
+	if len(errs) > 0 {
+		degraded.Status = operatorv1.ConditionTrue
+		degraded.Reason = "ScaleFailed"
+		degraded.Message = utilerrors.NewAggregate(errs).Error()
+	}
+	if err := updateKueueStatus(c.ctx, c.operatorClient, c.operatorNamespace, kueue.Name, func(status *kueuev1alpha1.KueueStatus) {
+		status.FlavorCapacities = capacities
+		status.QuotaRecommendations = recommendations
+		v1helpers.SetOperatorCondition(&status.Conditions, exceedsCapacity)
+		v1helpers.SetOperatorCondition(&status.Conditions, degraded)
+	}); err != nil {
+		errs = append(errs, err)
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+// scaleClusterQueue sets the nominal quotas of a managed ClusterQueue, given by flavor.
+// The object is edited as unstructured so that fields unknown to the vendored API survive.
+func (c *QuotaController) scaleClusterQueue(clusterQueue *unstructured.Unstructured, recommended map[string]corev1.ResourceList) error {
+	updated := clusterQueue.DeepCopy()
+	groups, _, err := unstructured.NestedSlice(updated.Object, "spec", "resourceGroups")
+	if err != nil {
+		return err
+	}
+	var changes []string
+	for _, group := range groups {
+		group, ok := group.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		flavors, _, _ := unstructured.NestedSlice(group, "flavors")
+		for _, f := range flavors {
+			f, ok := f.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			flavor, _ := f["name"].(string)
+			quotas, ok := recommended[flavor]
+			if !ok {
+				continue
+			}
+			resources, _, _ := unstructured.NestedSlice(f, "resources")
+			for _, r := range resources {
+				r, ok := r.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				name, _ := r["name"].(string)
+				quota, ok := quotas[corev1.ResourceName(name)]
+				if !ok {
+					continue
+				}
+				current, _ := r["nominalQuota"].(string)
+				if parsed, err := resource.ParseQuantity(current); err == nil && parsed.Cmp(quota) == 0 {
+					continue
+				}
+				r["nominalQuota"] = quota.String()
+				changes = append(changes, fmt.Sprintf("%s/%s=%s", flavor, name, quota.String()))
+			}
+			if err := unstructured.SetNestedSlice(f, resources, "resources"); err != nil {
+				return err
+			}
+		}
+		if err := unstructured.SetNestedSlice(group, flavors, "flavors"); err != nil {
+			return err
+		}
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+	if err := unstructured.SetNestedSlice(updated.Object, groups, "spec", "resourceGroups"); err != nil {
+		return err
+	}
+	if _, err := c.dynamicClient.Resource(queues.ClusterQueuesGVR).Update(c.ctx, updated, metav1.UpdateOptions{FieldManager: fieldManager}); err != nil {
+		return fmt.Errorf("unable to scale ClusterQueue %s: %w", updated.GetName(), err)
+	}
+	sort.Strings(changes)
+	c.eventRecorder.Eventf("ClusterQueueScaled", "Scaled the nominal quotas of ClusterQueue %s to %s", updated.GetName(), strings.Join(changes, ", "))
+	return nil
+}
+
+// quotaInputChanged reports whether the spec or the quota management annotation of a
+// ClusterQueue or ResourceFlavor changed.
+func quotaInputChanged(old, new interface{}) bool {
+	oldObj, err := meta.Accessor(old)
+	if err != nil {
+		return true
+	}
+	newObj, err := meta.Accessor(new)
+	if err != nil {
+		return true
+	}
+	return oldObj.GetGeneration() != newObj.GetGeneration() ||
+		oldObj.GetAnnotations()[quotaManagementAnnotation] != newObj.GetAnnotations()[quotaManagementAnnotation]
+}
+
+func sortedResourceNames(list corev1.ResourceList) []corev1.ResourceName {
+	names := make([]corev1.ResourceName, 0, len(list))
+	for name := range list {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+	return names
+}
@@ -0,0 +1,188 @@
+package operator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	kueuev1alpha1 "github.com/openshift/kueue-operator/pkg/apis/kueueoperator/v1alpha1"
+	"github.com/openshift/kueue-operator/pkg/builders/queues"
+	"github.com/openshift/kueue-operator/pkg/namespace"
+	"github.com/openshift/kueue-operator/pkg/operator/operatorclient"
+)
+
+func newTestQuotaController(t *testing.T, kueue *kueuev1alpha1.Kueue, nodes ...*corev1.Node) *QuotaController {
+	t.Helper()
+	defaults, _ := newTestDefaultsController(t, kueue)
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, node := range nodes {
+		if err := indexer.Add(node); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return &QuotaController{
+		ctx:                defaults.ctx,
+		operatorClient:     defaults.operatorClient,
+		dynamicClient:      defaults.dynamicClient,
+		nodeLister:         corev1listers.NewNodeLister(indexer),
+		clusterQueueLister: dynamicClientLister{client: defaults.dynamicClient.Resource(queues.ClusterQueuesGVR)},
+		flavorLister:       dynamicClientLister{client: defaults.dynamicClient.Resource(queues.ResourceFlavorsGVR)},
+		queuesSynced:       func() bool { return true },
+		eventRecorder:      defaults.eventRecorder,
+		operatorNamespace:  defaults.operatorNamespace,
+	}
+}
+
+// dynamicClientLister reads through to the fake dynamic client, so that a sync sees the
+// objects written by the previous one without waiting for an informer.
+type dynamicClientLister struct {
+	cache.GenericLister
+	client dynamic.NamespaceableResourceInterface
+}
+
+func (l dynamicClientLister) List(selector labels.Selector) ([]runtime.Object, error) {
+	list, err := l.client.List(context.Background(), metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, err
+	}
+	objects := make([]runtime.Object, 0, len(list.Items))
+	for i := range list.Items {
+		objects = append(objects, &list.Items[i])
+	}
+	return objects, nil
+}
+
+func (c *QuotaController) createClusterQueue(t *testing.T, name, cpu string, managed bool) {
+	t.Helper()
+	clusterQueue := queues.BuildClusterQueue(name, "default-flavor", nil, []corev1.ResourceName{corev1.ResourceCPU}, corev1.ResourceList{
+		corev1.ResourceCPU: resource.MustParse(cpu),
+	})
+	if managed {
+		clusterQueue.Annotations = map[string]string{quotaManagementAnnotation: quotaManagementManaged}
+	}
+	if err := createIfMissing(c.ctx, c.dynamicClient, c.eventRecorder, queues.ClusterQueuesGVR, clusterQueue); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func (c *QuotaController) nominalCPU(t *testing.T, name string) string {
+	t.Helper()
+	clusterQueue, err := c.dynamicClient.Resource(queues.ClusterQueuesGVR).Get(c.ctx, name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	groups, _, _ := unstructured.NestedSlice(clusterQueue.Object, "spec", "resourceGroups")
+	flavors, _, _ := unstructured.NestedSlice(groups[0].(map[string]interface{}), "flavors")
+	resources, _, _ := unstructured.NestedSlice(flavors[0].(map[string]interface{}), "resources")
+	return resources[0].(map[string]interface{})["nominalQuota"].(string)
+}
+
+func TestQuotaController(t *testing.T) {
+	kueue := newTestKueue()
+	kueue.Spec.QuotaManagement = &kueuev1alpha1.QuotaManagement{}
+	c := newTestQuotaController(t, kueue,
+		newTestNode("worker-0", "4", "16Gi", false),
+		newTestNode("worker-1", "6", "16Gi", false),
+		newTestNode("cordoned", "8", "16Gi", true),
+	)
+	if err := createIfMissing(c.ctx, c.dynamicClient, c.eventRecorder, queues.ResourceFlavorsGVR, queues.BuildResourceFlavor("default-flavor")); err != nil {
+		t.Fatal(err)
+	}
+	c.createClusterQueue(t, "research", "12", true)
+	c.createClusterQueue(t, "batch", "4", false)
+
+	if err := c.sync(); err != nil {
+		t.Fatalf("Unexpected sync error: %v", err)
+	}
+
+	kueue, err := c.operatorClient.Kueues(namespace.GetNamespace()).Get(c.ctx, operatorclient.OperatorConfigName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]kueuev1alpha1.FlavorCapacity{{
+		Name:         "default-flavor",
+		Nodes:        2,
+		Allocatable:  corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("10"), corev1.ResourceMemory: resource.MustParse("32Gi")},
+		NominalQuota: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("16")},
+	}}, kueue.Status.FlavorCapacities, cmp.Comparer(func(a, b resource.Quantity) bool { return a.Cmp(b) == 0 })); len(diff) != 0 {
+		t.Errorf("Unexpected status.flavorCapacities (-want,+got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]kueuev1alpha1.QuotaRecommendation{
+		{ClusterQueue: "batch", Flavor: "default-flavor", NominalQuota: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2500m")}},
+		{ClusterQueue: "research", Flavor: "default-flavor", NominalQuota: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("6")}, Managed: true},
+	}, kueue.Status.QuotaRecommendations, cmp.Comparer(func(a, b resource.Quantity) bool { return a.Cmp(b) == 0 })); len(diff) != 0 {
+		t.Errorf("Unexpected status.quotaRecommendations (-want,+got):\n%s", diff)
+	}
+	if !v1helpers.IsOperatorConditionTrue(kueue.Status.Conditions, quotaExceedsCapacityCondition) {
+		t.Errorf("Expected %s to be true, got %v", quotaExceedsCapacityCondition, kueue.Status.Conditions)
+	}
+	// Without autoScale, recommendations are only published.
+	if got := c.nominalCPU(t, "research"); got != "12" {
+		t.Errorf("Expected research to keep its quota, got %s", got)
+	}
+
+	kueue.Spec.QuotaManagement.AutoScale = true
+	if _, err := c.operatorClient.Kueues(namespace.GetNamespace()).Update(c.ctx, kueue, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.sync(); err != nil {
+		t.Fatalf("Unexpected sync error: %v", err)
+	}
+	if got := c.nominalCPU(t, "research"); got != "6" {
+		t.Errorf("Expected the managed ClusterQueue to be scaled to 6, got %s", got)
+	}
+	if got := c.nominalCPU(t, "batch"); got != "4" {
+		t.Errorf("Expected the unmanaged ClusterQueue to keep its quota, got %s", got)
+	}
+
+	// The quotas fit the nodes now and scaling is stable.
+	if err := c.sync(); err != nil {
+		t.Fatalf("Unexpected sync error: %v", err)
+	}
+	if got := c.nominalCPU(t, "research"); got != "6" {
+		t.Errorf("Expected the managed ClusterQueue to stay at 6, got %s", got)
+	}
+	kueue, err = c.operatorClient.Kueues(namespace.GetNamespace()).Get(c.ctx, operatorclient.OperatorConfigName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	condition := v1helpers.FindOperatorCondition(kueue.Status.Conditions, quotaExceedsCapacityCondition)
+	if condition == nil || condition.Status != operatorv1.ConditionFalse {
+		t.Errorf("Expected %s to be false, got %v", quotaExceedsCapacityCondition, condition)
+	}
+}
+
+func TestQuotaControllerWaitsForKueue(t *testing.T) {
+	kueue := newTestKueue()
+	kueue.Spec.QuotaManagement = &kueuev1alpha1.QuotaManagement{}
+	c := newTestQuotaController(t, kueue)
+	// The informers never sync while the Kueue CRDs are missing.
+	c.queuesSynced = func() bool { return false }
+
+	if err := c.sync(); err != nil {
+		t.Fatalf("Expected a missing Kueue to not be an error, got %v", err)
+	}
+	kueue, err := c.operatorClient.Kueues(namespace.GetNamespace()).Get(c.ctx, operatorclient.OperatorConfigName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	condition := v1helpers.FindOperatorCondition(kueue.Status.Conditions, quotaExceedsCapacityCondition)
+	if condition == nil || condition.Status != operatorv1.ConditionUnknown || condition.Reason != "KueueNotReady" {
+		t.Errorf("Expected %s to be unknown until Kueue is ready, got %v", quotaExceedsCapacityCondition, condition)
+	}
+	if condition := v1helpers.FindOperatorCondition(kueue.Status.Conditions, "QuotaControllerDegraded"); condition != nil && condition.Status == operatorv1.ConditionTrue {
+		t.Errorf("Expected QuotaControllerDegraded to not be true, got %v", condition)
+	}
+}
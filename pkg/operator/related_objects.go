@@ -0,0 +1,92 @@
+package operator
+
+import (
+	"strings"
+
+	configv1 "github.com/openshift/api/config/v1"
+	kueuev1alpha1 "github.com/openshift/kueue-operator/pkg/apis/kueueoperator/v1alpha1"
+	"github.com/openshift/kueue-operator/pkg/operator/operatorclient"
+	"github.com/openshift/kueue-operator/pkg/version"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// pipelineResources maps the resources of resourceName to their API group, and tells
+// whether they are namespaced.
+var pipelineResources = map[string]struct {
+	group      string
+	namespaced bool
+}{
+	"configmaps":                      {group: "", namespaced: true},
+	"serviceaccounts":                 {group: "", namespaced: true},
+	"secrets":                         {group: "", namespaced: true},
+	"services":                        {group: "", namespaced: true},
+	"roles":                           {group: "rbac.authorization.k8s.io", namespaced: true},
+	"rolebindings":                    {group: "rbac.authorization.k8s.io", namespaced: true},
+	"clusterroles":                    {group: "rbac.authorization.k8s.io"},
+	"clusterrolebindings":             {group: "rbac.authorization.k8s.io"},
+	"customresourcedefinitions":       {group: "apiextensions.k8s.io"},
+	"deployments":                     {group: "apps", namespaced: true},
+	"mutatingwebhookconfigurations":   {group: "admissionregistration.k8s.io"},
+	"validatingwebhookconfigurations": {group: "admissionregistration.k8s.io"},
+}
+
+// relatedObjects returns references to every object the operator manages for the given Kueue.
+// The list is published in status.relatedObjects so that must-gather and `oc adm inspect`
+// can collect everything the operator owns. It holds the objects of the pipeline rendered
+// for the Kueue. A step that fails to render, such as one with an invalid configuration,
+// keeps the objects it applied before, which are named like those of the routing Kueue.
+func relatedObjects(kueue *kueuev1alpha1.Kueue) []configv1.ObjectReference {
+	objects := []configv1.ObjectReference{
+		{Group: "operator.openshift.io", Resource: "kueues", Namespace: kueue.Namespace, Name: kueue.Name},
+		{Group: "", Resource: "namespaces", Name: kueue.Namespace},
+	}
+	routed := pipelineRoutedObjects()
+	for _, step := range resourcePipeline() {
+		required, err := step.required(kueue, map[string]string{})
+		if err != nil {
+			required = routed[step.name]
+		}
+		// Apply order within a step, as in applyStep.
+		for _, key := range sets.List(sets.KeySet(required)) {
+			obj := required[key]
+			accessor, err := meta.Accessor(obj)
+			if err != nil {
+				continue
+			}
+			resource := resourceName(obj)
+			reference := configv1.ObjectReference{Group: pipelineResources[resource].group, Resource: resource, Name: accessor.GetName()}
+			if pipelineResources[resource].namespaced {
+				reference.Namespace = kueue.Namespace
+			}
+			objects = append(objects, reference)
+		}
+	}
+
+	// The service-ca operator writes the serving certificate of the canary.
+	if activeCanary(kueue) != nil {
+		objects = append(objects, configv1.ObjectReference{Group: "", Resource: "secrets", Namespace: kueue.Namespace, Name: canaryWebhookCertName})
+	}
+	return objects
+}
+
+// operandVersions returns the versions of the operator and of the Kueue operand,
+// in the same shape as ClusterOperator status.versions.
+func operandVersions(kueue *kueuev1alpha1.Kueue) []configv1.OperandVersion {
+	return []configv1.OperandVersion{
+		{Name: "operator", Version: version.Get().GitVersion},
+		{Name: operatorclient.OperandName, Version: imageVersion(kueue.Spec.Image)},
+	}
+}
+
+// imageVersion returns the tag or digest of an image pull spec, or the pull spec itself
+// when it carries neither.
+func imageVersion(image string) string {
+	if i := strings.LastIndex(image, "@"); i >= 0 {
+		return image[i+1:]
+	}
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		return image[i+1:]
+	}
+	return image
+}
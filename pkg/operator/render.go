@@ -0,0 +1,447 @@
+package operator
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/kueue-operator/bindata"
+	kueuev1alpha1 "github.com/openshift/kueue-operator/pkg/apis/kueueoperator/v1alpha1"
+	"github.com/openshift/kueue-operator/pkg/operator/operatorclient"
+	"github.com/openshift/library-go/pkg/controller"
+	"github.com/openshift/library-go/pkg/operator/resource/resourcemerge"
+	"github.com/openshift/library-go/pkg/operator/resource/resourceread"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/utils/ptr"
+)
+
+// kueueGenerationAnnotation records on the kueue Deployment the generation of the Kueue
+// it was applied for. It is not set on the pod template, so that changes of the Kueue
+// that do not change the pods do not restart them.
+const kueueGenerationAnnotation = "kueueoperator.operator.openshift.io/cluster"
+
+// The functions in this file build the objects the TargetConfigReconciler applies.
+// They only read bindata and the Kueue CR so that the same rendering can run offline.
+
+// RenderManifests returns every object the operator would apply for the given Kueue, in apply order.
+// Pod template annotations that depend on the applied objects are omitted.
+// spec.config.rollbackTo is ignored, the recorded revisions only exist in the cluster.
+func RenderManifests(kueue *kueuev1alpha1.Kueue) ([]runtime.Object, error) {
+	specAnnotations := map[string]string{}
+
+	var objects []runtime.Object
+	for _, step := range resourcePipeline() {
+		required, err := step.requiredObjects(kueue, specAnnotations)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", step.name, err)
+		}
+		for _, key := range sets.List(sets.KeySet(required)) {
+			objects = append(objects, required[key])
+		}
+	}
+	return objects, nil
+}
+
+// setOwnerReference makes the Kueue CR the only owner of required.
+func setOwnerReference(required metav1.Object, kueue *kueuev1alpha1.Kueue) {
+	ownerReference := metav1.OwnerReference{
+		APIVersion: "operator.openshift.io/v1alpha1",
+		Kind:       "Kueue",
+		Name:       kueue.Name,
+		UID:        kueue.UID,
+	}
+	required.SetOwnerReferences([]metav1.OwnerReference{
+		ownerReference,
+	})
+	controller.EnsureOwnerRef(required, ownerReference)
+}
+
+// kueueAsset reads a bindata asset for the given Kueue. The namespace placeholder of the
+// asset is replaced with the namespace of the Kueue, the namespace the operator runs in.
+func kueueAsset(kueue *kueuev1alpha1.Kueue, assetPath string) []byte {
+	return bindata.MustNamespacedAsset(assetPath, kueue.Namespace)
+}
+
+func requiredServiceAccount(kueue *kueuev1alpha1.Kueue) *v1.ServiceAccount {
+	required := resourceread.ReadServiceAccountV1OrDie(kueueAsset(kueue, "assets/kueue-operator/serviceaccount.yaml"))
+	setOwnerReference(required, kueue)
+	return required
+}
+
+func requiredSecret(kueue *kueuev1alpha1.Kueue) *v1.Secret {
+	required := resourceread.ReadSecretV1OrDie(kueueAsset(kueue, "assets/kueue-operator/secret.yaml"))
+	setOwnerReference(required, kueue)
+	return required
+}
+
+// requiredMutatingWebhook returns the webhooks of the stable Kueue manager. The namespaces
+// of spec.canary are excluded from the webhooks of namespaced objects.
+func requiredMutatingWebhook(kueue *kueuev1alpha1.Kueue) *admissionregistrationv1.MutatingWebhookConfiguration {
+	required := mutatingWebhookAsset(kueue)
+	_, stable, _ := canaryNamespaceRequirements(kueue)
+	for i := range required.Webhooks {
+		if stable != nil && namespacedWebhook(required.Webhooks[i].Rules) {
+			addNamespaceRequirement(&required.Webhooks[i].NamespaceSelector, *stable)
+		}
+	}
+	return required
+}
+
+// requiredValidatingWebhook is requiredMutatingWebhook for the validating webhooks.
+func requiredValidatingWebhook(kueue *kueuev1alpha1.Kueue) *admissionregistrationv1.ValidatingWebhookConfiguration {
+	required := validatingWebhookAsset(kueue)
+	_, stable, _ := canaryNamespaceRequirements(kueue)
+	for i := range required.Webhooks {
+		if stable != nil && namespacedWebhook(required.Webhooks[i].Rules) {
+			addNamespaceRequirement(&required.Webhooks[i].NamespaceSelector, *stable)
+		}
+	}
+	return required
+}
+
+func mutatingWebhookAsset(kueue *kueuev1alpha1.Kueue) *admissionregistrationv1.MutatingWebhookConfiguration {
+	required := resourceread.ReadMutatingWebhookConfigurationV1OrDie(kueueAsset(kueue, "assets/kueue-operator/mutatingwebhook.yaml"))
+	setOwnerReference(required, kueue)
+	return required
+}
+
+func validatingWebhookAsset(kueue *kueuev1alpha1.Kueue) *admissionregistrationv1.ValidatingWebhookConfiguration {
+	required := resourceread.ReadValidatingWebhookConfigurationV1OrDie(kueueAsset(kueue, "assets/kueue-operator/validatingwebhook.yaml"))
+	setOwnerReference(required, kueue)
+	return required
+}
+
+func requiredRoleBinding(kueue *kueuev1alpha1.Kueue, assetPath string) *rbacv1.RoleBinding {
+	required := resourceread.ReadRoleBindingV1OrDie(kueueAsset(kueue, assetPath))
+	setOwnerReference(required, kueue)
+	return required
+}
+
+func requiredClusterRoleBinding(kueue *kueuev1alpha1.Kueue, assetPath string) *rbacv1.ClusterRoleBinding {
+	required := resourceread.ReadClusterRoleBindingV1OrDie(kueueAsset(kueue, assetPath))
+	setOwnerReference(required, kueue)
+	return required
+}
+
+func requiredRole(kueue *kueuev1alpha1.Kueue, assetPath string) *rbacv1.Role {
+	required := resourceread.ReadRoleV1OrDie(kueueAsset(kueue, assetPath))
+	setOwnerReference(required, kueue)
+	return required
+}
+
+func requiredService(kueue *kueuev1alpha1.Kueue, assetPath string) *v1.Service {
+	required := resourceread.ReadServiceV1OrDie(kueueAsset(kueue, assetPath))
+	setOwnerReference(required, kueue)
+	return required
+}
+
+// requiredClusterRoles returns the Kueue cluster roles keyed by their bindata file name.
+// Aggregated cluster roles are skipped, their rules are filled in by the API server.
+func requiredClusterRoles(kueue *kueuev1alpha1.Kueue) map[string]*rbacv1.ClusterRole {
+	clusterRoles := make(map[string]*rbacv1.ClusterRole, 35)
+	// This is hardcoded due to the amount of clusterroles that kueue has.
+	for i := 0; i < 35; i++ {
+		fileName := fmt.Sprintf("clusterrole_%d.yml", i)
+		required := resourceread.ReadClusterRoleV1OrDie(kueueAsset(kueue, "assets/kueue-operator/"+fileName))
+		if required.AggregationRule != nil {
+			continue
+		}
+		if kueue.Spec.ProvisioningRequest != nil && !kueue.Spec.ProvisioningRequest.Enabled {
+			required.Rules = slices.DeleteFunc(required.Rules, func(rule rbacv1.PolicyRule) bool {
+				return slices.Contains(rule.APIGroups, provisioningRequestsGVR.Group)
+			})
+		}
+		setOwnerReference(required, kueue)
+		clusterRoles[fileName] = required
+	}
+	return clusterRoles
+}
+
+func requiredOpenshiftClusterRoleBindingForKueue(kueue *kueuev1alpha1.Kueue) *rbacv1.ClusterRoleBinding {
+	clusterRoleBinding := &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "kueue-openshift-cluster-role-binding",
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				Kind:      "ServiceAccount",
+				Name:      "kueue-controller-manager",
+				Namespace: kueue.Namespace,
+			},
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: "rbac.authorization.k8s.io",
+			Kind:     "ClusterRole",
+			Name:     "kueue-openshift-roles",
+		},
+	}
+	setOwnerReference(clusterRoleBinding, kueue)
+	return clusterRoleBinding
+}
+
+func requiredOpenshiftClusterRoleForKueue(kueue *kueuev1alpha1.Kueue) *rbacv1.ClusterRole {
+	clusterRole := &rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{
+				"app.kubernetes.io/component": "controller",
+				"app.kubernetes.io/name":      "kueue",
+				"control-plane":               "controller-manager",
+			},
+			Name: "kueue-openshift-roles",
+		},
+		Rules: []rbacv1.PolicyRule{
+			{
+				APIGroups: []string{"config.openshift.io"},
+				Resources: []string{"infrastructures", "apiservers"},
+				Verbs:     []string{"get", "watch", "list"},
+			},
+		},
+	}
+	setOwnerReference(clusterRole, kueue)
+	return clusterRole
+}
+
+// requiredCustomResourceDefinitions returns the Kueue CRDs keyed by their bindata file name.
+func requiredCustomResourceDefinitions(kueue *kueuev1alpha1.Kueue) map[string]*apiextensionsv1.CustomResourceDefinition {
+	crds := make(map[string]*apiextensionsv1.CustomResourceDefinition, 11)
+	// This is hardcoded due to the amount of custom resources that kueue has.
+	for i := 0; i < 11; i++ {
+		fileName := fmt.Sprintf("crd_%d.yml", i)
+		required := resourceread.ReadCustomResourceDefinitionV1OrDie(kueueAsset(kueue, "assets/kueue-operator/"+fileName))
+		setOwnerReference(required, kueue)
+		crds[fileName] = required
+	}
+	return crds
+}
+
+func requiredDeployment(kueueoperator *kueuev1alpha1.Kueue, specAnnotations map[string]string) *appsv1.Deployment {
+	required := resourceread.ReadDeploymentV1OrDie(kueueAsset(kueueoperator, "assets/kueue-operator/deployment.yaml"))
+	required.Name = operatorclient.OperandName
+	setOwnerReference(required, kueueoperator)
+
+	required.Spec.Template.Spec.Containers[0].Image = kueueoperator.Spec.Image
+	switch kueueoperator.Spec.LogLevel {
+	case operatorv1.Normal:
+		required.Spec.Template.Spec.Containers[0].Args = append(required.Spec.Template.Spec.Containers[0].Args, fmt.Sprintf("--zap-log-level=%d", 2))
+	case operatorv1.Debug:
+		required.Spec.Template.Spec.Containers[0].Args = append(required.Spec.Template.Spec.Containers[0].Args, fmt.Sprintf("--zap-log-level=%d", 4))
+	case operatorv1.Trace:
+		required.Spec.Template.Spec.Containers[0].Args = append(required.Spec.Template.Spec.Containers[0].Args, fmt.Sprintf("--zap-log-level=%d", 6))
+	case operatorv1.TraceAll:
+		required.Spec.Template.Spec.Containers[0].Args = append(required.Spec.Template.Spec.Containers[0].Args, fmt.Sprintf("--zap-log-level=%d", 8))
+	default:
+		required.Spec.Template.Spec.Containers[0].Args = append(required.Spec.Template.Spec.Containers[0].Args, fmt.Sprintf("--zap-log-level=%d", 2))
+	}
+
+	if featureGates := kueueFeatureGates(kueueoperator); len(featureGates) > 0 {
+		required.Spec.Template.Spec.Containers[0].Args = append(required.Spec.Template.Spec.Containers[0].Args, "--feature-gates="+featureGates)
+	}
+
+	resourcemerge.MergeMap(ptr.To(false), &required.Annotations, map[string]string{
+		kueueGenerationAnnotation: strconv.FormatInt(kueueoperator.Generation, 10),
+	})
+	resourcemerge.MergeMap(ptr.To(false), &required.Spec.Template.Annotations, specAnnotations)
+	return required
+}
+
+// kueueFeatureGates returns the --feature-gates value of the Kueue manager, sorted by gate.
+// spec.provisioningRequest and spec.topologyAwareScheduling take precedence over
+// spec.config.featureGates.
+func kueueFeatureGates(kueue *kueuev1alpha1.Kueue) string {
+	gates := kueueFeatureGateMap(kueue)
+	values := make([]string, 0, len(gates))
+	for _, gate := range sets.List(sets.KeySet(gates)) {
+		values = append(values, fmt.Sprintf("%s=%t", gate, gates[gate]))
+	}
+	return strings.Join(values, ",")
+}
+
+// kueueFeatureGateMap returns the feature gates of the Kueue manager.
+func kueueFeatureGateMap(kueue *kueuev1alpha1.Kueue) map[string]bool {
+	gates := make(map[string]bool, len(kueue.Spec.Config.FeatureGates)+1)
+	for gate, enabled := range kueue.Spec.Config.FeatureGates {
+		gates[gate] = enabled
+	}
+	if kueue.Spec.ProvisioningRequest != nil {
+		gates[provisioningACCFeatureGate] = kueue.Spec.ProvisioningRequest.Enabled
+	}
+	if kueue.Spec.TopologyAwareScheduling != nil {
+		gates[topologyAwareSchedulingFeatureGate] = true
+	}
+	return gates
+}
+
+const (
+	canaryDeploymentName        = operatorclient.OperandName + "-canary"
+	canaryConfigMapName         = KueueConfigMap + "-canary"
+	canaryWebhookServiceName    = "kueue-canary-webhook-service"
+	canaryWebhookCertName       = "kueue-canary-webhook-server-cert"
+	canaryMutatingWebhookName   = "kueue-canary-mutating-webhook-configuration"
+	canaryValidatingWebhookName = "kueue-canary-validating-webhook-configuration"
+
+	// controlPlaneLabel selects the pods of a Kueue manager in its Services.
+	controlPlaneLabel  = "control-plane"
+	stableControlPlane = "controller-manager"
+	canaryControlPlane = "controller-manager-canary"
+
+	servingCertSecretAnnotation = "service.beta.openshift.io/serving-cert-secret-name"
+	injectCABundleAnnotation    = "service.beta.openshift.io/inject-cabundle"
+)
+
+// clusterScopedWebhookResources are the resources of the Kueue webhooks that are not
+// namespaced. A namespace selector does not apply to them.
+var clusterScopedWebhookResources = sets.New("clusterqueues", "cohorts", "resourceflavors")
+
+// namespacedWebhook returns whether a webhook only intercepts namespaced objects.
+func namespacedWebhook(rules []admissionregistrationv1.RuleWithOperations) bool {
+	for _, rule := range rules {
+		if clusterScopedWebhookResources.HasAny(rule.Resources...) {
+			return false
+		}
+	}
+	return true
+}
+
+// addNamespaceRequirement adds requirement to the namespace selector of a webhook.
+func addNamespaceRequirement(selector **metav1.LabelSelector, requirement metav1.LabelSelectorRequirement) {
+	if *selector == nil {
+		*selector = &metav1.LabelSelector{}
+	}
+	(*selector).MatchExpressions = append((*selector).MatchExpressions, requirement)
+}
+
+// activeCanary returns spec.canary while the operator runs it, or nil. An aborted canary
+// is removed right away. A promoted one keeps serving its namespaces until spec.canary is
+// removed, so that they are not switched back to the previous image in between.
+func activeCanary(kueue *kueuev1alpha1.Kueue) *kueuev1alpha1.Canary {
+	if kueue.Spec.Canary == nil || kueue.Spec.Canary.Action == kueuev1alpha1.CanaryActionAbort {
+		return nil
+	}
+	return kueue.Spec.Canary
+}
+
+// canaryNamespaceRequirements returns the requirement that selects the namespaces of
+// spec.canary and its negation, which selects every other namespace. Both are nil when
+// there is no canary. A selector with more than one requirement has no negation that a
+// label selector can express, so it is rejected.
+func canaryNamespaceRequirements(kueue *kueuev1alpha1.Kueue) (canary, stable *metav1.LabelSelectorRequirement, err error) {
+	if activeCanary(kueue) == nil {
+		return nil, nil, nil
+	}
+	selector := kueue.Spec.Canary.NamespaceSelector
+	if len(selector.MatchLabels)+len(selector.MatchExpressions) != 1 {
+		return nil, nil, fmt.Errorf("spec.canary.namespaceSelector must have a single label or expression, it has %d", len(selector.MatchLabels)+len(selector.MatchExpressions))
+	}
+	var requirement metav1.LabelSelectorRequirement
+	for key, value := range selector.MatchLabels {
+		requirement = metav1.LabelSelectorRequirement{Key: key, Operator: metav1.LabelSelectorOpIn, Values: []string{value}}
+	}
+	if len(selector.MatchExpressions) == 1 {
+		requirement = *selector.MatchExpressions[0].DeepCopy()
+	}
+	negation := metav1.LabelSelectorRequirement{Key: requirement.Key, Values: requirement.Values}
+	switch requirement.Operator {
+	case metav1.LabelSelectorOpIn:
+		negation.Operator = metav1.LabelSelectorOpNotIn
+	case metav1.LabelSelectorOpNotIn:
+		negation.Operator = metav1.LabelSelectorOpIn
+	case metav1.LabelSelectorOpExists:
+		negation.Operator = metav1.LabelSelectorOpDoesNotExist
+	case metav1.LabelSelectorOpDoesNotExist:
+		negation.Operator = metav1.LabelSelectorOpExists
+	default:
+		return nil, nil, fmt.Errorf("spec.canary.namespaceSelector has an unknown operator %q", requirement.Operator)
+	}
+	if _, err := metav1.LabelSelectorAsSelector(&metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{requirement}}); err != nil {
+		return nil, nil, fmt.Errorf("spec.canary.namespaceSelector: %w", err)
+	}
+	return &requirement, &negation, nil
+}
+
+// requiredCanaryService returns the Service of the canary webhooks, or nil when there is
+// no canary. The serving certificate of the canary is issued for this Service.
+func requiredCanaryService(kueue *kueuev1alpha1.Kueue) *v1.Service {
+	if activeCanary(kueue) == nil {
+		return nil
+	}
+	required := requiredService(kueue, "assets/kueue-operator/webhook-service.yaml")
+	required.Name = canaryWebhookServiceName
+	required.Labels[controlPlaneLabel] = canaryControlPlane
+	resourcemerge.MergeMap(ptr.To(false), &required.Annotations, map[string]string{servingCertSecretAnnotation: canaryWebhookCertName})
+	required.Spec.Selector = map[string]string{controlPlaneLabel: canaryControlPlane}
+	return required
+}
+
+// requiredCanaryDeployment returns the canary Kueue manager, or nil when there is no
+// canary. It runs the stable Deployment with the canary image, the canary configuration
+// and a single replica. Its pods are not selected by the Services of the stable manager.
+func requiredCanaryDeployment(kueue *kueuev1alpha1.Kueue, specAnnotations map[string]string) *appsv1.Deployment {
+	if activeCanary(kueue) == nil {
+		return nil
+	}
+	required := requiredDeployment(kueue, specAnnotations)
+	required.Name = canaryDeploymentName
+	required.Labels[controlPlaneLabel] = canaryControlPlane
+	required.Spec.Replicas = ptr.To[int32](1)
+	required.Spec.Selector.MatchLabels[controlPlaneLabel] = canaryControlPlane
+	required.Spec.Template.Labels[controlPlaneLabel] = canaryControlPlane
+	required.Spec.Template.Spec.Containers[0].Image = kueue.Spec.Canary.Image
+	for i := range required.Spec.Template.Spec.Volumes {
+		if secret := required.Spec.Template.Spec.Volumes[i].Secret; secret != nil && secret.SecretName == "kueue-webhook-server-cert" {
+			secret.SecretName = canaryWebhookCertName
+		}
+		if configMap := required.Spec.Template.Spec.Volumes[i].ConfigMap; configMap != nil && configMap.Name == KueueConfigMap {
+			configMap.Name = canaryConfigMapName
+		}
+	}
+	return required
+}
+
+// requiredCanaryMutatingWebhook returns the webhooks of namespaced objects served by the
+// canary for the namespaces of spec.canary, or nil when there is no canary.
+func requiredCanaryMutatingWebhook(kueue *kueuev1alpha1.Kueue) (*admissionregistrationv1.MutatingWebhookConfiguration, error) {
+	canary, _, err := canaryNamespaceRequirements(kueue)
+	if canary == nil {
+		return nil, err
+	}
+	required := mutatingWebhookAsset(kueue)
+	required.Name = canaryMutatingWebhookName
+	resourcemerge.MergeMap(ptr.To(false), &required.Annotations, map[string]string{injectCABundleAnnotation: "true"})
+	required.Webhooks = slices.DeleteFunc(required.Webhooks, func(webhook admissionregistrationv1.MutatingWebhook) bool {
+		return !namespacedWebhook(webhook.Rules)
+	})
+	for i := range required.Webhooks {
+		required.Webhooks[i].ClientConfig.Service.Name = canaryWebhookServiceName
+		addNamespaceRequirement(&required.Webhooks[i].NamespaceSelector, *canary)
+	}
+	return required, nil
+}
+
+// requiredCanaryValidatingWebhook is requiredCanaryMutatingWebhook for the validating
+// webhooks.
+func requiredCanaryValidatingWebhook(kueue *kueuev1alpha1.Kueue) (*admissionregistrationv1.ValidatingWebhookConfiguration, error) {
+	canary, _, err := canaryNamespaceRequirements(kueue)
+	if canary == nil {
+		return nil, err
+	}
+	required := validatingWebhookAsset(kueue)
+	required.Name = canaryValidatingWebhookName
+	resourcemerge.MergeMap(ptr.To(false), &required.Annotations, map[string]string{injectCABundleAnnotation: "true"})
+	required.Webhooks = slices.DeleteFunc(required.Webhooks, func(webhook admissionregistrationv1.ValidatingWebhook) bool {
+		return !namespacedWebhook(webhook.Rules)
+	})
+	for i := range required.Webhooks {
+		required.Webhooks[i].ClientConfig.Service.Name = canaryWebhookServiceName
+		addNamespaceRequirement(&required.Webhooks[i].NamespaceSelector, *canary)
+	}
+	return required, nil
+}
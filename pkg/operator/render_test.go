@@ -0,0 +1,52 @@
+package operator
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRenderManifestsInNamespace(t *testing.T) {
+	kueue := newTestKueue()
+	kueue.Namespace = "team-kueue"
+	objects, err := RenderManifests(kueue)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, obj := range objects {
+		data, err := json.Marshal(obj)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, leaked := range []string{"kueue-system", "openshift-kueue-operator", "${NAMESPACE}"} {
+			if strings.Contains(string(data), leaked) {
+				t.Errorf("%T %s refers to %s", obj, obj.(metav1.Object).GetName(), leaked)
+			}
+		}
+		if namespaced := obj.(metav1.Object).GetNamespace(); namespaced != "" && namespaced != kueue.Namespace {
+			t.Errorf("%T %s is in namespace %s", obj, obj.(metav1.Object).GetName(), namespaced)
+		}
+	}
+}
+
+func TestRequiredBindingsKeepOtherSubjects(t *testing.T) {
+	kueue := newTestKueue()
+	kueue.Namespace = "team-kueue"
+
+	// The auth reader binding lives in kube-system, only its subject is in the Kueue namespace.
+	roleBinding := requiredRoleBinding(kueue, "assets/kueue-operator/rolebinding-auth-reader.yaml")
+	if roleBinding.Namespace != metav1.NamespaceSystem {
+		t.Errorf("Unexpected namespace of %s: %s", roleBinding.Name, roleBinding.Namespace)
+	}
+	if got := roleBinding.Subjects[0].Namespace; got != kueue.Namespace {
+		t.Errorf("Unexpected namespace of the %s subject: %s", roleBinding.Name, got)
+	}
+
+	clusterRoleBinding := requiredClusterRoleBinding(kueue, "assets/kueue-operator/clusterrolebinding-kueue-manager-role.yaml")
+	if got := clusterRoleBinding.Subjects[0].Namespace; got != kueue.Namespace {
+		t.Errorf("Unexpected namespace of the %s subject: %s", clusterRoleBinding.Name, got)
+	}
+}
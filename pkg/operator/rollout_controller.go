@@ -0,0 +1,309 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	kueuev1alpha1 "github.com/openshift/kueue-operator/pkg/apis/kueueoperator/v1alpha1"
+	"github.com/openshift/kueue-operator/pkg/builders/configmap"
+	kueueconfigclient "github.com/openshift/kueue-operator/pkg/generated/clientset/versioned/typed/kueueoperator/v1alpha1"
+	operatorclientinformers "github.com/openshift/kueue-operator/pkg/generated/informers/externalversions/kueueoperator/v1alpha1"
+	"github.com/openshift/kueue-operator/pkg/namespace"
+	"github.com/openshift/kueue-operator/pkg/operator/operatorclient"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	appsv1listers "k8s.io/client-go/listers/apps/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
+	"k8s.io/utils/ptr"
+)
+
+const defaultRollbackWindow = 5 * time.Minute
+
+// RolloutController implements spec.automaticRollback. It watches every change of
+// spec.image or of the configuration revision in use, and when the kueue Deployment does
+// not become available within the window, it rolls spec.image and
+// spec.config.rollbackTo back to the last revision that was available. It unsets the
+// spec.config.rollbackTo it set once spec.config changes again. The rollouts are
+// reported in status.rollout.
+type RolloutController struct {
+	ctx               context.Context
+	operatorClient    kueueconfigclient.KueueV1alpha1Interface
+	deploymentLister  appsv1listers.DeploymentLister
+	eventRecorder     events.Recorder
+	queue             workqueue.RateLimitingInterface
+	operatorNamespace string
+	clock             clock.PassiveClock
+}
+
+func NewRolloutController(
+	ctx context.Context,
+	operatorConfigClient kueueconfigclient.KueueV1alpha1Interface,
+	operatorClientInformer operatorclientinformers.KueueInformer,
+	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
+	eventRecorder events.Recorder,
+) (factory.Controller, error) {
+	deploymentInformer := kubeInformersForNamespaces.InformersFor(namespace.GetNamespace()).Apps().V1().Deployments()
+	syncCtx := factory.NewSyncContext("RolloutController", eventRecorder)
+	c := &RolloutController{
+		ctx:               ctx,
+		operatorClient:    operatorConfigClient,
+		deploymentLister:  deploymentInformer.Lister(),
+		eventRecorder:     eventRecorder.WithComponentSuffix("rollout-controller"),
+		queue:             syncCtx.Queue(),
+		operatorNamespace: namespace.GetNamespace(),
+		clock:             clock.RealClock{},
+	}
+
+	// The revision in use is recorded in status.configRevisions by the TargetConfigReconciler.
+	if _, err := operatorClientInformer.Informer().AddEventHandler(queueHandler(syncCtx, func(old, new interface{}) bool {
+		oldKueue, ok := old.(*kueuev1alpha1.Kueue)
+		if !ok {
+			return true
+		}
+		newKueue, ok := new.(*kueuev1alpha1.Kueue)
+		if !ok {
+			return true
+		}
+		return kueueSpecChanged(old, new) || !equality.Semantic.DeepEqual(oldKueue.Status.ConfigRevisions, newKueue.Status.ConfigRevisions)
+	})); err != nil {
+		return nil, err
+	}
+
+	return factory.New().
+		WithSyncContext(syncCtx).
+		WithFilteredEventsInformers(func(obj interface{}) bool {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			deployment, ok := obj.(*appsv1.Deployment)
+			return ok && deployment.Name == operatorclient.OperandName
+		}, deploymentInformer.Informer()).
+		WithBareInformers(operatorClientInformer.Informer()).
+		WithSync(func(ctx context.Context, _ factory.SyncContext) error { return c.sync() }).
+		ToController("RolloutController", c.eventRecorder), nil
+}
+
+func (c *RolloutController) sync() error {
+	kueue, err := c.operatorClient.Kueues(c.operatorNamespace).Get(c.ctx, operatorclient.OperatorConfigName, metav1.GetOptions{})
+	if err != nil {
+		klog.ErrorS(err, "unable to get operator configuration", "namespace", c.operatorNamespace, "kueue", operatorclient.OperatorConfigName)
+		return err
+	}
+
+	degraded := operatorv1.OperatorCondition{
+		Type:   "RolloutControllerDegraded",
+		Status: operatorv1.ConditionFalse,
+		Reason: "AsExpected",
+	}
+	if kueue.Spec.AutomaticRollback == nil {
+		return updateKueueStatus(c.ctx, c.operatorClient, c.operatorNamespace, kueue.Name, func(status *kueuev1alpha1.KueueStatus) {
+			status.Rollout = nil
+			v1helpers.SetOperatorCondition(&status.Conditions, degraded)
+		})
+	}
+
+	current, ok := currentRolloutRevision(kueue)
+	if !ok {
+		// The configuration has not been recorded by the TargetConfigReconciler yet.
+		return nil
+	}
+	now := metav1.NewTime(c.clock.Now())
+	rollout := &kueuev1alpha1.RolloutStatus{Current: current, State: kueuev1alpha1.RolloutStateProgressing, StartedAt: now}
+	if kueue.Status.Rollout != nil {
+		rollout = kueue.Status.Rollout.DeepCopy()
+	}
+	if rollout.PinnedConfig != nil {
+		unpinned, err := c.unpinConfig(kueue, *rollout.PinnedConfig)
+		if err != nil {
+			return err
+		}
+		if unpinned {
+			rollout.PinnedConfig = nil
+		}
+	}
+	if rollout.Current != current {
+		// A new change, the last one is only known to be good if it became available.
+		if rollout.State == kueuev1alpha1.RolloutStateHealthy {
+			rollout.LastKnownGood = ptr.To(rollout.Current)
+		}
+		if rollout.RolledBackFrom != nil && (rollout.LastKnownGood == nil || current != *rollout.LastKnownGood) {
+			rollout.RolledBackFrom = nil
+		}
+		rollout.Current = current
+		rollout.State = kueuev1alpha1.RolloutStateProgressing
+		rollout.StartedAt = now
+		rollout.Message = ""
+	}
+
+	var rollback *kueuev1alpha1.RolloutRevision
+	if rollout.State == kueuev1alpha1.RolloutStateProgressing {
+		window := kueue.Spec.AutomaticRollback.Window.Duration
+		if window <= 0 {
+			window = defaultRollbackWindow
+		}
+		available, message, err := c.deploymentAvailable(kueue)
+		if err != nil {
+			return err
+		}
+		switch deadline := rollout.StartedAt.Add(window); {
+		case available:
+			rollout.State = kueuev1alpha1.RolloutStateHealthy
+			rollout.LastKnownGood = ptr.To(current)
+			rollout.Message = ""
+		case now.Time.Before(deadline):
+			rollout.Message = message
+			c.queue.AddAfter(factory.DefaultQueueKey, deadline.Sub(now.Time))
+		case rollout.LastKnownGood == nil || *rollout.LastKnownGood == current:
+			rollout.State = kueuev1alpha1.RolloutStateFailed
+			rollout.Message = fmt.Sprintf("%s after %s, there is no earlier revision to roll back to", message, window)
+		default:
+			rollout.State = kueuev1alpha1.RolloutStateRolledBack
+			rollout.RolledBackFrom = ptr.To(current)
+			rollout.Message = fmt.Sprintf("%s after %s", message, window)
+			rollback = rollout.LastKnownGood
+		}
+	}
+
+	if rollback != nil {
+		// The configuration is only pinned when it changed, so that rolling back an
+		// image keeps following spec.config.
+		var pinned *kueuev1alpha1.PinnedConfig
+		if rollback.ConfigRevision != current.ConfigRevision {
+			hash, err := renderedConfigHash(kueue)
+			if err != nil {
+				return err
+			}
+			pinned = &kueuev1alpha1.PinnedConfig{Revision: rollback.ConfigRevision, RolledBackHash: hash}
+		}
+		if err := c.rollBack(rollback.Image, pinned); err != nil {
+			return err
+		}
+		if pinned != nil {
+			rollout.PinnedConfig = pinned
+		}
+		c.eventRecorder.Warningf("AutomaticRollback", "Rolled back image %s and config revision %d to image %s and config revision %d: %s",
+			current.Image, current.ConfigRevision, rollback.Image, rollback.ConfigRevision, rollout.Message)
+	}
+
+	switch {
+	case rollout.RolledBackFrom != nil:
+		degraded.Status = operatorv1.ConditionTrue
+		degraded.Reason = "RolledBack"
+		degraded.Message = fmt.Sprintf("Image %s and config revision %d were rolled back: %s", rollout.RolledBackFrom.Image, rollout.RolledBackFrom.ConfigRevision, rollout.Message)
+	case rollout.State == kueuev1alpha1.RolloutStateFailed:
+		degraded.Status = operatorv1.ConditionTrue
+		degraded.Reason = "RolloutFailed"
+		degraded.Message = rollout.Message
+	}
+	return updateKueueStatus(c.ctx, c.operatorClient, c.operatorNamespace, kueue.Name, func(status *kueuev1alpha1.KueueStatus) {
+		status.Rollout = rollout
+		v1helpers.SetOperatorCondition(&status.Conditions, degraded)
+	})
+}
+
+// currentRolloutRevision returns the image and the configuration revision in use.
+func currentRolloutRevision(kueue *kueuev1alpha1.Kueue) (kueuev1alpha1.RolloutRevision, bool) {
+	for _, revision := range kueue.Status.ConfigRevisions {
+		if revision.Current {
+			return kueuev1alpha1.RolloutRevision{Image: kueue.Spec.Image, ConfigRevision: revision.Revision}, true
+		}
+	}
+	return kueuev1alpha1.RolloutRevision{}, false
+}
+
+// deploymentAvailable returns whether the kueue Deployment was applied for the current
+// generation of the Kueue and every replica runs its latest template and is available,
+// and otherwise why not.
+func (c *RolloutController) deploymentAvailable(kueue *kueuev1alpha1.Kueue) (bool, string, error) {
+	deployment, err := c.deploymentLister.Deployments(c.operatorNamespace).Get(operatorclient.OperandName)
+	if errors.IsNotFound(err) {
+		return false, fmt.Sprintf("Deployment %s does not exist", operatorclient.OperandName), nil
+	}
+	if err != nil {
+		return false, "", err
+	}
+	replicas := ptr.Deref(deployment.Spec.Replicas, 1)
+	switch {
+	case deployment.Annotations[kueueGenerationAnnotation] != strconv.FormatInt(kueue.Generation, 10) ||
+		len(deployment.Spec.Template.Spec.Containers) == 0 || deployment.Spec.Template.Spec.Containers[0].Image != kueue.Spec.Image:
+		return false, fmt.Sprintf("Deployment %s has not been applied for generation %d", deployment.Name, kueue.Generation), nil
+	case deployment.Status.ObservedGeneration < deployment.Generation:
+		return false, fmt.Sprintf("Deployment %s has not observed generation %d", deployment.Name, deployment.Generation), nil
+	case deployment.Status.UpdatedReplicas < replicas || deployment.Status.Replicas > replicas:
+		return false, fmt.Sprintf("Deployment %s has %d of %d replicas updated", deployment.Name, deployment.Status.UpdatedReplicas, replicas), nil
+	case deployment.Status.AvailableReplicas < replicas:
+		return false, fmt.Sprintf("Deployment %s has %d of %d replicas available", deployment.Name, deployment.Status.AvailableReplicas, replicas), nil
+	}
+	return true, "", nil
+}
+
+// rollBack sets spec.image to image, and spec.config.rollbackTo to the pinned revision.
+func (c *RolloutController) rollBack(image string, pinned *kueuev1alpha1.PinnedConfig) error {
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		kueue, err := c.operatorClient.Kueues(c.operatorNamespace).Get(c.ctx, operatorclient.OperatorConfigName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		kueue.Spec.Image = image
+		if pinned != nil {
+			kueue.Spec.Config.RollbackTo = ptr.To(pinned.Revision)
+		}
+		_, err = c.operatorClient.Kueues(c.operatorNamespace).Update(c.ctx, kueue, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+// unpinConfig unsets spec.config.rollbackTo once spec.config renders another
+// configuration than the one that was rolled back. It returns true when the pinned
+// revision is no longer in spec.config.rollbackTo, also when the user changed it.
+func (c *RolloutController) unpinConfig(kueue *kueuev1alpha1.Kueue, pinned kueuev1alpha1.PinnedConfig) (bool, error) {
+	if kueue.Spec.Config.RollbackTo == nil || *kueue.Spec.Config.RollbackTo != pinned.Revision {
+		return true, nil
+	}
+	hash, err := renderedConfigHash(kueue)
+	if err != nil {
+		return false, err
+	}
+	if hash == pinned.RolledBackHash {
+		return false, nil
+	}
+	err = retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		kueue, err := c.operatorClient.Kueues(c.operatorNamespace).Get(c.ctx, operatorclient.OperatorConfigName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if kueue.Spec.Config.RollbackTo == nil || *kueue.Spec.Config.RollbackTo != pinned.Revision {
+			return nil
+		}
+		kueue.Spec.Config.RollbackTo = nil
+		_, err = c.operatorClient.Kueues(c.operatorNamespace).Update(c.ctx, kueue, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		return false, err
+	}
+	c.eventRecorder.Eventf("ConfigRollbackUnset", "Unset spec.config.rollbackTo %d of the automatic rollback, spec.config changed since", pinned.Revision)
+	return true, nil
+}
+
+// renderedConfigHash returns the content hash of the configuration rendered from
+// spec.config, as recorded in status.configRevisions.
+func renderedConfigHash(kueue *kueuev1alpha1.Kueue) (string, error) {
+	cfgMap, err := configmap.BuildConfigMap(kueue.Namespace, kueue.Spec.Config, kueue.Spec.MultiKueue)
+	if err != nil {
+		return "", err
+	}
+	return configmap.Hash(cfgMap.Data), nil
+}
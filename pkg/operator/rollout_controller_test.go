@@ -0,0 +1,221 @@
+package operator
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	appsv1listers "k8s.io/client-go/listers/apps/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	clocktesting "k8s.io/utils/clock/testing"
+	"k8s.io/utils/ptr"
+
+	kueuev1alpha1 "github.com/openshift/kueue-operator/pkg/apis/kueueoperator/v1alpha1"
+	"github.com/openshift/kueue-operator/pkg/namespace"
+	"github.com/openshift/kueue-operator/pkg/operator/operatorclient"
+)
+
+func newTestRolloutController(t *testing.T, kueue *kueuev1alpha1.Kueue) (*RolloutController, cache.Indexer, *clocktesting.FakeClock) {
+	t.Helper()
+	defaults, _ := newTestDefaultsController(t, kueue)
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	fakeClock := clocktesting.NewFakeClock(time.Now())
+	return &RolloutController{
+		ctx:               defaults.ctx,
+		operatorClient:    defaults.operatorClient,
+		deploymentLister:  appsv1listers.NewDeploymentLister(indexer),
+		eventRecorder:     defaults.eventRecorder,
+		queue:             workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "test"),
+		operatorNamespace: defaults.operatorNamespace,
+		clock:             fakeClock,
+	}, indexer, fakeClock
+}
+
+// newTestKueueDeployment returns the kueue Deployment as applied for the Kueue, with
+// available replicas.
+func newTestKueueDeployment(kueue *kueuev1alpha1.Kueue, available int32) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        operatorclient.OperandName,
+			Namespace:   namespace.GetNamespace(),
+			Generation:  kueue.Generation,
+			Annotations: map[string]string{kueueGenerationAnnotation: strconv.FormatInt(kueue.Generation, 10)},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: ptr.To[int32](1),
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "manager", Image: kueue.Spec.Image}}},
+			},
+		},
+		Status: appsv1.DeploymentStatus{
+			ObservedGeneration: kueue.Generation,
+			Replicas:           1,
+			UpdatedReplicas:    1,
+			AvailableReplicas:  available,
+		},
+	}
+}
+
+func (c *RolloutController) getKueue(t *testing.T) *kueuev1alpha1.Kueue {
+	t.Helper()
+	kueue, err := c.operatorClient.Kueues(namespace.GetNamespace()).Get(c.ctx, operatorclient.OperatorConfigName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return kueue
+}
+
+func TestRolloutControllerRollsBack(t *testing.T) {
+	kueue := newTestKueue()
+	kueue.Spec.AutomaticRollback = &kueuev1alpha1.AutomaticRollback{Window: metav1.Duration{Duration: 5 * time.Minute}}
+	kueue.Status.ConfigRevisions = []kueuev1alpha1.ConfigRevision{{Revision: 1, Hash: "a", Current: true}}
+	c, indexer, fakeClock := newTestRolloutController(t, kueue)
+	if err := indexer.Add(newTestKueueDeployment(kueue, 1)); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.sync(); err != nil {
+		t.Fatalf("Unexpected sync error: %v", err)
+	}
+	good := kueuev1alpha1.RolloutRevision{Image: kueue.Spec.Image, ConfigRevision: 1}
+	if rollout := c.getKueue(t).Status.Rollout; rollout.State != kueuev1alpha1.RolloutStateHealthy || !cmp.Equal(rollout.LastKnownGood, &good) {
+		t.Fatalf("Expected a healthy rollout, got %+v", rollout)
+	}
+
+	// A new image that does not become available within the window is rolled back.
+	kueue = c.getKueue(t)
+	kueue.Spec.Image = "registry.k8s.io/kueue/kueue:v0.11.0"
+	kueue.Generation = 4
+	if _, err := c.operatorClient.Kueues(namespace.GetNamespace()).Update(c.ctx, kueue, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := indexer.Update(newTestKueueDeployment(kueue, 0)); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.sync(); err != nil {
+		t.Fatalf("Unexpected sync error: %v", err)
+	}
+	if rollout := c.getKueue(t).Status.Rollout; rollout.State != kueuev1alpha1.RolloutStateProgressing {
+		t.Fatalf("Expected a progressing rollout, got %+v", rollout)
+	}
+	fakeClock.Step(6 * time.Minute)
+	if err := c.sync(); err != nil {
+		t.Fatalf("Unexpected sync error: %v", err)
+	}
+
+	kueue = c.getKueue(t)
+	if kueue.Spec.Image != good.Image || kueue.Spec.Config.RollbackTo != nil {
+		t.Errorf("Expected image %s without a config rollback, got %s and %v", good.Image, kueue.Spec.Image, kueue.Spec.Config.RollbackTo)
+	}
+	if rollout := kueue.Status.Rollout; rollout.State != kueuev1alpha1.RolloutStateRolledBack || rollout.RolledBackFrom == nil || rollout.RolledBackFrom.Image != "registry.k8s.io/kueue/kueue:v0.11.0" {
+		t.Errorf("Expected a rolled back rollout, got %+v", rollout)
+	}
+	condition := v1helpers.FindOperatorCondition(kueue.Status.Conditions, "RolloutControllerDegraded")
+	if condition == nil || condition.Status != operatorv1.ConditionTrue || condition.Reason != "RolledBack" || !strings.Contains(condition.Message, "0 of 1 replicas available") {
+		t.Errorf("Unexpected RolloutControllerDegraded condition %+v", condition)
+	}
+
+	// The restored revision keeps the condition until the next change.
+	if err := c.sync(); err != nil {
+		t.Fatalf("Unexpected sync error: %v", err)
+	}
+	if rollout := c.getKueue(t).Status.Rollout; rollout.State != kueuev1alpha1.RolloutStateProgressing || rollout.Current != good || rollout.RolledBackFrom == nil {
+		t.Errorf("Expected the rollout of the restored revision, got %+v", rollout)
+	}
+}
+
+func TestRolloutControllerUnpinsConfig(t *testing.T) {
+	kueue := newTestKueue()
+	kueue.Spec.AutomaticRollback = &kueuev1alpha1.AutomaticRollback{Window: metav1.Duration{Duration: 5 * time.Minute}}
+	kueue.Status.ConfigRevisions = []kueuev1alpha1.ConfigRevision{{Revision: 1, Hash: "a", Current: true}}
+	c, indexer, fakeClock := newTestRolloutController(t, kueue)
+	if err := indexer.Add(newTestKueueDeployment(kueue, 1)); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.sync(); err != nil {
+		t.Fatalf("Unexpected sync error: %v", err)
+	}
+
+	// A configuration that does not become available is rolled back to revision 1.
+	kueue = c.getKueue(t)
+	kueue.Spec.Config.Integrations.Frameworks = []string{"batch/job", "pod"}
+	kueue.Generation = 4
+	kueue.Status.ConfigRevisions = []kueuev1alpha1.ConfigRevision{{Revision: 1, Hash: "a"}, {Revision: 2, Hash: "b", Current: true}}
+	if _, err := c.operatorClient.Kueues(namespace.GetNamespace()).Update(c.ctx, kueue, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := indexer.Update(newTestKueueDeployment(kueue, 0)); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.sync(); err != nil {
+		t.Fatalf("Unexpected sync error: %v", err)
+	}
+	fakeClock.Step(6 * time.Minute)
+	if err := c.sync(); err != nil {
+		t.Fatalf("Unexpected sync error: %v", err)
+	}
+	kueue = c.getKueue(t)
+	if kueue.Spec.Config.RollbackTo == nil || *kueue.Spec.Config.RollbackTo != 1 {
+		t.Fatalf("Expected spec.config.rollbackTo 1, got %v", kueue.Spec.Config.RollbackTo)
+	}
+	if pinned := kueue.Status.Rollout.PinnedConfig; pinned == nil || pinned.Revision != 1 {
+		t.Fatalf("Expected the rollback to pin revision 1, got %+v", pinned)
+	}
+
+	// The revision stays pinned while spec.config is unchanged.
+	if err := c.sync(); err != nil {
+		t.Fatalf("Unexpected sync error: %v", err)
+	}
+	if kueue = c.getKueue(t); kueue.Spec.Config.RollbackTo == nil {
+		t.Fatalf("Expected spec.config.rollbackTo to be kept")
+	}
+
+	// A change of spec.config unpins it.
+	kueue.Spec.Config.Integrations.Frameworks = []string{"batch/job", "kubeflow.org/pytorchjob"}
+	if _, err := c.operatorClient.Kueues(namespace.GetNamespace()).Update(c.ctx, kueue, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.sync(); err != nil {
+		t.Fatalf("Unexpected sync error: %v", err)
+	}
+	kueue = c.getKueue(t)
+	if kueue.Spec.Config.RollbackTo != nil || kueue.Status.Rollout.PinnedConfig != nil {
+		t.Errorf("Expected spec.config.rollbackTo to be unset, got %v and %+v", kueue.Spec.Config.RollbackTo, kueue.Status.Rollout.PinnedConfig)
+	}
+}
+
+func TestRolloutControllerWithoutKnownGood(t *testing.T) {
+	kueue := newTestKueue()
+	kueue.Spec.AutomaticRollback = &kueuev1alpha1.AutomaticRollback{}
+	kueue.Status.ConfigRevisions = []kueuev1alpha1.ConfigRevision{{Revision: 1, Hash: "a", Current: true}}
+	c, indexer, fakeClock := newTestRolloutController(t, kueue)
+	if err := indexer.Add(newTestKueueDeployment(kueue, 0)); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.sync(); err != nil {
+		t.Fatalf("Unexpected sync error: %v", err)
+	}
+	fakeClock.Step(defaultRollbackWindow + time.Second)
+	if err := c.sync(); err != nil {
+		t.Fatalf("Unexpected sync error: %v", err)
+	}
+
+	kueue = c.getKueue(t)
+	if kueue.Spec.Image != newTestKueue().Spec.Image {
+		t.Errorf("Expected the image to be kept, got %s", kueue.Spec.Image)
+	}
+	if kueue.Status.Rollout.State != kueuev1alpha1.RolloutStateFailed {
+		t.Errorf("Expected a failed rollout, got %+v", kueue.Status.Rollout)
+	}
+	condition := v1helpers.FindOperatorCondition(kueue.Status.Conditions, "RolloutControllerDegraded")
+	if condition == nil || condition.Reason != "RolloutFailed" {
+		t.Errorf("Unexpected RolloutControllerDegraded condition %+v", condition)
+	}
+}
@@ -81,6 +81,148 @@ func RunOperator(ctx context.Context, cc *controllercmd.ControllerContext) error
 		return err
 	}
 
+	defaultsController, err := NewDefaultsController(
+		ctx,
+		operatorConfigClient.KueueV1alpha1(),
+		operatorConfigInformers.Kueue().V1alpha1().Kueues(),
+		kubeInformersForNamespaces,
+		dynamicClient,
+		cc.EventRecorder,
+	)
+	if err != nil {
+		return err
+	}
+
+	localQueueController, err := NewLocalQueueController(
+		ctx,
+		operatorConfigClient.KueueV1alpha1(),
+		operatorConfigInformers.Kueue().V1alpha1().Kueues(),
+		kubeInformersForNamespaces,
+		dynamicClient,
+		cc.EventRecorder,
+	)
+	if err != nil {
+		return err
+	}
+
+	flavorDiscoveryController, err := NewFlavorDiscoveryController(
+		ctx,
+		operatorConfigClient.KueueV1alpha1(),
+		operatorConfigInformers.Kueue().V1alpha1().Kueues(),
+		kubeInformersForNamespaces,
+		dynamicClient,
+		cc.EventRecorder,
+	)
+	if err != nil {
+		return err
+	}
+
+	quotaController, err := NewQuotaController(
+		ctx,
+		operatorConfigClient.KueueV1alpha1(),
+		operatorConfigInformers.Kueue().V1alpha1().Kueues(),
+		kubeInformersForNamespaces,
+		dynamicClient,
+		cc.EventRecorder,
+	)
+	if err != nil {
+		return err
+	}
+
+	queueAccessController, err := NewQueueAccessController(
+		ctx,
+		operatorConfigClient.KueueV1alpha1(),
+		operatorConfigInformers.Kueue().V1alpha1().Kueues(),
+		kubeInformersForNamespaces,
+		kubeClient,
+		dynamicClient,
+		cc.EventRecorder,
+	)
+	if err != nil {
+		return err
+	}
+
+	multiKueueController, err := NewMultiKueueController(
+		ctx,
+		operatorConfigClient.KueueV1alpha1(),
+		operatorConfigInformers.Kueue().V1alpha1().Kueues(),
+		kubeInformersForNamespaces,
+		kubeClient,
+		dynamicClient,
+		cc.EventRecorder,
+	)
+	if err != nil {
+		return err
+	}
+
+	provisioningRequestController, err := NewProvisioningRequestController(
+		ctx,
+		operatorConfigClient.KueueV1alpha1(),
+		operatorConfigInformers.Kueue().V1alpha1().Kueues(),
+		dynamicClient,
+		cc.EventRecorder,
+	)
+	if err != nil {
+		return err
+	}
+
+	topologyController, err := NewTopologyController(
+		ctx,
+		operatorConfigClient.KueueV1alpha1(),
+		operatorConfigInformers.Kueue().V1alpha1().Kueues(),
+		kubeInformersForNamespaces,
+		dynamicClient,
+		cc.EventRecorder,
+	)
+	if err != nil {
+		return err
+	}
+
+	workloadPriorityController, err := NewWorkloadPriorityController(
+		ctx,
+		operatorConfigClient.KueueV1alpha1(),
+		operatorConfigInformers.Kueue().V1alpha1().Kueues(),
+		kubeInformersForNamespaces,
+		dynamicClient,
+		cc.EventRecorder,
+	)
+	if err != nil {
+		return err
+	}
+
+	admissionCheckController, err := NewAdmissionCheckController(
+		ctx,
+		operatorConfigClient.KueueV1alpha1(),
+		operatorConfigInformers.Kueue().V1alpha1().Kueues(),
+		dynamicClient,
+		cc.EventRecorder,
+	)
+	if err != nil {
+		return err
+	}
+
+	rolloutController, err := NewRolloutController(
+		ctx,
+		operatorConfigClient.KueueV1alpha1(),
+		operatorConfigInformers.Kueue().V1alpha1().Kueues(),
+		kubeInformersForNamespaces,
+		cc.EventRecorder,
+	)
+	if err != nil {
+		return err
+	}
+
+	canaryController, err := NewCanaryController(
+		ctx,
+		operatorConfigClient.KueueV1alpha1(),
+		operatorConfigInformers.Kueue().V1alpha1().Kueues(),
+		kubeInformersForNamespaces,
+		cc.EventRecorder,
+	)
+	if err != nil {
+		return err
+	}
+
 	logLevelController := loglevel.NewClusterOperatorLoggingController(kueueClient, cc.EventRecorder)
 
 	klog.Infof("Starting informers")
@@ -91,6 +233,30 @@ func RunOperator(ctx context.Context, cc *controllercmd.ControllerContext) error
 	go logLevelController.Run(ctx, 1)
 	klog.Infof("Starting target config reconciler")
 	go targetConfigReconciler.Run(1, ctx.Done())
+	klog.Infof("Starting defaults controller")
+	go defaultsController.Run(ctx, 1)
+	klog.Infof("Starting local queue controller")
+	go localQueueController.Run(ctx, 1)
+	klog.Infof("Starting flavor discovery controller")
+	go flavorDiscoveryController.Run(ctx, 1)
+	klog.Infof("Starting quota controller")
+	go quotaController.Run(ctx, 1)
+	klog.Infof("Starting queue access controller")
+	go queueAccessController.Run(ctx, 1)
+	klog.Infof("Starting MultiKueue controller")
+	go multiKueueController.Run(ctx, 1)
+	klog.Infof("Starting provisioning request controller")
+	go provisioningRequestController.Run(ctx, 1)
+	klog.Infof("Starting topology controller")
+	go topologyController.Run(ctx, 1)
+	klog.Infof("Starting workload priority controller")
+	go workloadPriorityController.Run(ctx, 1)
+	klog.Infof("Starting admission check controller")
+	go admissionCheckController.Run(ctx, 1)
+	klog.Infof("Starting rollout controller")
+	go rolloutController.Run(ctx, 1)
+	klog.Infof("Starting canary controller")
+	go canaryController.Run(ctx, 1)
 
 	<-ctx.Done()
 	return nil
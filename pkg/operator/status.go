@@ -0,0 +1,35 @@
+package operator
+
+import (
+	"context"
+
+	kueuev1alpha1 "github.com/openshift/kueue-operator/pkg/apis/kueueoperator/v1alpha1"
+	kueueconfigclient "github.com/openshift/kueue-operator/pkg/generated/clientset/versioned/typed/kueueoperator/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+)
+
+// updateKueueStatus applies updateFuncs to the latest status of the named Kueue
+// and writes it back when something changed, retrying on conflicts.
+func updateKueueStatus(ctx context.Context, client kueueconfigclient.KueueV1alpha1Interface, namespace, name string, updateFuncs ...func(status *kueuev1alpha1.KueueStatus)) error {
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		kueue, err := client.Kueues(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		updated := kueue.DeepCopy()
+		for _, update := range updateFuncs {
+			update(&updated.Status)
+		}
+		if equality.Semantic.DeepEqual(kueue.Status, updated.Status) {
+			return nil
+		}
+		_, err = client.Kueues(namespace).UpdateStatus(ctx, updated, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+func (c *TargetConfigReconciler) updateKueueStatus(name string, updateFuncs ...func(status *kueuev1alpha1.KueueStatus)) error {
+	return updateKueueStatus(c.ctx, c.operatorClient, c.operatorNamespace, name, updateFuncs...)
+}
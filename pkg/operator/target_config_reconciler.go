@@ -2,13 +2,13 @@ package operator
 
 import (
 	"context"
+	goerrors "errors"
 	"fmt"
-	"strconv"
+	"strings"
 	"time"
 
 	operatorv1 "github.com/openshift/api/operator/v1"
 	openshiftrouteclientset "github.com/openshift/client-go/route/clientset/versioned"
-	"github.com/openshift/kueue-operator/bindata"
 	kueuev1alpha1 "github.com/openshift/kueue-operator/pkg/apis/kueueoperator/v1alpha1"
 	"github.com/openshift/kueue-operator/pkg/builders/configmap"
 	kueueconfigclient "github.com/openshift/kueue-operator/pkg/generated/clientset/versioned/typed/kueueoperator/v1alpha1"
@@ -16,24 +16,18 @@ import (
 	"github.com/openshift/kueue-operator/pkg/namespace"
 	"github.com/openshift/kueue-operator/pkg/operator/operatorclient"
 	"github.com/openshift/library-go/pkg/operator/events"
-	"github.com/openshift/library-go/pkg/operator/resource/resourceapply"
 	"github.com/openshift/library-go/pkg/operator/resource/resourcemerge"
-	"github.com/openshift/library-go/pkg/operator/resource/resourceread"
 	"github.com/openshift/library-go/pkg/operator/v1helpers"
-	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
-
-	"github.com/openshift/library-go/pkg/controller"
-	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
-	"k8s.io/utils/ptr"
-
-	rbacv1 "k8s.io/api/rbac/v1"
 	apiextv1 "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/typed/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
@@ -46,6 +40,8 @@ const (
 	KueueServiceAccount = "openshift-kueue-operator"
 	PromRouteName       = "prometheus-k8s"
 	PromTokenPrefix     = "prometheus-k8s-token"
+
+	fullResyncInterval = 10 * time.Minute
 )
 
 type TargetConfigReconciler struct {
@@ -60,6 +56,10 @@ type TargetConfigReconciler struct {
 	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces
 	crdClient                  apiextv1.ApiextensionsV1Interface
 	operatorNamespace          string
+
+	managedInformers informers.SharedInformerFactory
+	crdInformer      cache.SharedIndexInformer
+	appliedVersions  appliedVersions
 }
 
 func NewTargetConfigReconciler(
@@ -93,24 +93,25 @@ func NewTargetConfigReconciler(
 		return nil, err
 	}
 
-	_, err = kubeInformersForNamespaces.InformersFor(c.operatorNamespace).Core().V1().ConfigMaps().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
-		AddFunc: func(obj interface{}) {},
-		UpdateFunc: func(old, new interface{}) {
-			cm, ok := old.(*v1.ConfigMap)
-			if !ok {
-				klog.Errorf("Unable to convert obj to ConfigMap")
-				return
+	if err := c.newDriftInformers(); err != nil {
+		return nil, err
+	}
+
+	// Only the Kueue configuration is relevant out of the ConfigMaps in the operator
+	// namespace, and a change to it only needs the ConfigMap and the deployment reconciled.
+	_, err = kubeInformersForNamespaces.InformersFor(c.operatorNamespace).Core().V1().ConfigMaps().Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: func(obj interface{}) bool {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
 			}
-			c.queue.Add(queueItem{kind: "configmap", name: cm.Name})
-		},
-		DeleteFunc: func(obj interface{}) {
 			cm, ok := obj.(*v1.ConfigMap)
 			if !ok {
 				klog.Errorf("Unable to convert obj to ConfigMap")
-				return
+				return false
 			}
-			c.queue.Add(queueItem{kind: "configmap", name: cm.Name})
+			return cm.Name == KueueConfigMap
 		},
+		Handler: c.eventHandler(queueItem{kind: "configmaps", name: KueueConfigMap}),
 	})
 	if err != nil {
 		return nil, err
@@ -119,544 +120,140 @@ func NewTargetConfigReconciler(
 	return c, nil
 }
 
-func (c TargetConfigReconciler) sync(item queueItem) error {
+func (c *TargetConfigReconciler) sync(item queueItem) error {
 	kueue, err := c.operatorClient.Kueues(c.operatorNamespace).Get(c.ctx, operatorclient.OperatorConfigName, metav1.GetOptions{})
 	if err != nil {
 		klog.ErrorS(err, "unable to get operator configuration", "namespace", c.operatorNamespace, "kueue", operatorclient.OperatorConfigName)
 		return err
 	}
 
-	specAnnotations := map[string]string{
-		"kueueoperator.operator.openshift.io/cluster": strconv.FormatInt(kueue.Generation, 10),
-	}
-
-	if cm, _, err := c.manageConfigMap(kueue); err != nil {
-		return err
-	} else {
-		resourceVersion := "0"
-		if cm != nil { // SyncConfigMap can return nil
-			resourceVersion = cm.ObjectMeta.ResourceVersion
+	steps := resourcePipeline()
+	if kueue.Spec.Config.RollbackTo != nil {
+		revisions, err := c.listConfigRevisions()
+		if err != nil {
+			return err
 		}
-		specAnnotations["kueue/configmap"] = resourceVersion
-
-	}
-
-	crdAnnotations, crdErr := c.manageCustomResources(kueue)
-
-	if crdErr != nil {
-		klog.Error("unable to manage custom resource")
-		return crdErr
-	}
-
-	for key, val := range crdAnnotations {
-		specAnnotations[key] = val
-	}
-
-	if sa, _, err := c.manageServiceAccount(kueue); err != nil {
-		klog.Error("unable to manage service account")
-		return err
-	} else {
-		resourceVersion := "0"
-		if sa != nil { // SyncConfigMap can return nil
-			resourceVersion = sa.ObjectMeta.ResourceVersion
+		steps = withConfigRollback(*kueue.Spec.Config.RollbackTo, steps, revisions)
+	}
+	selected := selectSteps(kueue, steps, item)
+
+	// The deployment is re-applied with the annotations of the resources that are not
+	// reconciled this time, as recorded on its pod template. With the OnNextRestart change
+	// policy the content hashes are kept as well, so that a change does not restart the manager.
+	onNextRestart := kueue.Spec.Config.ChangePolicy == kueuev1alpha1.ConfigChangePolicyOnNextRestart
+	specAnnotations := map[string]string{}
+	if selected != nil || onNextRestart {
+		if selected != nil {
+			klog.V(4).InfoS("Reconciling resources affected by change", "kind", item.kind, "name", item.name, "resources", sets.List(selected))
 		}
-		specAnnotations["serviceaccounts/kueue-operator"] = resourceVersion
-	}
-
-	if secret, _, err := c.manageSecret(kueue); err != nil {
-		klog.Error("unable to create secret")
-		return err
-	} else {
-		resourceVersion := "0"
-		if secret != nil { // SyncConfigMap can return nil
-			resourceVersion = secret.ObjectMeta.ResourceVersion
+		deployment, err := c.kubeClient.AppsV1().Deployments(c.operatorNamespace).Get(c.ctx, operatorclient.OperandName, metav1.GetOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			return err
 		}
-		specAnnotations["secret/kueue-webhook-server-cert"] = resourceVersion
-	}
-
-	if roleBindings, _, err := c.manageRole(kueue, "assets/kueue-operator/role-leader-election.yaml"); err != nil {
-		klog.Error("unable to create role leader-election")
-		return err
-	} else {
-		resourceVersion := "0"
-		if roleBindings != nil { // SyncConfigMap can return nil
-			resourceVersion = roleBindings.ObjectMeta.ResourceVersion
+		if err == nil {
+			for key, value := range deployment.Spec.Template.Annotations {
+				if selected != nil || contentHashAnnotations.Has(key) {
+					specAnnotations[key] = value
+				}
+			}
 		}
-		specAnnotations["role/leader-election"] = resourceVersion
 	}
 
-	if roleBindings, _, err := c.manageRoleBindings(kueue, "assets/kueue-operator/rolebinding-leader-election.yaml"); err != nil {
-		klog.Error("unable to bind role leader-election")
-		return err
-	} else {
-		resourceVersion := "0"
-		if roleBindings != nil { // SyncConfigMap can return nil
-			resourceVersion = roleBindings.ObjectMeta.ResourceVersion
+	result := c.runPipeline(kueue, steps, selected, specAnnotations)
+	for _, resource := range result.resources {
+		if resource.State != kueuev1alpha1.ResourceStateApplied {
+			klog.InfoS("Resource was not applied", "resource", resource.Name, "state", resource.State, "message", resource.Message)
 		}
-		specAnnotations["rolebindings/leader-election"] = resourceVersion
 	}
 
-	if service, _, err := c.manageService(kueue, "assets/kueue-operator/metrics-service.yaml"); err != nil {
-		klog.Error("unable to manage metrics service")
-		return err
-	} else {
-		resourceVersion := "0"
-		if service != nil { // SyncConfigMap can return nil
-			resourceVersion = service.ObjectMeta.ResourceVersion
+	// The objects of a removed or aborted canary are only looked for on a full reconcile.
+	if selected == nil && activeCanary(kueue) == nil {
+		if err := c.deleteCanary(); err != nil {
+			result.errors = append(result.errors, fmt.Errorf("canary: %w", err))
 		}
-		specAnnotations["service/metrics-service"] = resourceVersion
 	}
 
-	if service, _, err := c.manageService(kueue, "assets/kueue-operator/visibility-service.yaml"); err != nil {
-		klog.Error("unable to manage visbility service")
-		return err
-	} else {
-		resourceVersion := "0"
-		if service != nil { // SyncConfigMap can return nil
-			resourceVersion = service.ObjectMeta.ResourceVersion
+	// Every configuration applied is recorded, so that it can be rolled back to.
+	var history []kueuev1alpha1.ConfigRevision
+	for _, resource := range result.resources {
+		if resource.Name != "configmap" || resource.State != kueuev1alpha1.ResourceStateApplied || (selected != nil && !selected.Has("configmap")) {
+			continue
 		}
-		specAnnotations["service/visibility-service"] = resourceVersion
-	}
-
-	if service, _, err := c.manageService(kueue, "assets/kueue-operator/webhook-service.yaml"); err != nil {
-		klog.Error("unable to manage webhook service")
-		return err
-	} else {
-		resourceVersion := "0"
-		if service != nil { // SyncConfigMap can return nil
-			resourceVersion = service.ObjectMeta.ResourceVersion
+		history, err = c.recordConfigRevision(kueue)
+		if err != nil {
+			result.errors = append(result.errors, fmt.Errorf("configmap: %w", err))
 		}
-		specAnnotations["service/webhook-service"] = resourceVersion
 	}
 
-	annotations, err := c.manageClusterRoles(kueue)
-	if err != nil {
-		klog.Error("unable to manage cluster roles")
-		return err
+	degraded := operatorv1.OperatorCondition{
+		Type:   "TargetConfigControllerDegraded",
+		Status: operatorv1.ConditionFalse,
+		Reason: "AsExpected",
 	}
-	for key, val := range annotations {
-		specAnnotations[key] = val
+	if len(result.errors) > 0 {
+		degraded.Status = operatorv1.ConditionTrue
+		degraded.Reason = "ApplyFailed"
+		degraded.Message = utilerrors.NewAggregate(result.errors).Error()
 	}
 
-	if openshiftClusterRole, _, err := c.manageOpenshiftClusterRolesForKueue(kueue); err != nil {
-		klog.Error("unable to manage openshift cluster roles")
-		return err
-	} else {
-		resourceVersion := "0"
-		if openshiftClusterRole != nil { // SyncConfigMap can return nil
-			resourceVersion = openshiftClusterRole.ObjectMeta.ResourceVersion
+	// A targeted reconcile only applies some of the tracked objects, so it can report a
+	// pending restart but not clear one.
+	var pendingRestart *operatorv1.OperatorCondition
+	if selected == nil || len(result.pendingRestart) > 0 {
+		pendingRestart = &operatorv1.OperatorCondition{
+			Type:   "ConfigPendingRestart",
+			Status: operatorv1.ConditionFalse,
+			Reason: "AsExpected",
 		}
-		specAnnotations["clusterrole/openshift-roles"] = resourceVersion
-	}
-
-	if openshiftClusterRoleBinding, _, err := c.manageOpenshiftClusterRolesBindingForKueue(kueue); err != nil {
-		klog.Error("unable to manage openshift cluster roles binding")
-		return err
-	} else {
-		resourceVersion := "0"
-		if openshiftClusterRoleBinding != nil { // SyncConfigMap can return nil
-			resourceVersion = openshiftClusterRoleBinding.ObjectMeta.ResourceVersion
+		if len(result.pendingRestart) > 0 {
+			pendingRestart.Status = operatorv1.ConditionTrue
+			pendingRestart.Reason = "OnNextRestart"
+			pendingRestart.Message = fmt.Sprintf("%s changed, the Kueue manager reads the change when its pods restart", strings.Join(result.pendingRestart, ", "))
 		}
-		specAnnotations["clusterrolebinding/openshift-roles"] = resourceVersion
 	}
 
-	if service, _, err := c.manageClusterRoleBindings(kueue, "assets/kueue-operator/clusterrolebinding-kube-proxy.yaml"); err != nil {
-		klog.Error("unable to manage kube proxy cluster roles")
-		return err
-	} else {
-		resourceVersion := "0"
-		if service != nil { // SyncConfigMap can return nil
-			resourceVersion = service.ObjectMeta.ResourceVersion
+	// The configuration is only validated when the configmap step runs.
+	var configInvalid *operatorv1.OperatorCondition
+	if selected == nil || selected.Has("configmap") {
+		configInvalid = &operatorv1.OperatorCondition{
+			Type:   "ConfigInvalid",
+			Status: operatorv1.ConditionFalse,
+			Reason: "AsExpected",
 		}
-		specAnnotations["clusterrolebinding/kube-proxy"] = resourceVersion
-	}
-
-	if service, _, err := c.manageClusterRoleBindings(kueue, "assets/kueue-operator/clusterrolebinding-kueue-manager-role.yaml"); err != nil {
-		klog.Error("unable to manage cluster role kueue-manager")
-		return err
-	} else {
-		resourceVersion := "0"
-		if service != nil { // SyncConfigMap can return nil
-			resourceVersion = service.ObjectMeta.ResourceVersion
-		}
-		specAnnotations["clusterrolebinding/kueue-manager-role"] = resourceVersion
-	}
-
-	deployment, _, err := c.manageDeployment(kueue, specAnnotations)
-	if err != nil {
-		klog.Error("unable to manage deployment")
-		return err
-	}
-
-	_, _, err = v1helpers.UpdateStatus(c.ctx, c.kueueClient, func(status *operatorv1.OperatorStatus) error {
-		resourcemerge.SetDeploymentGeneration(&status.Generations, deployment)
-		return nil
-	})
-
-	if err != nil {
-		return err
-	}
-
-	if _, _, err := c.manageMutatingWebhook(kueue); err != nil {
-		klog.Error("unable to manage mutating webhook")
-		return err
-	}
-
-	if _, _, err := c.manageValidatingWebhook(kueue); err != nil {
-		klog.Error("unable to manage validating webhook")
-		return err
-	}
-
-	return nil
-}
-
-func (c *TargetConfigReconciler) manageConfigMap(kueue *kueuev1alpha1.Kueue) (*v1.ConfigMap, bool, error) {
-	required, err := c.kubeClient.CoreV1().ConfigMaps(c.operatorNamespace).Get(context.TODO(), KueueConfigMap, metav1.GetOptions{})
-
-	if errors.IsNotFound(err) {
-		return c.buildAndApplyConfigMap(nil, kueue.Spec.Config)
-	} else if err != nil {
-		klog.Errorf("Cannot load ConfigMap %s/kueue-manager-config for the kueue operator", c.operatorNamespace)
-		return nil, false, err
-	}
-	return c.buildAndApplyConfigMap(required, kueue.Spec.Config)
-}
-
-func (c *TargetConfigReconciler) buildAndApplyConfigMap(oldCfgMap *v1.ConfigMap, kueueCfg kueuev1alpha1.KueueConfiguration) (*v1.ConfigMap, bool, error) {
-	cfgMap, buildErr := configmap.BuildConfigMap(c.operatorNamespace, kueueCfg)
-	if buildErr != nil {
-		klog.Errorf("Cannot build configmap %s for kueue", c.operatorNamespace)
-		return nil, false, buildErr
-	}
-	if oldCfgMap != nil && oldCfgMap.Data["controller_manager_config.yaml"] == cfgMap.Data["controller_manager_config.yaml"] {
-		return nil, true, nil
-	}
-	klog.InfoS("Configmap difference detected", "Namespace", c.operatorNamespace, "ConfigMap", KueueConfigMap)
-	return resourceapply.ApplyConfigMap(c.ctx, c.kubeClient.CoreV1(), c.eventRecorder, cfgMap)
-
-}
-
-func (c *TargetConfigReconciler) manageServiceAccount(kueue *kueuev1alpha1.Kueue) (*v1.ServiceAccount, bool, error) {
-	required := resourceread.ReadServiceAccountV1OrDie(bindata.MustAsset("assets/kueue-operator/serviceaccount.yaml"))
-	required.Namespace = kueue.Namespace
-	ownerReference := metav1.OwnerReference{
-		APIVersion: "operator.openshift.io/v1alpha1",
-		Kind:       "Kueue",
-		Name:       kueue.Name,
-		UID:        kueue.UID,
-	}
-	required.OwnerReferences = []metav1.OwnerReference{
-		ownerReference,
-	}
-	controller.EnsureOwnerRef(required, ownerReference)
-
-	return resourceapply.ApplyServiceAccount(c.ctx, c.kubeClient.CoreV1(), c.eventRecorder, required)
-}
-
-func (c *TargetConfigReconciler) manageSecret(kueue *kueuev1alpha1.Kueue) (*v1.Secret, bool, error) {
-	required := resourceread.ReadSecretV1OrDie(bindata.MustAsset("assets/kueue-operator/secret.yaml"))
-	required.Namespace = kueue.Namespace
-	ownerReference := metav1.OwnerReference{
-		APIVersion: "operator.openshift.io/v1alpha1",
-		Kind:       "Kueue",
-		Name:       kueue.Name,
-		UID:        kueue.UID,
-	}
-	required.OwnerReferences = []metav1.OwnerReference{
-		ownerReference,
-	}
-	controller.EnsureOwnerRef(required, ownerReference)
-
-	return resourceapply.ApplySecret(c.ctx, c.kubeClient.CoreV1(), c.eventRecorder, required)
-}
-
-func (c *TargetConfigReconciler) manageMutatingWebhook(kueue *kueuev1alpha1.Kueue) (*admissionregistrationv1.MutatingWebhookConfiguration, bool, error) {
-	required := resourceread.ReadMutatingWebhookConfigurationV1OrDie(bindata.MustAsset("assets/kueue-operator/mutatingwebhook.yaml"))
-	ownerReference := metav1.OwnerReference{
-		APIVersion: "operator.openshift.io/v1alpha1",
-		Kind:       "Kueue",
-		Name:       kueue.Name,
-		UID:        kueue.UID,
-	}
-	required.OwnerReferences = []metav1.OwnerReference{
-		ownerReference,
-	}
-	controller.EnsureOwnerRef(required, ownerReference)
-
-	for i := range required.Webhooks {
-		required.Webhooks[i].ClientConfig.Service.Namespace = kueue.Namespace
-	}
-	return resourceapply.ApplyMutatingWebhookConfigurationImproved(c.ctx, c.kubeClient.AdmissionregistrationV1(), c.eventRecorder, required, resourceapply.NewResourceCache())
-}
-
-func (c *TargetConfigReconciler) manageValidatingWebhook(kueue *kueuev1alpha1.Kueue) (*admissionregistrationv1.ValidatingWebhookConfiguration, bool, error) {
-	required := resourceread.ReadValidatingWebhookConfigurationV1OrDie(bindata.MustAsset("assets/kueue-operator/validatingwebhook.yaml"))
-	ownerReference := metav1.OwnerReference{
-		APIVersion: "operator.openshift.io/v1alpha1",
-		Kind:       "Kueue",
-		Name:       kueue.Name,
-		UID:        kueue.UID,
-	}
-	required.OwnerReferences = []metav1.OwnerReference{
-		ownerReference,
-	}
-	controller.EnsureOwnerRef(required, ownerReference)
-
-	for i := range required.Webhooks {
-		required.Webhooks[i].ClientConfig.Service.Namespace = kueue.Namespace
-	}
-	return resourceapply.ApplyValidatingWebhookConfigurationImproved(c.ctx, c.kubeClient.AdmissionregistrationV1(), c.eventRecorder, required, resourceapply.NewResourceCache())
-}
-
-func (c *TargetConfigReconciler) manageRoleBindings(kueue *kueuev1alpha1.Kueue, assetPath string) (*rbacv1.RoleBinding, bool, error) {
-	required := resourceread.ReadRoleBindingV1OrDie(bindata.MustAsset(assetPath))
-	ownerReference := metav1.OwnerReference{
-		APIVersion: "operator.openshift.io/v1alpha1",
-		Kind:       "Kueue",
-		Name:       kueue.Name,
-		UID:        kueue.UID,
-	}
-	required.OwnerReferences = []metav1.OwnerReference{
-		ownerReference,
-	}
-	controller.EnsureOwnerRef(required, ownerReference)
-
-	required.Namespace = kueue.Namespace
-	for i := range required.Subjects {
-		if required.Subjects[i].Kind != "ServiceAccount" {
-			continue
-		}
-		required.Subjects[i].Namespace = kueue.Namespace
-	}
-	return resourceapply.ApplyRoleBinding(c.ctx, c.kubeClient.RbacV1(), c.eventRecorder, required)
-}
-
-func (c *TargetConfigReconciler) manageClusterRoleBindings(kueue *kueuev1alpha1.Kueue, assetDir string) (*rbacv1.ClusterRoleBinding, bool, error) {
-	required := resourceread.ReadClusterRoleBindingV1OrDie(bindata.MustAsset(assetDir))
-	ownerReference := metav1.OwnerReference{
-		APIVersion: "operator.openshift.io/v1alpha1",
-		Kind:       "Kueue",
-		Name:       kueue.Name,
-		UID:        kueue.UID,
-	}
-	required.OwnerReferences = []metav1.OwnerReference{
-		ownerReference,
-	}
-	controller.EnsureOwnerRef(required, ownerReference)
-
-	required.Namespace = kueue.Namespace
-	for i := range required.Subjects {
-		required.Subjects[i].Namespace = kueue.Namespace
-	}
-	return resourceapply.ApplyClusterRoleBinding(c.ctx, c.kubeClient.RbacV1(), c.eventRecorder, required)
-}
-
-func (c *TargetConfigReconciler) manageRole(kueue *kueuev1alpha1.Kueue, assetPath string) (*rbacv1.Role, bool, error) {
-	required := resourceread.ReadRoleV1OrDie(bindata.MustAsset(assetPath))
-	ownerReference := metav1.OwnerReference{
-		APIVersion: "operator.openshift.io/v1alpha1",
-		Kind:       "Kueue",
-		Name:       kueue.Name,
-		UID:        kueue.UID,
-	}
-	required.OwnerReferences = []metav1.OwnerReference{
-		ownerReference,
-	}
-	controller.EnsureOwnerRef(required, ownerReference)
-
-	required.Namespace = kueue.Namespace
-	return resourceapply.ApplyRole(c.ctx, c.kubeClient.RbacV1(), c.eventRecorder, required)
-}
-
-func (c *TargetConfigReconciler) manageService(kueue *kueuev1alpha1.Kueue, assetPath string) (*v1.Service, bool, error) {
-	required := resourceread.ReadServiceV1OrDie(bindata.MustAsset(assetPath))
-	ownerReference := metav1.OwnerReference{
-		APIVersion: "operator.openshift.io/v1alpha1",
-		Kind:       "Kueue",
-		Name:       kueue.Name,
-		UID:        kueue.UID,
-	}
-	required.OwnerReferences = []metav1.OwnerReference{
-		ownerReference,
-	}
-	controller.EnsureOwnerRef(required, ownerReference)
-
-	required.Namespace = kueue.Namespace
-	return resourceapply.ApplyService(c.ctx, c.kubeClient.CoreV1(), c.eventRecorder, required)
-}
-
-func (c *TargetConfigReconciler) manageClusterRoles(kueue *kueuev1alpha1.Kueue) (map[string]string, error) {
-	returnMap := make(map[string]string, 34)
-	// This is hardcoded due to the amount of clusterroles that kueue has.
-	for i := 0; i < 35; i++ {
-		assetPath := fmt.Sprintf("assets/kueue-operator/clusterrole_%d.yml", i)
-		clusterRoleName := fmt.Sprintf("clusterrole/clusterrole_%d.yml", i)
-		required := resourceread.ReadClusterRoleV1OrDie(bindata.MustAsset(assetPath))
-		if required.AggregationRule != nil {
-			continue
-		}
-		ownerReference := metav1.OwnerReference{
-			APIVersion: "operator.openshift.io/v1alpha1",
-			Kind:       "Kueue",
-			Name:       kueue.Name,
-			UID:        kueue.UID,
-		}
-		required.OwnerReferences = []metav1.OwnerReference{
-			ownerReference,
-		}
-		controller.EnsureOwnerRef(required, ownerReference)
-
-		clusterRole, _, err := resourceapply.ApplyClusterRole(c.ctx, c.kubeClient.RbacV1(), c.eventRecorder, required)
-		if err != nil {
-			return nil, err
-		}
-
-		resourceVersion := "0"
-		if clusterRole != nil { // SyncConfigMap can return nil
-			resourceVersion = clusterRole.ObjectMeta.ResourceVersion
+		for _, err := range result.errors {
+			var invalid *configmap.InvalidConfigError
+			if goerrors.As(err, &invalid) {
+				configInvalid.Status = operatorv1.ConditionTrue
+				configInvalid.Reason = "ValidationFailed"
+				configInvalid.Message = invalid.Error() + ", the running configuration is kept"
+			}
 		}
-		returnMap[clusterRoleName] = resourceVersion
 	}
-	return returnMap, nil
-}
 
-func (c *TargetConfigReconciler) manageOpenshiftClusterRolesBindingForKueue(kueue *kueuev1alpha1.Kueue) (*rbacv1.ClusterRoleBinding, bool, error) {
-	clusterRoleBinding := &rbacv1.ClusterRoleBinding{
-		ObjectMeta: metav1.ObjectMeta{
-			Name: "kueue-openshift-cluster-role-binding",
-		},
-		Subjects: []rbacv1.Subject{
-			{
-				Kind:      "ServiceAccount",
-				Name:      "kueue-controller-manager",
-				Namespace: kueue.Namespace,
-			},
-		},
-		RoleRef: rbacv1.RoleRef{
-			APIGroup: "rbac.authorization.k8s.io",
-			Kind:     "ClusterRole",
-			Name:     "kueue-openshift-roles",
-		},
-	}
-
-	ownerReference := metav1.OwnerReference{
-		APIVersion: "operator.openshift.io/v1alpha1",
-		Kind:       "Kueue",
-		Name:       kueue.Name,
-		UID:        kueue.UID,
-	}
-	clusterRoleBinding.OwnerReferences = []metav1.OwnerReference{
-		ownerReference,
-	}
-	controller.EnsureOwnerRef(clusterRoleBinding, ownerReference)
-	return resourceapply.ApplyClusterRoleBinding(c.ctx, c.kubeClient.RbacV1(), c.eventRecorder, clusterRoleBinding)
-}
-
-func (c *TargetConfigReconciler) manageOpenshiftClusterRolesForKueue(kueue *kueuev1alpha1.Kueue) (*rbacv1.ClusterRole, bool, error) {
-	clusterRole := &rbacv1.ClusterRole{
-		ObjectMeta: metav1.ObjectMeta{
-			Labels: map[string]string{
-				"app.kubernetes.io/component": "controller",
-				"app.kubernetes.io/name":      "kueue",
-				"control-plane":               "controller-manager",
-			},
-			Name: "kueue-openshift-roles",
-		},
-		Rules: []rbacv1.PolicyRule{
-			{
-				APIGroups: []string{"config.openshift.io"},
-				Resources: []string{"infrastructures", "apiservers"},
-				Verbs:     []string{"get", "watch", "list"},
-			},
-		},
-	}
-
-	ownerReference := metav1.OwnerReference{
-		APIVersion: "operator.openshift.io/v1alpha1",
-		Kind:       "Kueue",
-		Name:       kueue.Name,
-		UID:        kueue.UID,
-	}
-	clusterRole.OwnerReferences = []metav1.OwnerReference{
-		ownerReference,
-	}
-	controller.EnsureOwnerRef(clusterRole, ownerReference)
-	return resourceapply.ApplyClusterRole(c.ctx, c.kubeClient.RbacV1(), c.eventRecorder, clusterRole)
-}
-
-func (c *TargetConfigReconciler) manageCustomResources(kueue *kueuev1alpha1.Kueue) (map[string]string, error) {
-	returnMap := make(map[string]string, 11)
-	// This is hardcoded due to the amount of custom resources that kueue has.
-	for i := 0; i < 11; i++ {
-		assetPath := fmt.Sprintf("assets/kueue-operator/crd_%d.yml", i)
-		crdName := fmt.Sprintf("crd/crd_%d.yml", i)
-		required := resourceread.ReadCustomResourceDefinitionV1OrDie(bindata.MustAsset(assetPath))
-		ownerReference := metav1.OwnerReference{
-			APIVersion: "operator.openshift.io/v1alpha1",
-			Kind:       "Kueue",
-			Name:       kueue.Name,
-			UID:        kueue.UID,
+	now := metav1.Now()
+	if err := c.updateKueueStatus(kueue.Name, func(status *kueuev1alpha1.KueueStatus) {
+		if result.deployment != nil {
+			resourcemerge.SetDeploymentGeneration(&status.Generations, result.deployment)
 		}
-		required.OwnerReferences = []metav1.OwnerReference{
-			ownerReference,
+		v1helpers.SetOperatorCondition(&status.Conditions, degraded)
+		if configInvalid != nil {
+			v1helpers.SetOperatorCondition(&status.Conditions, *configInvalid)
 		}
-		controller.EnsureOwnerRef(required, ownerReference)
-
-		crd, _, err := resourceapply.ApplyCustomResourceDefinitionV1(c.ctx, c.crdClient, c.eventRecorder, required)
-		if err != nil {
-			return nil, err
+		if pendingRestart != nil {
+			v1helpers.SetOperatorCondition(&status.Conditions, *pendingRestart)
 		}
-
-		resourceVersion := "0"
-		if crd != nil { // SyncConfigMap can return nil
-			resourceVersion = crd.ObjectMeta.ResourceVersion
+		status.Resources = mergeResourceStatuses(status.Resources, result.resources, now)
+		status.RelatedObjects = relatedObjects(kueue)
+		status.Versions = operandVersions(kueue)
+		if history != nil {
+			status.ConfigRevisions = history
 		}
-		returnMap[crdName] = resourceVersion
-	}
-	return returnMap, nil
-}
-
-func (c *TargetConfigReconciler) manageDeployment(kueueoperator *kueuev1alpha1.Kueue, specAnnotations map[string]string) (*appsv1.Deployment, bool, error) {
-	required := resourceread.ReadDeploymentV1OrDie(bindata.MustAsset("assets/kueue-operator/deployment.yaml"))
-	required.Name = operatorclient.OperandName
-	required.Namespace = kueueoperator.Namespace
-	ownerReference := metav1.OwnerReference{
-		APIVersion: "operator.openshift.io/v1alpha1",
-		Kind:       "Kueue",
-		Name:       kueueoperator.Name,
-		UID:        kueueoperator.UID,
-	}
-	required.OwnerReferences = []metav1.OwnerReference{
-		ownerReference,
+	}); err != nil {
+		klog.Error("unable to update kueue status")
+		result.errors = append(result.errors, err)
 	}
-	controller.EnsureOwnerRef(required, ownerReference)
-
-	required.Spec.Template.Spec.Containers[0].Image = kueueoperator.Spec.Image
-	switch kueueoperator.Spec.LogLevel {
-	case operatorv1.Normal:
-		required.Spec.Template.Spec.Containers[0].Args = append(required.Spec.Template.Spec.Containers[0].Args, fmt.Sprintf("--zap-log-level=%d", 2))
-	case operatorv1.Debug:
-		required.Spec.Template.Spec.Containers[0].Args = append(required.Spec.Template.Spec.Containers[0].Args, fmt.Sprintf("--zap-log-level=%d", 4))
-	case operatorv1.Trace:
-		required.Spec.Template.Spec.Containers[0].Args = append(required.Spec.Template.Spec.Containers[0].Args, fmt.Sprintf("--zap-log-level=%d", 6))
-	case operatorv1.TraceAll:
-		required.Spec.Template.Spec.Containers[0].Args = append(required.Spec.Template.Spec.Containers[0].Args, fmt.Sprintf("--zap-log-level=%d", 8))
-	default:
-		required.Spec.Template.Spec.Containers[0].Args = append(required.Spec.Template.Spec.Containers[0].Args, fmt.Sprintf("--zap-log-level=%d", 2))
-	}
-
-	resourcemerge.MergeMap(ptr.To(false), &required.Spec.Template.Annotations, specAnnotations)
 
-	deploy, flag, err := resourceapply.ApplyDeployment(
-		c.ctx,
-		c.kubeClient.AppsV1(),
-		c.eventRecorder,
-		required,
-		resourcemerge.ExpectedDeploymentGeneration(required, kueueoperator.Status.Generations))
-	if err != nil {
-		klog.InfoS("Deployment error", "Deployment", deploy)
-	}
-	return deploy, flag, err
+	return utilerrors.NewAggregate(result.errors)
 }
 
 // Run starts the kube-scheduler and blocks until stopCh is closed.
@@ -667,9 +264,18 @@ func (c *TargetConfigReconciler) Run(workers int, stopCh <-chan struct{}) {
 	klog.Infof("Starting TargetConfigReconciler")
 	defer klog.Infof("Shutting down TargetConfigReconciler")
 
+	if !c.startDriftInformers(stopCh) {
+		klog.Error("Unable to sync caches of operand informers")
+		return
+	}
+
 	// doesn't matter what workers say, only start one.
 	go wait.Until(c.runWorker, time.Second, stopCh)
 
+	// Events only reconcile the affected resources, so everything is reconciled
+	// periodically in case an event was missed.
+	go wait.Until(func() { c.queue.Add(queueItem{kind: "kueue"}) }, fullResyncInterval, stopCh)
+
 	<-stopCh
 }
 
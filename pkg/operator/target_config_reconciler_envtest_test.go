@@ -0,0 +1,179 @@
+//go:build envtest
+
+package operator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	routeclientset "github.com/openshift/client-go/route/clientset/versioned"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	clocktesting "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+
+	operatorclientset "github.com/openshift/kueue-operator/pkg/generated/clientset/versioned"
+	operatorclientinformers "github.com/openshift/kueue-operator/pkg/generated/informers/externalversions"
+	"github.com/openshift/kueue-operator/pkg/namespace"
+	"github.com/openshift/kueue-operator/pkg/operator/operatorclient"
+)
+
+// These tests run the reconciler against a real API server started by envtest.
+// They are built with -tags envtest and need KUBEBUILDER_ASSETS to point at the
+// kube-apiserver and etcd binaries, see `make test-envtest`.
+
+type envtestReconciler struct {
+	*TargetConfigReconciler
+	kubeClient kubernetes.Interface
+	crdClient  apiextensionsclientset.Interface
+}
+
+func newEnvtestReconciler(t *testing.T) *envtestReconciler {
+	t.Helper()
+	if len(os.Getenv("KUBEBUILDER_ASSETS")) == 0 {
+		t.Skip("KUBEBUILDER_ASSETS is not set")
+	}
+
+	env := &envtest.Environment{
+		CRDDirectoryPaths:     []string{filepath.Join("..", "..", "deploy", "crd")},
+		ErrorIfCRDPathMissing: true,
+	}
+	cfg, err := env.Start()
+	if err != nil {
+		t.Fatalf("Unable to start envtest: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := env.Stop(); err != nil {
+			t.Errorf("Unable to stop envtest: %v", err)
+		}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	kubeClient := kubernetes.NewForConfigOrDie(cfg)
+	operatorClient := operatorclientset.NewForConfigOrDie(cfg)
+	crdClient := apiextensionsclientset.NewForConfigOrDie(cfg)
+
+	if _, err := kubeClient.CoreV1().Namespaces().Create(ctx, &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: namespace.GetNamespace()},
+	}, metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := operatorClient.KueueV1alpha1().Kueues(namespace.GetNamespace()).Create(ctx, newTestKueue(), metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	operatorInformers := operatorclientinformers.NewSharedInformerFactory(operatorClient, 0)
+	c, err := NewTargetConfigReconciler(
+		ctx,
+		operatorClient.KueueV1alpha1(),
+		operatorInformers.Kueue().V1alpha1().Kueues(),
+		v1helpers.NewKubeInformersForNamespaces(kubeClient, "", namespace.GetNamespace()),
+		&operatorclient.KueueClient{
+			Ctx:            ctx,
+			SharedInformer: operatorInformers.Kueue().V1alpha1().Kueues().Informer(),
+			OperatorClient: operatorClient.KueueV1alpha1(),
+		},
+		kubeClient,
+		routeclientset.NewForConfigOrDie(cfg),
+		dynamic.NewForConfigOrDie(cfg),
+		crdClient.ApiextensionsV1(),
+		events.NewInMemoryRecorder("envtest", clocktesting.NewFakePassiveClock(time.Now())),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &envtestReconciler{TargetConfigReconciler: c, kubeClient: kubeClient, crdClient: crdClient}
+}
+
+func TestEnvtestCustomResourceDefinitionsEstablished(t *testing.T) {
+	r := newEnvtestReconciler(t)
+	ctx := context.Background()
+
+	if err := r.sync(queueItem{kind: "kueue"}); err != nil {
+		t.Fatalf("Unexpected sync error: %v", err)
+	}
+
+	resourceVersions := map[string]string{}
+	for _, required := range requiredCustomResourceDefinitions(newTestKueue()) {
+		err := wait.PollUntilContextTimeout(ctx, 100*time.Millisecond, 30*time.Second, true, func(ctx context.Context) (bool, error) {
+			crd, err := r.crdClient.ApiextensionsV1().CustomResourceDefinitions().Get(ctx, required.Name, metav1.GetOptions{})
+			if err != nil {
+				return false, err
+			}
+			resourceVersions[crd.Name] = crd.ResourceVersion
+			for _, condition := range crd.Status.Conditions {
+				if condition.Type == apiextensionsv1.Established && condition.Status == apiextensionsv1.ConditionTrue {
+					return true, nil
+				}
+			}
+			return false, nil
+		})
+		if err != nil {
+			t.Errorf("CRD %s was not established: %v", required.Name, err)
+		}
+	}
+
+	if err := r.sync(queueItem{kind: "kueue"}); err != nil {
+		t.Fatalf("Unexpected sync error on second pass: %v", err)
+	}
+	for name, resourceVersion := range resourceVersions {
+		crd, err := r.crdClient.ApiextensionsV1().CustomResourceDefinitions().Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if crd.ResourceVersion != resourceVersion {
+			t.Errorf("CRD %s was rewritten by an unchanged sync", name)
+		}
+	}
+}
+
+func TestEnvtestWebhookCABundlePreserved(t *testing.T) {
+	r := newEnvtestReconciler(t)
+	ctx := context.Background()
+
+	if err := r.sync(queueItem{kind: "kueue"}); err != nil {
+		t.Fatalf("Unexpected sync error: %v", err)
+	}
+
+	// Simulate the service-ca operator injecting the serving CA.
+	caBundle := []byte("injected-ca-bundle")
+	webhook, err := r.kubeClient.AdmissionregistrationV1().MutatingWebhookConfigurations().Get(ctx, "kueue-mutating-webhook-configuration", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range webhook.Webhooks {
+		webhook.Webhooks[i].ClientConfig.CABundle = caBundle
+	}
+	if _, err := r.kubeClient.AdmissionregistrationV1().MutatingWebhookConfigurations().Update(ctx, webhook, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := r.sync(queueItem{kind: "kueue"}); err != nil {
+		t.Fatalf("Unexpected sync error on second pass: %v", err)
+	}
+
+	webhook, err = r.kubeClient.AdmissionregistrationV1().MutatingWebhookConfigurations().Get(ctx, "kueue-mutating-webhook-configuration", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, wh := range webhook.Webhooks {
+		if string(wh.ClientConfig.CABundle) != string(caBundle) {
+			t.Errorf("CA bundle of webhook %s was not preserved", wh.Name)
+		}
+		if wh.ClientConfig.Service.Namespace != namespace.GetNamespace() {
+			t.Errorf("Unexpected service namespace for webhook %s: %s", wh.Name, wh.ClientConfig.Service.Namespace)
+		}
+	}
+}
@@ -0,0 +1,865 @@
+package operator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	routefake "github.com/openshift/client-go/route/clientset/versioned/fake"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsv1ac "k8s.io/apiextensions-apiserver/pkg/client/applyconfiguration/apiextensions/v1"
+	apiextensionsfake "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/util/managedfields"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	appsv1ac "k8s.io/client-go/applyconfigurations/apps/v1"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+	clocktesting "k8s.io/utils/clock/testing"
+	"k8s.io/utils/ptr"
+
+	kueuev1alpha1 "github.com/openshift/kueue-operator/pkg/apis/kueueoperator/v1alpha1"
+	operatorfake "github.com/openshift/kueue-operator/pkg/generated/clientset/versioned/fake"
+	operatorclientinformers "github.com/openshift/kueue-operator/pkg/generated/informers/externalversions"
+	"github.com/openshift/kueue-operator/pkg/namespace"
+	"github.com/openshift/kueue-operator/pkg/operator/operatorclient"
+	configapi "sigs.k8s.io/kueue/apis/config/v1beta1"
+)
+
+// testReconciler wraps a TargetConfigReconciler built on fake clients so that tests can
+// drive sync and inspect everything it applied.
+type testReconciler struct {
+	*TargetConfigReconciler
+	kubeClient     *kubefake.Clientset
+	operatorClient *operatorfake.Clientset
+	crdClient      *apiextensionsfake.Clientset
+	dynamicClient  *dynamicfake.FakeDynamicClient
+}
+
+func newTestKueue() *kueuev1alpha1.Kueue {
+	return &kueuev1alpha1.Kueue{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       operatorclient.OperatorConfigName,
+			Namespace:  namespace.GetNamespace(),
+			UID:        "kueue-uid",
+			Generation: 3,
+		},
+		Spec: kueuev1alpha1.KueueOperandSpec{
+			OperatorSpec: operatorv1.OperatorSpec{
+				ManagementState: operatorv1.Managed,
+				LogLevel:        operatorv1.Debug,
+			},
+			Image: "registry.k8s.io/kueue/kueue:v0.10.0",
+			Config: kueuev1alpha1.KueueConfiguration{
+				Integrations: configapi.Integrations{
+					Frameworks: []string{"batch/job"},
+				},
+			},
+		},
+	}
+}
+
+func newTestReconciler(t *testing.T, kueue *kueuev1alpha1.Kueue, kubeObjects ...runtime.Object) *testReconciler {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	// NewClientset tracks managed fields, so server-side apply behaves like on a real API server.
+	kubeClient := kubefake.NewClientset(kubeObjects...)
+	operatorClient := operatorfake.NewSimpleClientset(kueue)
+	crdClient := newCRDClientset()
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+
+	operatorInformers := operatorclientinformers.NewSharedInformerFactory(operatorClient, 0)
+	kueueClient := &operatorclient.KueueClient{
+		Ctx:            ctx,
+		SharedInformer: operatorInformers.Kueue().V1alpha1().Kueues().Informer(),
+		OperatorClient: operatorClient.KueueV1alpha1(),
+	}
+
+	c, err := NewTargetConfigReconciler(
+		ctx,
+		operatorClient.KueueV1alpha1(),
+		operatorInformers.Kueue().V1alpha1().Kueues(),
+		v1helpers.NewKubeInformersForNamespaces(kubeClient, "", namespace.GetNamespace()),
+		kueueClient,
+		kubeClient,
+		routefake.NewSimpleClientset(),
+		dynamicClient,
+		crdClient.ApiextensionsV1(),
+		events.NewInMemoryRecorder("test", clocktesting.NewFakePassiveClock(metav1.Now().Time)),
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error creating reconciler: %v", err)
+	}
+
+	return &testReconciler{
+		TargetConfigReconciler: c,
+		kubeClient:             kubeClient,
+		operatorClient:         operatorClient,
+		crdClient:              crdClient,
+		dynamicClient:          dynamicClient,
+	}
+}
+
+// newCRDClientset returns a fake apiextensions clientset that supports server-side apply.
+// The type converter of apiextensionsfake.NewClientset has no schema for CRDs, so the
+// tracker deduces the structure of applied objects instead.
+func newCRDClientset() *apiextensionsfake.Clientset {
+	crdScheme := runtime.NewScheme()
+	utilruntime.Must(apiextensionsv1.AddToScheme(crdScheme))
+	tracker := clienttesting.NewFieldManagedObjectTracker(
+		crdScheme,
+		serializer.NewCodecFactory(crdScheme).UniversalDecoder(),
+		managedfields.NewDeducedTypeConverter(),
+	)
+	crdClient := &apiextensionsfake.Clientset{}
+	crdClient.AddReactor("*", "*", clienttesting.ObjectReaction(tracker))
+	return crdClient
+}
+
+// appliedObjects returns "resource/namespace/name" for every object present in the fake clients.
+func (r *testReconciler) appliedObjects(t *testing.T) []string {
+	t.Helper()
+	ctx := context.Background()
+	var applied []string
+	add := func(resource string, list []metav1.Object) {
+		for _, obj := range list {
+			applied = append(applied, resource+"/"+obj.GetNamespace()+"/"+obj.GetName())
+		}
+	}
+
+	configMaps, err := r.kubeClient.CoreV1().ConfigMaps("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	add("configmaps", objectsOf(configMaps.Items))
+	serviceAccounts, err := r.kubeClient.CoreV1().ServiceAccounts("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	add("serviceaccounts", objectsOf(serviceAccounts.Items))
+	secrets, err := r.kubeClient.CoreV1().Secrets("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	add("secrets", objectsOf(secrets.Items))
+	services, err := r.kubeClient.CoreV1().Services("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	add("services", objectsOf(services.Items))
+	roles, err := r.kubeClient.RbacV1().Roles("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	add("roles", objectsOf(roles.Items))
+	roleBindings, err := r.kubeClient.RbacV1().RoleBindings("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	add("rolebindings", objectsOf(roleBindings.Items))
+	clusterRoleBindings, err := r.kubeClient.RbacV1().ClusterRoleBindings().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	add("clusterrolebindings", objectsOf(clusterRoleBindings.Items))
+	deployments, err := r.kubeClient.AppsV1().Deployments("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	add("deployments", objectsOf(deployments.Items))
+	mutatingWebhooks, err := r.kubeClient.AdmissionregistrationV1().MutatingWebhookConfigurations().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	add("mutatingwebhookconfigurations", objectsOf(mutatingWebhooks.Items))
+	validatingWebhooks, err := r.kubeClient.AdmissionregistrationV1().ValidatingWebhookConfigurations().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	add("validatingwebhookconfigurations", objectsOf(validatingWebhooks.Items))
+
+	sort.Strings(applied)
+	return applied
+}
+
+func objectsOf[T any, PT interface {
+	*T
+	metav1.Object
+}](items []T) []metav1.Object {
+	objects := make([]metav1.Object, 0, len(items))
+	for i := range items {
+		objects = append(objects, PT(&items[i]))
+	}
+	return objects
+}
+
+func TestSyncAppliesOperandObjects(t *testing.T) {
+	ns := namespace.GetNamespace()
+	kueue := newTestKueue()
+	r := newTestReconciler(t, kueue)
+
+	if err := r.sync(queueItem{kind: "kueue"}); err != nil {
+		t.Fatalf("Unexpected sync error: %v", err)
+	}
+
+	wantObjects := []string{
+		"clusterrolebindings//kueue-manager-role-binding",
+		"clusterrolebindings//kueue-openshift-cluster-role-binding",
+		"clusterrolebindings//kueue-proxy-rolebinding",
+		"configmaps/" + ns + "/kueue-manager-config",
+		"configmaps/" + ns + "/kueue-manager-config-1",
+		"deployments/" + ns + "/kueue",
+		"mutatingwebhookconfigurations//kueue-mutating-webhook-configuration",
+		"rolebindings/" + ns + "/kueue-leader-election-rolebinding",
+		"roles/" + ns + "/kueue-leader-election-role",
+		"secrets/" + ns + "/kueue-webhook-server-cert",
+		"serviceaccounts/" + ns + "/kueue-controller-manager",
+		"services/" + ns + "/kueue-controller-manager-metrics-service",
+		"services/" + ns + "/kueue-visibility-server",
+		"services/" + ns + "/kueue-webhook-service",
+		"validatingwebhookconfigurations//kueue-validating-webhook-configuration",
+	}
+	if diff := cmp.Diff(wantObjects, r.appliedObjects(t)); len(diff) != 0 {
+		t.Errorf("Unexpected applied objects (-want,+got):\n%s", diff)
+	}
+
+	ctx := context.Background()
+	clusterRoles, err := r.kubeClient.RbacV1().ClusterRoles().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := len(requiredClusterRoles(kueue)) + 1; len(clusterRoles.Items) != want {
+		t.Errorf("Unexpected number of cluster roles: want=%d, got=%d", want, len(clusterRoles.Items))
+	}
+	crds, err := r.crdClient.ApiextensionsV1().CustomResourceDefinitions().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := len(requiredCustomResourceDefinitions(kueue)); len(crds.Items) != want {
+		t.Errorf("Unexpected number of CRDs: want=%d, got=%d", want, len(crds.Items))
+	}
+}
+
+func TestSyncSetsOwnerReferences(t *testing.T) {
+	kueue := newTestKueue()
+	r := newTestReconciler(t, kueue)
+
+	if err := r.sync(queueItem{kind: "kueue"}); err != nil {
+		t.Fatalf("Unexpected sync error: %v", err)
+	}
+
+	ctx := context.Background()
+	var owned []metav1.Object
+	deployments, err := r.kubeClient.AppsV1().Deployments("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	owned = append(owned, objectsOf(deployments.Items)...)
+	clusterRoles, err := r.kubeClient.RbacV1().ClusterRoles().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	owned = append(owned, objectsOf(clusterRoles.Items)...)
+	crds, err := r.crdClient.ApiextensionsV1().CustomResourceDefinitions().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	owned = append(owned, objectsOf(crds.Items)...)
+	webhooks, err := r.kubeClient.AdmissionregistrationV1().ValidatingWebhookConfigurations().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	owned = append(owned, objectsOf(webhooks.Items)...)
+
+	wantOwners := []metav1.OwnerReference{{
+		APIVersion: "operator.openshift.io/v1alpha1",
+		Kind:       "Kueue",
+		Name:       kueue.Name,
+		UID:        kueue.UID,
+	}}
+	for _, obj := range owned {
+		if diff := cmp.Diff(wantOwners, obj.GetOwnerReferences()); len(diff) != 0 {
+			t.Errorf("Unexpected owner references on %s (-want,+got):\n%s", obj.GetName(), diff)
+		}
+	}
+}
+
+func TestSyncRewritesNamespaces(t *testing.T) {
+	ns := namespace.GetNamespace()
+	kueue := newTestKueue()
+	r := newTestReconciler(t, kueue)
+
+	if err := r.sync(queueItem{kind: "kueue"}); err != nil {
+		t.Fatalf("Unexpected sync error: %v", err)
+	}
+
+	ctx := context.Background()
+	mutating, err := r.kubeClient.AdmissionregistrationV1().MutatingWebhookConfigurations().Get(ctx, "kueue-mutating-webhook-configuration", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, webhook := range mutating.Webhooks {
+		if got := webhook.ClientConfig.Service.Namespace; got != ns {
+			t.Errorf("Unexpected service namespace for mutating webhook %s: want=%s, got=%s", webhook.Name, ns, got)
+		}
+	}
+	validating, err := r.kubeClient.AdmissionregistrationV1().ValidatingWebhookConfigurations().Get(ctx, "kueue-validating-webhook-configuration", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, webhook := range validating.Webhooks {
+		if got := webhook.ClientConfig.Service.Namespace; got != ns {
+			t.Errorf("Unexpected service namespace for validating webhook %s: want=%s, got=%s", webhook.Name, ns, got)
+		}
+	}
+
+	roleBinding, err := r.kubeClient.RbacV1().RoleBindings(ns).Get(ctx, "kueue-leader-election-rolebinding", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	clusterRoleBinding, err := r.kubeClient.RbacV1().ClusterRoleBindings().Get(ctx, "kueue-manager-role-binding", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, subject := range append(roleBinding.Subjects, clusterRoleBinding.Subjects...) {
+		if subject.Namespace != ns {
+			t.Errorf("Unexpected subject namespace for %s: want=%s, got=%s", subject.Name, ns, subject.Namespace)
+		}
+	}
+}
+
+func TestSyncDeploymentSpec(t *testing.T) {
+	ns := namespace.GetNamespace()
+	kueue := newTestKueue()
+	r := newTestReconciler(t, kueue)
+
+	if err := r.sync(queueItem{kind: "kueue"}); err != nil {
+		t.Fatalf("Unexpected sync error: %v", err)
+	}
+
+	deployment, err := r.kubeClient.AppsV1().Deployments(ns).Get(context.Background(), operatorclient.OperandName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	container := deployment.Spec.Template.Spec.Containers[0]
+	if container.Image != kueue.Spec.Image {
+		t.Errorf("Unexpected image: want=%s, got=%s", kueue.Spec.Image, container.Image)
+	}
+	if diff := cmp.Diff([]string{"--config=/controller_manager_config.yaml", "--zap-log-level=4"}, container.Args); len(diff) != 0 {
+		t.Errorf("Unexpected args (-want,+got):\n%s", diff)
+	}
+
+	if got := deployment.Annotations["kueueoperator.operator.openshift.io/cluster"]; got != "3" {
+		t.Errorf("Unexpected generation annotation: want=3, got=%s", got)
+	}
+	annotations := deployment.Spec.Template.Annotations
+	if _, ok := annotations["kueueoperator.operator.openshift.io/cluster"]; ok {
+		t.Errorf("Unexpected generation annotation on the pod template")
+	}
+	if got, want := annotations["kueue/configmap"], r.appliedConfigHash(t); got != want {
+		t.Errorf("Unexpected configmap annotation: want=%s, got=%s", want, got)
+	}
+	for _, key := range []string{
+		"kueue/configmap",
+		"serviceaccounts/kueue-operator",
+		"secret/kueue-webhook-server-cert",
+		"role/leader-election",
+		"rolebindings/leader-election",
+		"service/metrics-service",
+		"service/visibility-service",
+		"service/webhook-service",
+		"clusterrole/openshift-roles",
+		"clusterrolebinding/openshift-roles",
+		"clusterrolebinding/kube-proxy",
+		"clusterrolebinding/kueue-manager-role",
+		"crd/crd_0.yml",
+	} {
+		if _, ok := annotations[key]; !ok {
+			t.Errorf("Missing pod template annotation %q", key)
+		}
+	}
+	for fileName := range requiredClusterRoles(kueue) {
+		if _, ok := annotations["clusterrole/"+fileName]; !ok {
+			t.Errorf("Missing pod template annotation %q", "clusterrole/"+fileName)
+		}
+	}
+}
+
+func TestSyncUpdatesStatus(t *testing.T) {
+	ns := namespace.GetNamespace()
+	kueue := newTestKueue()
+	r := newTestReconciler(t, kueue)
+
+	if err := r.sync(queueItem{kind: "kueue"}); err != nil {
+		t.Fatalf("Unexpected sync error: %v", err)
+	}
+
+	updated, err := r.operatorClient.KueueV1alpha1().Kueues(ns).Get(context.Background(), kueue.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(relatedObjects(kueue), updated.Status.RelatedObjects); len(diff) != 0 {
+		t.Errorf("Unexpected related objects (-want,+got):\n%s", diff)
+	}
+	if len(updated.Status.Generations) != 1 || updated.Status.Generations[0].Resource != "deployments" {
+		t.Errorf("Unexpected generations: %v", updated.Status.Generations)
+	}
+}
+
+func TestRelatedObjectsMatchAppliedObjects(t *testing.T) {
+	ctx := context.Background()
+	kueue := newTestKueue()
+	kueue.Spec.Canary = newTestCanary()
+	r := newTestReconciler(t, kueue)
+	if err := r.sync(queueItem{kind: "kueue"}); err != nil {
+		t.Fatalf("Unexpected sync error: %v", err)
+	}
+
+	// Every object the pipeline applied, without the recorded config revisions.
+	var want []string
+	for _, applied := range r.appliedObjects(t) {
+		if !strings.HasPrefix(applied, "configmaps/"+kueue.Namespace+"/"+KueueConfigMap+"-") || strings.HasSuffix(applied, "-canary") {
+			want = append(want, applied)
+		}
+	}
+	clusterRoles, err := r.kubeClient.RbacV1().ClusterRoles().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, clusterRole := range clusterRoles.Items {
+		want = append(want, "clusterroles//"+clusterRole.Name)
+	}
+	crds, err := r.crdClient.ApiextensionsV1().CustomResourceDefinitions().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, crd := range crds.Items {
+		want = append(want, "customresourcedefinitions//"+crd.Name)
+	}
+	sort.Strings(want)
+
+	var got []string
+	for _, related := range relatedObjects(kueue) {
+		switch {
+		case related.Resource == "kueues" || related.Resource == "namespaces":
+		case related.Resource == "secrets" && related.Name == canaryWebhookCertName:
+			// Written by the service-ca operator.
+		default:
+			if group := pipelineResources[related.Resource].group; related.Group != group {
+				t.Errorf("Unexpected group %q of %s %s, want %q", related.Group, related.Resource, related.Name, group)
+			}
+			got = append(got, related.Resource+"/"+related.Namespace+"/"+related.Name)
+		}
+	}
+	sort.Strings(got)
+	if diff := cmp.Diff(want, got); len(diff) != 0 {
+		t.Errorf("Unexpected related objects (-applied,+related):\n%s", diff)
+	}
+}
+
+func TestSyncKeepsConfigWhenInvalid(t *testing.T) {
+	ns := namespace.GetNamespace()
+	r := newTestReconciler(t, newTestKueue())
+	if err := r.sync(queueItem{kind: "kueue"}); err != nil {
+		t.Fatalf("Unexpected sync error: %v", err)
+	}
+	applied := r.appliedConfigHash(t)
+
+	err := r.updateConfig(t, func(config *kueuev1alpha1.KueueConfiguration) {
+		config.Integrations.Frameworks = append(config.Integrations.Frameworks, "example.com/notebook")
+	})
+	if err == nil || !strings.Contains(err.Error(), "invalid Kueue configuration") {
+		t.Fatalf("Expected an error about the invalid configuration, got %v", err)
+	}
+	if got := r.appliedConfigHash(t); got != applied {
+		t.Errorf("Expected the running configuration to be kept")
+	}
+	updated, err := r.operatorClient.KueueV1alpha1().Kueues(ns).Get(context.Background(), operatorclient.OperatorConfigName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	condition := v1helpers.FindOperatorCondition(updated.Status.Conditions, "ConfigInvalid")
+	if condition == nil || condition.Status != operatorv1.ConditionTrue || !strings.Contains(condition.Message, "example.com/notebook") {
+		t.Errorf("Unexpected ConfigInvalid condition %+v", condition)
+	}
+	for _, resource := range updated.Status.Resources {
+		if resource.Name == "deployment" && resource.State != kueuev1alpha1.ResourceStateSkipped {
+			t.Errorf("Expected the deployment to be skipped, got %s", resource.State)
+		}
+	}
+
+	// A fixed configuration clears the condition.
+	if err := r.updateConfig(t, func(config *kueuev1alpha1.KueueConfiguration) {
+		config.Integrations.Frameworks = []string{"batch/job"}
+	}); err != nil {
+		t.Fatalf("Unexpected sync error: %v", err)
+	}
+	updated, err = r.operatorClient.KueueV1alpha1().Kueues(ns).Get(context.Background(), operatorclient.OperatorConfigName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if condition := v1helpers.FindOperatorCondition(updated.Status.Conditions, "ConfigInvalid"); condition == nil || condition.Status != operatorv1.ConditionFalse {
+		t.Errorf("Unexpected ConfigInvalid condition %+v", condition)
+	}
+}
+
+func TestSyncIsIdempotent(t *testing.T) {
+	ns := namespace.GetNamespace()
+	kueue := newTestKueue()
+	r := newTestReconciler(t, kueue)
+
+	if err := r.sync(queueItem{kind: "kueue"}); err != nil {
+		t.Fatalf("Unexpected sync error: %v", err)
+	}
+	before := r.appliedObjects(t)
+	deployment, err := r.kubeClient.AppsV1().Deployments(ns).Get(context.Background(), operatorclient.OperandName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := r.sync(queueItem{kind: "kueue"}); err != nil {
+		t.Fatalf("Unexpected sync error on second pass: %v", err)
+	}
+	if diff := cmp.Diff(before, r.appliedObjects(t)); len(diff) != 0 {
+		t.Errorf("Unexpected objects after second sync (-want,+got):\n%s", diff)
+	}
+	// The pod template tracks the resourceVersions of every applied object, so an
+	// unchanged template means nothing was rewritten and the operand is not rolled out.
+	updated, err := r.kubeClient.AppsV1().Deployments(ns).Get(context.Background(), operatorclient.OperandName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.ResourceVersion != deployment.ResourceVersion {
+		t.Errorf("Deployment was rewritten by an unchanged sync:\n%s", cmp.Diff(deployment.Spec.Template.Annotations, updated.Spec.Template.Annotations))
+	}
+}
+
+func TestSyncForcesConflictingFields(t *testing.T) {
+	ns := namespace.GetNamespace()
+	ctx := context.Background()
+	kueue := newTestKueue()
+	r := newTestReconciler(t, kueue)
+
+	if err := r.sync(queueItem{kind: "kueue"}); err != nil {
+		t.Fatalf("Unexpected sync error: %v", err)
+	}
+
+	// Simulate an `oc edit` of a field the operator owns and of one it does not set.
+	deployment, err := r.kubeClient.AppsV1().Deployments(ns).Get(ctx, operatorclient.OperandName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	deployment.Spec.Replicas = ptr.To[int32](3)
+	deployment.Spec.MinReadySeconds = 30
+	if _, err := r.kubeClient.AppsV1().Deployments(ns).Update(ctx, deployment, metav1.UpdateOptions{FieldManager: "kubectl-edit"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := r.sync(queueItem{kind: "deployments", name: operatorclient.OperandName}); err != nil {
+		t.Fatalf("Unexpected sync error: %v", err)
+	}
+	deployment, err = r.kubeClient.AppsV1().Deployments(ns).Get(ctx, operatorclient.OperandName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *deployment.Spec.Replicas != 1 {
+		t.Errorf("Expected replicas to be reverted to 1, got %d", *deployment.Spec.Replicas)
+	}
+	if deployment.Spec.MinReadySeconds != 30 {
+		t.Errorf("Expected a field the operator does not own to be kept, got minReadySeconds %d", deployment.Spec.MinReadySeconds)
+	}
+
+	updated, err := r.operatorClient.KueueV1alpha1().Kueues(ns).Get(ctx, kueue.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, resource := range updated.Status.Resources {
+		if resource.Name != "deployment" {
+			continue
+		}
+		if resource.State != kueuev1alpha1.ResourceStateApplied || !strings.Contains(resource.Message, "kubectl-edit") {
+			t.Errorf("Expected the conflict with kubectl-edit to be reported, got %+v", resource)
+		}
+	}
+}
+
+func TestApplyConfigurationOmitsUnsetFields(t *testing.T) {
+	deployment := requiredDeployment(newTestKueue(), map[string]string{})
+	deployment.Spec.Template.Spec.Volumes = append(deployment.Spec.Template.Spec.Volumes, corev1.Volume{
+		Name:         "tmp",
+		VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+	})
+	config, err := applyConfiguration[appsv1ac.DeploymentApplyConfiguration](deployment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	patch := map[string]interface{}{}
+	if err := json.Unmarshal(data, &patch); err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range [][]string{
+		{"status"},
+		{"metadata", "creationTimestamp"},
+		{"spec", "template", "metadata", "creationTimestamp"},
+	} {
+		if _, found, _ := unstructured.NestedFieldNoCopy(patch, path...); found {
+			t.Errorf("Expected %s not to be in the apply patch", strings.Join(path, "."))
+		}
+	}
+	if !strings.Contains(string(data), `"emptyDir":{}`) {
+		t.Errorf("Expected the empty emptyDir volume source to be kept: %s", data)
+	}
+
+	for _, crd := range requiredCustomResourceDefinitions(newTestKueue()) {
+		if crd.Spec.Versions[0].Subresources == nil || crd.Spec.Versions[0].Subresources.Status == nil {
+			continue
+		}
+		config, err := applyConfiguration[apiextensionsv1ac.CustomResourceDefinitionApplyConfiguration](crd)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := json.Marshal(config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(data), `"subresources":{"status":{}}`) {
+			t.Errorf("Expected the status subresource of %s to be kept", crd.Name)
+		}
+		break
+	}
+}
+
+func TestSyncContinuesPastFailedResources(t *testing.T) {
+	ns := namespace.GetNamespace()
+	kueue := newTestKueue()
+	r := newTestReconciler(t, kueue)
+	r.kubeClient.PrependReactor("patch", "clusterroles", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		return true, nil, fmt.Errorf("injected failure")
+	})
+
+	if err := r.sync(queueItem{kind: "kueue"}); err == nil {
+		t.Fatal("Expected sync to fail")
+	}
+
+	// Steps that do not depend on the cluster roles are still applied.
+	ctx := context.Background()
+	if _, err := r.kubeClient.AdmissionregistrationV1().MutatingWebhookConfigurations().Get(ctx, "kueue-mutating-webhook-configuration", metav1.GetOptions{}); err != nil {
+		t.Errorf("Expected mutating webhook to be applied: %v", err)
+	}
+	if _, err := r.kubeClient.CoreV1().Services(ns).Get(ctx, "kueue-webhook-service", metav1.GetOptions{}); err != nil {
+		t.Errorf("Expected webhook service to be applied: %v", err)
+	}
+	if _, err := r.kubeClient.AppsV1().Deployments(ns).Get(ctx, operatorclient.OperandName, metav1.GetOptions{}); err == nil {
+		t.Error("Expected deployment to wait for the cluster roles")
+	}
+
+	updated, err := r.operatorClient.KueueV1alpha1().Kueues(ns).Get(ctx, kueue.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	states := map[string]kueuev1alpha1.ResourceState{}
+	for _, resource := range updated.Status.Resources {
+		states[resource.Name] = resource.State
+	}
+	for name, want := range map[string]kueuev1alpha1.ResourceState{
+		"configmap":                     kueuev1alpha1.ResourceStateApplied,
+		"webhook-service":               kueuev1alpha1.ResourceStateApplied,
+		"clusterroles":                  kueuev1alpha1.ResourceStateFailed,
+		"kube-proxy-clusterrolebinding": kueuev1alpha1.ResourceStateSkipped,
+		"deployment":                    kueuev1alpha1.ResourceStateSkipped,
+		"mutating-webhook":              kueuev1alpha1.ResourceStateApplied,
+		"validating-webhook":            kueuev1alpha1.ResourceStateApplied,
+	} {
+		if states[name] != want {
+			t.Errorf("Unexpected state of %s: got %q, want %q", name, states[name], want)
+		}
+	}
+	if !v1helpers.IsOperatorConditionTrue(updated.Status.Conditions, "TargetConfigControllerDegraded") {
+		t.Errorf("Expected TargetConfigControllerDegraded condition, got %v", updated.Status.Conditions)
+	}
+}
+
+func TestSyncTargetedReconcile(t *testing.T) {
+	ns := namespace.GetNamespace()
+	ctx := context.Background()
+	r := newTestReconciler(t, newTestKueue())
+
+	if err := r.sync(queueItem{kind: "kueue"}); err != nil {
+		t.Fatalf("Unexpected sync error: %v", err)
+	}
+	deployment, err := r.kubeClient.AppsV1().Deployments(ns).Get(ctx, operatorclient.OperandName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	annotations := deployment.Spec.Template.Annotations
+
+	if err := r.kubeClient.CoreV1().Services(ns).Delete(ctx, "kueue-controller-manager-metrics-service", metav1.DeleteOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	r.kubeClient.ClearActions()
+	r.crdClient.ClearActions()
+
+	if err := r.sync(queueItem{kind: "services", name: "kueue-controller-manager-metrics-service"}); err != nil {
+		t.Fatalf("Unexpected sync error: %v", err)
+	}
+	service, err := r.kubeClient.CoreV1().Services(ns).Get(ctx, "kueue-controller-manager-metrics-service", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Expected metrics service to be recreated: %v", err)
+	}
+
+	touched := sets.New[string]()
+	for _, action := range r.kubeClient.Actions() {
+		touched.Insert(action.GetResource().Resource)
+	}
+	if want := sets.New("services", "deployments"); !touched.Equal(want) {
+		t.Errorf("Unexpected resources touched by targeted reconcile: %v", sets.List(touched))
+	}
+	if len(r.crdClient.Actions()) != 0 {
+		t.Errorf("Unexpected CRD actions: %v", r.crdClient.Actions())
+	}
+
+	deployment, err = r.kubeClient.AppsV1().Deployments(ns).Get(ctx, operatorclient.OperandName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for key, value := range annotations {
+		if key == "service/metrics-service" {
+			value = service.ResourceVersion
+		}
+		if deployment.Spec.Template.Annotations[key] != value {
+			t.Errorf("Unexpected annotation %s: got %q, want %q", key, deployment.Spec.Template.Annotations[key], value)
+		}
+	}
+}
+
+// podTemplateAnnotations returns the pod template annotations of the operand deployment.
+func (r *testReconciler) podTemplateAnnotations(t *testing.T) map[string]string {
+	t.Helper()
+	deployment, err := r.kubeClient.AppsV1().Deployments(namespace.GetNamespace()).Get(context.Background(), operatorclient.OperandName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return deployment.Spec.Template.Annotations
+}
+
+func TestPipelineStepRoutes(t *testing.T) {
+	routed := sets.New[string]()
+	for _, step := range pipelineStepRoutes() {
+		routed.Insert(step)
+	}
+	for _, step := range resourcePipeline() {
+		if !routed.Has(step.name) {
+			t.Errorf("No object is routed to step %s", step.name)
+		}
+	}
+	if got := pipelineStepRoutes()[stepRoute{kind: "configmaps", name: KueueConfigMap}]; got != "configmap" {
+		t.Errorf("Unexpected step for the Kueue configuration: %q", got)
+	}
+}
+
+func TestSyncRestartsOnContentChange(t *testing.T) {
+	ns := namespace.GetNamespace()
+	ctx := context.Background()
+	r := newTestReconciler(t, newTestKueue())
+	if err := r.sync(queueItem{kind: "kueue"}); err != nil {
+		t.Fatalf("Unexpected sync error: %v", err)
+	}
+	initial := r.podTemplateAnnotations(t)
+
+	// Changes of the metadata and of the Kueue that do not change the configuration
+	// keep the pods running.
+	cfgMap, err := r.kubeClient.CoreV1().ConfigMaps(ns).Get(ctx, KueueConfigMap, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfgMap.Labels["example.com/team"] = "batch"
+	if _, err := r.kubeClient.CoreV1().ConfigMaps(ns).Update(ctx, cfgMap, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.sync(queueItem{kind: "configmaps", name: KueueConfigMap}); err != nil {
+		t.Fatalf("Unexpected sync error: %v", err)
+	}
+	if err := r.updateConfig(t, func(config *kueuev1alpha1.KueueConfiguration) {
+		config.RevisionHistoryLimit = ptr.To[int32](3)
+	}); err != nil {
+		t.Fatalf("Unexpected sync error: %v", err)
+	}
+	if diff := cmp.Diff(initial, r.podTemplateAnnotations(t)); len(diff) != 0 {
+		t.Errorf("Unexpected pod template change (-want,+got):\n%s", diff)
+	}
+
+	if err := r.updateConfig(t, func(config *kueuev1alpha1.KueueConfiguration) {
+		config.WaitForPodsReady = &configapi.WaitForPodsReady{Enable: true}
+	}); err != nil {
+		t.Fatalf("Unexpected sync error: %v", err)
+	}
+	if got := r.podTemplateAnnotations(t)["kueue/configmap"]; got == initial["kueue/configmap"] {
+		t.Errorf("Expected the configmap annotation to change on a configuration change")
+	}
+}
+
+func TestSyncDefersRestartOnNextRestartPolicy(t *testing.T) {
+	ns := namespace.GetNamespace()
+	r := newTestReconciler(t, newTestKueue())
+	if err := r.updateConfig(t, func(config *kueuev1alpha1.KueueConfiguration) {
+		config.ChangePolicy = kueuev1alpha1.ConfigChangePolicyOnNextRestart
+	}); err != nil {
+		t.Fatalf("Unexpected sync error: %v", err)
+	}
+	initial := r.podTemplateAnnotations(t)
+	if got, want := initial["kueue/configmap"], r.appliedConfigHash(t); got != want {
+		t.Errorf("Expected the first rollout to track the configuration: want=%s, got=%s", want, got)
+	}
+
+	if err := r.updateConfig(t, func(config *kueuev1alpha1.KueueConfiguration) {
+		config.WaitForPodsReady = &configapi.WaitForPodsReady{Enable: true}
+	}); err != nil {
+		t.Fatalf("Unexpected sync error: %v", err)
+	}
+	if got := r.podTemplateAnnotations(t)["kueue/configmap"]; got != initial["kueue/configmap"] {
+		t.Errorf("Expected the configmap annotation to be kept, got %s", got)
+	}
+	kueue, err := r.operatorClient.KueueV1alpha1().Kueues(ns).Get(context.Background(), operatorclient.OperatorConfigName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !v1helpers.IsOperatorConditionTrue(kueue.Status.Conditions, "ConfigPendingRestart") {
+		t.Errorf("Expected ConfigPendingRestart to be true, got %v", kueue.Status.Conditions)
+	}
+
+	if err := r.updateConfig(t, func(config *kueuev1alpha1.KueueConfiguration) {
+		config.ChangePolicy = kueuev1alpha1.ConfigChangePolicyRestart
+	}); err != nil {
+		t.Fatalf("Unexpected sync error: %v", err)
+	}
+	if got, want := r.podTemplateAnnotations(t)["kueue/configmap"], r.appliedConfigHash(t); got != want {
+		t.Errorf("Expected the pending change to be rolled out: want=%s, got=%s", want, got)
+	}
+	kueue, err = r.operatorClient.KueueV1alpha1().Kueues(ns).Get(context.Background(), operatorclient.OperatorConfigName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !v1helpers.IsOperatorConditionFalse(kueue.Status.Conditions, "ConfigPendingRestart") {
+		t.Errorf("Expected ConfigPendingRestart to be false, got %v", kueue.Status.Conditions)
+	}
+}
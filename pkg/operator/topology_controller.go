@@ -0,0 +1,270 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	kueuev1alpha1 "github.com/openshift/kueue-operator/pkg/apis/kueueoperator/v1alpha1"
+	"github.com/openshift/kueue-operator/pkg/builders/queues"
+	kueueconfigclient "github.com/openshift/kueue-operator/pkg/generated/clientset/versioned/typed/kueueoperator/v1alpha1"
+	operatorclientinformers "github.com/openshift/kueue-operator/pkg/generated/informers/externalversions/kueueoperator/v1alpha1"
+	"github.com/openshift/kueue-operator/pkg/namespace"
+	"github.com/openshift/kueue-operator/pkg/operator/operatorclient"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/dynamic"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/klog/v2"
+	kueuev1beta1 "sigs.k8s.io/kueue/apis/kueue/v1beta1"
+)
+
+const (
+	// topologyLabel marks the Topologies created for spec.topologyAwareScheduling. Only
+	// these are recreated and deleted by the controller.
+	topologyLabel = "kueue.openshift.io/topology"
+
+	// topologyAwareSchedulingFeatureGate enables topology-aware scheduling in Kueue.
+	topologyAwareSchedulingFeatureGate = "TopologyAwareScheduling"
+
+	topologyResyncInterval = 10 * time.Minute
+)
+
+// defaultTopologyLevels matches the default of spec.topologyAwareScheduling.topologies[].levels.
+var defaultTopologyLevels = []string{corev1.LabelTopologyRegion, corev1.LabelTopologyZone, corev1.LabelHostname}
+
+// TopologyController keeps a Topology for every entry of spec.topologyAwareScheduling
+// of the Kueue CR and sets the topologyName of the ResourceFlavors listed with it. The
+// TopologyAwareScheduling feature gate is rendered by the TargetConfigReconciler.
+type TopologyController struct {
+	ctx               context.Context
+	operatorClient    kueueconfigclient.KueueV1alpha1Interface
+	dynamicClient     dynamic.Interface
+	nodeLister        corev1listers.NodeLister
+	eventRecorder     events.Recorder
+	operatorNamespace string
+}
+
+func NewTopologyController(
+	ctx context.Context,
+	operatorConfigClient kueueconfigclient.KueueV1alpha1Interface,
+	operatorClientInformer operatorclientinformers.KueueInformer,
+	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
+	dynamicClient dynamic.Interface,
+	eventRecorder events.Recorder,
+) (factory.Controller, error) {
+	nodeInformer := kubeInformersForNamespaces.InformersFor("").Core().V1().Nodes()
+	c := &TopologyController{
+		ctx:               ctx,
+		operatorClient:    operatorConfigClient,
+		dynamicClient:     dynamicClient,
+		nodeLister:        nodeInformer.Lister(),
+		eventRecorder:     eventRecorder.WithComponentSuffix("topology-controller"),
+		operatorNamespace: namespace.GetNamespace(),
+	}
+
+	syncCtx := factory.NewSyncContext("TopologyController", eventRecorder)
+	if _, err := operatorClientInformer.Informer().AddEventHandler(queueHandler(syncCtx, kueueSpecChanged)); err != nil {
+		return nil, err
+	}
+	// Only node labels affect the node counts of the topologies.
+	if _, err := nodeInformer.Informer().AddEventHandler(queueHandler(syncCtx, func(old, new interface{}) bool {
+		oldNode, ok := old.(*corev1.Node)
+		if !ok {
+			return false
+		}
+		newNode, ok := new.(*corev1.Node)
+		if !ok {
+			return false
+		}
+		return !equality.Semantic.DeepEqual(oldNode.Labels, newNode.Labels)
+	})); err != nil {
+		return nil, err
+	}
+
+	return factory.New().
+		WithSyncContext(syncCtx).
+		WithBareInformers(operatorClientInformer.Informer(), nodeInformer.Informer()).
+		WithSync(func(ctx context.Context, _ factory.SyncContext) error { return c.sync() }).
+		// Restore topologies that were deleted and link flavors created later, nothing
+		// watches them.
+		ResyncEvery(topologyResyncInterval).
+		ToController("TopologyController", c.eventRecorder), nil
+}
+
+func (c *TopologyController) sync() error {
+	kueue, err := c.operatorClient.Kueues(c.operatorNamespace).Get(c.ctx, operatorclient.OperatorConfigName, metav1.GetOptions{})
+	if err != nil {
+		klog.ErrorS(err, "unable to get operator configuration", "namespace", c.operatorNamespace, "kueue", operatorclient.OperatorConfigName)
+		return err
+	}
+
+	var definitions []kueuev1alpha1.TopologyDefinition
+	if kueue.Spec.TopologyAwareScheduling != nil {
+		definitions = kueue.Spec.TopologyAwareScheduling.Topologies
+	}
+	nodes, err := c.nodeLister.List(labels.Everything())
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	statuses := make([]kueuev1alpha1.TopologyStatus, 0, len(definitions))
+	wanted := sets.New[string]()
+	for _, definition := range definitions {
+		wanted.Insert(definition.Name)
+		levels := definition.Levels
+		if len(levels) == 0 {
+			levels = defaultTopologyLevels
+		}
+		status := kueuev1alpha1.TopologyStatus{
+			Name:  definition.Name,
+			Nodes: int32(len(queues.TopologyNodes(nodes, levels))),
+		}
+		if err := c.applyTopology(definition.Name, levels); err != nil {
+			errs = append(errs, err)
+			status.Message = err.Error()
+			statuses = append(statuses, status)
+			continue
+		}
+
+		var messages []string
+		for _, flavor := range definition.ResourceFlavors {
+			linked, message, err := c.linkFlavor(flavor, definition.Name)
+			if err != nil {
+				errs = append(errs, err)
+				messages = append(messages, err.Error())
+				continue
+			}
+			if len(message) > 0 {
+				messages = append(messages, message)
+			}
+			if linked {
+				status.ResourceFlavors = append(status.ResourceFlavors, flavor)
+			}
+		}
+		if status.Nodes == 0 {
+			messages = append(messages, fmt.Sprintf("no node has all the labels %s", strings.Join(levels, ", ")))
+		}
+		status.Message = strings.Join(messages, "; ")
+		statuses = append(statuses, status)
+	}
+
+	existing, err := c.dynamicClient.Resource(queues.TopologiesGVR).List(c.ctx, metav1.ListOptions{LabelSelector: topologyLabel})
+	if err != nil {
+		errs = append(errs, err)
+	} else {
+		for _, topology := range existing.Items {
+			if wanted.Has(topology.GetName()) {
+				continue
+			}
+			if err := c.dynamicClient.Resource(queues.TopologiesGVR).Delete(c.ctx, topology.GetName(), metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+				errs = append(errs, fmt.Errorf("unable to delete Topology %s: %w", topology.GetName(), err))
+				continue
+			}
+			c.eventRecorder.Eventf("TopologyDeleted", "Deleted Topology %s", topology.GetName())
+		}
+	}
+
+	degraded := operatorv1.OperatorCondition{
+		Type:   "TopologyControllerDegraded",
+		Status: operatorv1.ConditionFalse,
+		Reason: "AsExpected",
+	}
+	if len(errs) > 0 {
+		degraded.Status = operatorv1.ConditionTrue
+		degraded.Reason = "SyncFailed"
+		degraded.Message = utilerrors.NewAggregate(errs).Error()
+	}
+	if err := updateKueueStatus(c.ctx, c.operatorClient, c.operatorNamespace, kueue.Name, func(status *kueuev1alpha1.KueueStatus) {
+		status.Topologies = statuses
+		if len(statuses) == 0 {
+			status.Topologies = nil
+		}
+		v1helpers.SetOperatorCondition(&status.Conditions, degraded)
+	}); err != nil {
+		errs = append(errs, err)
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+// applyTopology creates the Topology. The levels of a Topology are immutable, a Topology
+// created by the operator whose levels differ is deleted and created again. The
+// ResourceFlavors refer to it by name and are not affected.
+func (c *TopologyController) applyTopology(name string, levels []string) error {
+	required := queues.BuildTopology(name, levels)
+	required.SetLabels(map[string]string{topologyLabel: "true"})
+	setManagedByLabel(required)
+
+	client := c.dynamicClient.Resource(queues.TopologiesGVR)
+	existing, err := client.Get(c.ctx, name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		if _, err := client.Create(c.ctx, required, metav1.CreateOptions{FieldManager: fieldManager}); err != nil {
+			return fmt.Errorf("unable to create Topology %s: %w", name, err)
+		}
+		c.eventRecorder.Eventf("TopologyCreated", "Created Topology %s with levels %s", name, strings.Join(levels, ", "))
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if _, ok := existing.GetLabels()[topologyLabel]; !ok {
+		return fmt.Errorf("Topology %s exists and is not managed by the operator", name)
+	}
+	if equality.Semantic.DeepEqual(queues.TopologyLevels(existing), levels) {
+		return nil
+	}
+	if err := client.Delete(c.ctx, name, metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("unable to delete Topology %s: %w", name, err)
+	}
+	return c.applyTopology(name, levels)
+}
+
+// linkFlavor sets the topologyName of the ResourceFlavor. It returns false and a message
+// when the flavor cannot use the topology.
+func (c *TopologyController) linkFlavor(name, topology string) (bool, string, error) {
+	client := c.dynamicClient.Resource(queues.ResourceFlavorsGVR)
+	existing, err := client.Get(c.ctx, name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return false, fmt.Sprintf("ResourceFlavor %s not found", name), nil
+	}
+	if err != nil {
+		return false, "", err
+	}
+	flavor := &kueuev1beta1.ResourceFlavor{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(existing.Object, flavor); err != nil {
+		return false, "", err
+	}
+	if flavor.Spec.TopologyName != nil {
+		if string(*flavor.Spec.TopologyName) == topology {
+			return true, "", nil
+		}
+		return false, fmt.Sprintf("ResourceFlavor %s uses Topology %s", name, *flavor.Spec.TopologyName), nil
+	}
+	if len(flavor.Spec.NodeLabels) == 0 {
+		return false, fmt.Sprintf("ResourceFlavor %s has no nodeLabels, which a topology requires", name), nil
+	}
+
+	updated := existing.DeepCopy()
+	if err := unstructured.SetNestedField(updated.Object, topology, "spec", "topologyName"); err != nil {
+		return false, "", err
+	}
+	if _, err := client.Update(c.ctx, updated, metav1.UpdateOptions{FieldManager: fieldManager}); err != nil {
+		return false, "", fmt.Errorf("unable to link ResourceFlavor %s to Topology %s: %w", name, topology, err)
+	}
+	c.eventRecorder.Eventf("ResourceFlavorLinked", "Linked ResourceFlavor %s to Topology %s", name, topology)
+	return true, "", nil
+}
@@ -0,0 +1,133 @@
+package operator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	kueuev1alpha1 "github.com/openshift/kueue-operator/pkg/apis/kueueoperator/v1alpha1"
+	"github.com/openshift/kueue-operator/pkg/builders/queues"
+	"github.com/openshift/kueue-operator/pkg/namespace"
+	"github.com/openshift/kueue-operator/pkg/operator/operatorclient"
+)
+
+const testRackLabel = "example.com/rack"
+
+func newTestTopologyController(t *testing.T, kueue *kueuev1alpha1.Kueue, nodes ...*corev1.Node) *TopologyController {
+	t.Helper()
+	defaults, _ := newTestDefaultsController(t, kueue)
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, node := range nodes {
+		if err := indexer.Add(node); err != nil {
+			t.Fatal(err)
+		}
+	}
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		queues.ResourceFlavorsGVR: "ResourceFlavorList",
+		queues.TopologiesGVR:      "TopologyList",
+	})
+	return &TopologyController{
+		ctx:               defaults.ctx,
+		operatorClient:    defaults.operatorClient,
+		dynamicClient:     dynamicClient,
+		nodeLister:        corev1listers.NewNodeLister(indexer),
+		eventRecorder:     defaults.eventRecorder,
+		operatorNamespace: defaults.operatorNamespace,
+	}
+}
+
+func (c *TopologyController) topologyLevels(t *testing.T, name string) []string {
+	t.Helper()
+	topology, err := c.dynamicClient.Resource(queues.TopologiesGVR).Get(c.ctx, name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return queues.TopologyLevels(topology)
+}
+
+func TestTopologyController(t *testing.T) {
+	rackNode := newTestNode("worker-0", "4", "16Gi", false)
+	rackNode.Labels = map[string]string{corev1.LabelTopologyZone: "zone-a", testRackLabel: "rack-1", corev1.LabelHostname: "worker-0"}
+	kueue := newTestKueue()
+	kueue.Spec.TopologyAwareScheduling = &kueuev1alpha1.TopologyAwareScheduling{
+		Topologies: []kueuev1alpha1.TopologyDefinition{{
+			Name:            "racks",
+			Levels:          []string{corev1.LabelTopologyZone, testRackLabel, corev1.LabelHostname},
+			ResourceFlavors: []string{"gpu", "default-flavor", "missing"},
+		}},
+	}
+	c := newTestTopologyController(t, kueue, rackNode, newTestNode("worker-1", "4", "16Gi", false))
+
+	gpu := queues.BuildResourceFlavor("gpu")
+	gpu.Spec.NodeLabels = map[string]string{"nvidia.com/gpu.product": "A100"}
+	for _, flavor := range []runtime.Object{gpu, queues.BuildResourceFlavor("default-flavor")} {
+		if err := createIfMissing(c.ctx, c.dynamicClient, c.eventRecorder, queues.ResourceFlavorsGVR, flavor); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := c.sync(); err != nil {
+		t.Fatalf("Unexpected sync error: %v", err)
+	}
+
+	if diff := cmp.Diff([]string{corev1.LabelTopologyZone, testRackLabel, corev1.LabelHostname}, c.topologyLevels(t, "racks")); len(diff) != 0 {
+		t.Errorf("Unexpected levels (-want,+got):\n%s", diff)
+	}
+	flavor, err := c.dynamicClient.Resource(queues.ResourceFlavorsGVR).Get(c.ctx, "gpu", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if topologyName, _, _ := unstructured.NestedString(flavor.Object, "spec", "topologyName"); topologyName != "racks" {
+		t.Errorf("Expected the gpu flavor to use the racks topology, got %q", topologyName)
+	}
+
+	kueue, err = c.operatorClient.Kueues(namespace.GetNamespace()).Get(c.ctx, operatorclient.OperatorConfigName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(kueue.Status.Topologies) != 1 {
+		t.Fatalf("Unexpected status.topologies: %v", kueue.Status.Topologies)
+	}
+	status := kueue.Status.Topologies[0]
+	if status.Nodes != 1 || !cmp.Equal(status.ResourceFlavors, []string{"gpu"}) ||
+		!strings.Contains(status.Message, "default-flavor has no nodeLabels") ||
+		!strings.Contains(status.Message, "missing not found") {
+		t.Errorf("Unexpected status: %+v", status)
+	}
+
+	// Changing the levels recreates the Topology, removing it deletes it.
+	kueue.Spec.TopologyAwareScheduling.Topologies[0].Levels = []string{corev1.LabelTopologyZone, corev1.LabelHostname}
+	if _, err := c.operatorClient.Kueues(namespace.GetNamespace()).Update(c.ctx, kueue, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.sync(); err != nil {
+		t.Fatalf("Unexpected sync error: %v", err)
+	}
+	if diff := cmp.Diff([]string{corev1.LabelTopologyZone, corev1.LabelHostname}, c.topologyLevels(t, "racks")); len(diff) != 0 {
+		t.Errorf("Unexpected levels (-want,+got):\n%s", diff)
+	}
+
+	kueue.Spec.TopologyAwareScheduling.Topologies = nil
+	if _, err := c.operatorClient.Kueues(namespace.GetNamespace()).Update(c.ctx, kueue, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.sync(); err != nil {
+		t.Fatalf("Unexpected sync error: %v", err)
+	}
+	topologies, err := c.dynamicClient.Resource(queues.TopologiesGVR).List(c.ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(topologies.Items) != 0 {
+		t.Errorf("Expected the Topology to be deleted, got %v", topologies.Items)
+	}
+}
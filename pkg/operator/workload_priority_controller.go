@@ -0,0 +1,233 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	kueuev1alpha1 "github.com/openshift/kueue-operator/pkg/apis/kueueoperator/v1alpha1"
+	"github.com/openshift/kueue-operator/pkg/builders/queues"
+	kueueconfigclient "github.com/openshift/kueue-operator/pkg/generated/clientset/versioned/typed/kueueoperator/v1alpha1"
+	operatorclientinformers "github.com/openshift/kueue-operator/pkg/generated/informers/externalversions/kueueoperator/v1alpha1"
+	"github.com/openshift/kueue-operator/pkg/namespace"
+	"github.com/openshift/kueue-operator/pkg/operator/operatorclient"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/dynamic"
+	schedulingv1listers "k8s.io/client-go/listers/scheduling/v1"
+	"k8s.io/klog/v2"
+	kueuev1beta1 "sigs.k8s.io/kueue/apis/kueue/v1beta1"
+)
+
+const (
+	// workloadPriorityClassLabel marks the WorkloadPriorityClasses of the catalogue. Only
+	// these are updated and deleted by the controller.
+	workloadPriorityClassLabel = "kueue.openshift.io/workload-priority-class"
+
+	workloadPriorityResyncInterval = 10 * time.Minute
+)
+
+// WorkloadPriorityController reconciles the catalogue of spec.workloadPriorities of the
+// Kueue CR into WorkloadPriorityClasses, mirroring PriorityClasses when asked to, and
+// deletes the classes it created that left the catalogue.
+type WorkloadPriorityController struct {
+	ctx                 context.Context
+	operatorClient      kueueconfigclient.KueueV1alpha1Interface
+	dynamicClient       dynamic.Interface
+	priorityClassLister schedulingv1listers.PriorityClassLister
+	eventRecorder       events.Recorder
+	operatorNamespace   string
+}
+
+func NewWorkloadPriorityController(
+	ctx context.Context,
+	operatorConfigClient kueueconfigclient.KueueV1alpha1Interface,
+	operatorClientInformer operatorclientinformers.KueueInformer,
+	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
+	dynamicClient dynamic.Interface,
+	eventRecorder events.Recorder,
+) (factory.Controller, error) {
+	priorityClassInformer := kubeInformersForNamespaces.InformersFor("").Scheduling().V1().PriorityClasses()
+	c := &WorkloadPriorityController{
+		ctx:                 ctx,
+		operatorClient:      operatorConfigClient,
+		dynamicClient:       dynamicClient,
+		priorityClassLister: priorityClassInformer.Lister(),
+		eventRecorder:       eventRecorder.WithComponentSuffix("workload-priority-controller"),
+		operatorNamespace:   namespace.GetNamespace(),
+	}
+
+	syncCtx := factory.NewSyncContext("WorkloadPriorityController", eventRecorder)
+	if _, err := operatorClientInformer.Informer().AddEventHandler(queueHandler(syncCtx, kueueSpecChanged)); err != nil {
+		return nil, err
+	}
+
+	return factory.New().
+		WithSyncContext(syncCtx).
+		WithInformers(priorityClassInformer.Informer()).
+		WithBareInformers(operatorClientInformer.Informer()).
+		WithSync(func(ctx context.Context, _ factory.SyncContext) error { return c.sync() }).
+		// Restore classes that were edited or deleted, nothing watches them.
+		ResyncEvery(workloadPriorityResyncInterval).
+		ToController("WorkloadPriorityController", c.eventRecorder), nil
+}
+
+func (c *WorkloadPriorityController) sync() error {
+	kueue, err := c.operatorClient.Kueues(c.operatorNamespace).Get(c.ctx, operatorclient.OperatorConfigName, metav1.GetOptions{})
+	if err != nil {
+		klog.ErrorS(err, "unable to get operator configuration", "namespace", c.operatorNamespace, "kueue", operatorclient.OperatorConfigName)
+		return err
+	}
+
+	catalogue, err := c.catalogue(kueue.Spec.WorkloadPriorities)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	statuses := make([]kueuev1alpha1.WorkloadPriorityClassStatus, 0, len(catalogue))
+	wanted := sets.New[string]()
+	for _, entry := range catalogue {
+		wanted.Insert(entry.class.Name)
+		status := kueuev1alpha1.WorkloadPriorityClassStatus{
+			Name:   entry.class.Name,
+			Value:  entry.class.Value,
+			Source: entry.source,
+		}
+		if err := c.applyWorkloadPriorityClass(entry.class); err != nil {
+			errs = append(errs, err)
+			status.Message = err.Error()
+		}
+		statuses = append(statuses, status)
+	}
+
+	existing, err := c.dynamicClient.Resource(queues.WorkloadPriorityClassesGVR).List(c.ctx, metav1.ListOptions{LabelSelector: workloadPriorityClassLabel})
+	if err != nil {
+		errs = append(errs, err)
+	} else {
+		for _, class := range existing.Items {
+			if wanted.Has(class.GetName()) {
+				continue
+			}
+			if err := c.dynamicClient.Resource(queues.WorkloadPriorityClassesGVR).Delete(c.ctx, class.GetName(), metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+				errs = append(errs, fmt.Errorf("unable to delete WorkloadPriorityClass %s: %w", class.GetName(), err))
+				continue
+			}
+			c.eventRecorder.Eventf("WorkloadPriorityClassDeleted", "Deleted WorkloadPriorityClass %s", class.GetName())
+		}
+	}
+
+	degraded := operatorv1.OperatorCondition{
+		Type:   "WorkloadPriorityControllerDegraded",
+		Status: operatorv1.ConditionFalse,
+		Reason: "AsExpected",
+	}
+	if len(errs) > 0 {
+		degraded.Status = operatorv1.ConditionTrue
+		degraded.Reason = "SyncFailed"
+		degraded.Message = utilerrors.NewAggregate(errs).Error()
+	}
+	if err := updateKueueStatus(c.ctx, c.operatorClient, c.operatorNamespace, kueue.Name, func(status *kueuev1alpha1.KueueStatus) {
+		status.WorkloadPriorityClasses = statuses
+		if len(statuses) == 0 {
+			status.WorkloadPriorityClasses = nil
+		}
+		v1helpers.SetOperatorCondition(&status.Conditions, degraded)
+	}); err != nil {
+		errs = append(errs, err)
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+// catalogueEntry is a WorkloadPriorityClass of the catalogue and where it comes from.
+type catalogueEntry struct {
+	class  *kueuev1beta1.WorkloadPriorityClass
+	source kueuev1alpha1.WorkloadPriorityClassSource
+}
+
+// catalogue returns the classes of the catalogue sorted by name. Classes listed in the
+// spec take precedence over mirrored PriorityClasses of the same name.
+func (c *WorkloadPriorityController) catalogue(priorities *kueuev1alpha1.WorkloadPriorities) ([]catalogueEntry, error) {
+	if priorities == nil {
+		return nil, nil
+	}
+	entries := map[string]catalogueEntry{}
+	if priorities.MirrorPriorityClasses {
+		priorityClasses, err := c.priorityClassLister.List(labels.Everything())
+		if err != nil {
+			return nil, err
+		}
+		for _, priorityClass := range priorityClasses {
+			if strings.HasPrefix(priorityClass.Name, "system-") {
+				continue
+			}
+			entries[priorityClass.Name] = catalogueEntry{
+				class:  queues.BuildWorkloadPriorityClass(priorityClass.Name, priorityClass.Value, priorityClass.Description),
+				source: kueuev1alpha1.WorkloadPriorityClassSourcePriorityClass,
+			}
+		}
+	}
+	for _, tier := range priorities.Classes {
+		entries[tier.Name] = catalogueEntry{
+			class:  queues.BuildWorkloadPriorityClass(tier.Name, tier.Value, tier.Description),
+			source: kueuev1alpha1.WorkloadPriorityClassSourceCatalogue,
+		}
+	}
+
+	catalogue := make([]catalogueEntry, 0, len(entries))
+	for _, name := range sets.List(sets.KeySet(entries)) {
+		catalogue = append(catalogue, entries[name])
+	}
+	return catalogue, nil
+}
+
+// applyWorkloadPriorityClass creates the class or updates the value and description of
+// a class of the catalogue. Classes of the same name created by someone else are left
+// alone and reported.
+func (c *WorkloadPriorityController) applyWorkloadPriorityClass(class *kueuev1beta1.WorkloadPriorityClass) error {
+	class.Labels = map[string]string{workloadPriorityClassLabel: "true"}
+	client := c.dynamicClient.Resource(queues.WorkloadPriorityClassesGVR)
+	existing, err := client.Get(c.ctx, class.Name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return createIfMissing(c.ctx, c.dynamicClient, c.eventRecorder, queues.WorkloadPriorityClassesGVR, class)
+	}
+	if err != nil {
+		return err
+	}
+	if _, ok := existing.GetLabels()[workloadPriorityClassLabel]; !ok {
+		return fmt.Errorf("WorkloadPriorityClass %s exists and is not managed by the operator", class.Name)
+	}
+
+	current := &kueuev1beta1.WorkloadPriorityClass{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(existing.Object, current); err != nil {
+		return err
+	}
+	if current.Value == class.Value && current.Description == class.Description {
+		return nil
+	}
+	updated := existing.DeepCopy()
+	if err := unstructured.SetNestedField(updated.Object, int64(class.Value), "value"); err != nil {
+		return err
+	}
+	if len(class.Description) == 0 {
+		unstructured.RemoveNestedField(updated.Object, "description")
+	} else if err := unstructured.SetNestedField(updated.Object, class.Description, "description"); err != nil {
+		return err
+	}
+	if _, err := client.Update(c.ctx, updated, metav1.UpdateOptions{FieldManager: fieldManager}); err != nil {
+		return fmt.Errorf("unable to update WorkloadPriorityClass %s: %w", class.Name, err)
+	}
+	c.eventRecorder.Eventf("WorkloadPriorityClassUpdated", "Updated WorkloadPriorityClass %s to value %d", class.Name, class.Value)
+	return nil
+}
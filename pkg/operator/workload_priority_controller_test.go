@@ -0,0 +1,123 @@
+package operator
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	schedulingv1listers "k8s.io/client-go/listers/scheduling/v1"
+	"k8s.io/client-go/tools/cache"
+	kueuev1beta1 "sigs.k8s.io/kueue/apis/kueue/v1beta1"
+
+	kueuev1alpha1 "github.com/openshift/kueue-operator/pkg/apis/kueueoperator/v1alpha1"
+	"github.com/openshift/kueue-operator/pkg/builders/queues"
+	"github.com/openshift/kueue-operator/pkg/namespace"
+	"github.com/openshift/kueue-operator/pkg/operator/operatorclient"
+)
+
+func newTestWorkloadPriorityController(t *testing.T, kueue *kueuev1alpha1.Kueue, priorityClasses ...*schedulingv1.PriorityClass) *WorkloadPriorityController {
+	t.Helper()
+	defaults, _ := newTestDefaultsController(t, kueue)
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, priorityClass := range priorityClasses {
+		if err := indexer.Add(priorityClass); err != nil {
+			t.Fatal(err)
+		}
+	}
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		queues.WorkloadPriorityClassesGVR: "WorkloadPriorityClassList",
+	})
+	return &WorkloadPriorityController{
+		ctx:                 defaults.ctx,
+		operatorClient:      defaults.operatorClient,
+		dynamicClient:       dynamicClient,
+		priorityClassLister: schedulingv1listers.NewPriorityClassLister(indexer),
+		eventRecorder:       defaults.eventRecorder,
+		operatorNamespace:   defaults.operatorNamespace,
+	}
+}
+
+func (c *WorkloadPriorityController) workloadPriorityClasses(t *testing.T) []string {
+	t.Helper()
+	list, err := c.dynamicClient.Resource(queues.WorkloadPriorityClassesGVR).List(c.ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, item := range list.Items {
+		class := &kueuev1beta1.WorkloadPriorityClass{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, class); err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, fmt.Sprintf("%s %d %s", class.Name, class.Value, class.Description))
+	}
+	return got
+}
+
+func TestWorkloadPriorityController(t *testing.T) {
+	kueue := newTestKueue()
+	kueue.Spec.WorkloadPriorities = &kueuev1alpha1.WorkloadPriorities{
+		Classes: []kueuev1alpha1.WorkloadPriorityTier{
+			{Name: "production", Value: 1000, Description: "Production training"},
+			{Name: "batch", Value: 100, Description: "Best effort"},
+			{Name: "taken", Value: 10},
+		},
+		MirrorPriorityClasses: true,
+	}
+	c := newTestWorkloadPriorityController(t, kueue,
+		&schedulingv1.PriorityClass{ObjectMeta: metav1.ObjectMeta{Name: "interactive"}, Value: 500, Description: "Notebooks"},
+		&schedulingv1.PriorityClass{ObjectMeta: metav1.ObjectMeta{Name: "batch"}, Value: 1},
+		&schedulingv1.PriorityClass{ObjectMeta: metav1.ObjectMeta{Name: "system-cluster-critical"}, Value: 2000000000},
+	)
+	// A class created by a team is not taken over.
+	if err := createIfMissing(c.ctx, c.dynamicClient, c.eventRecorder, queues.WorkloadPriorityClassesGVR, queues.BuildWorkloadPriorityClass("taken", 5, "")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.sync(); err == nil || !strings.Contains(err.Error(), "taken exists and is not managed") {
+		t.Fatalf("Expected an error about the unmanaged class, got %v", err)
+	}
+	if diff := cmp.Diff([]string{
+		"batch 100 Best effort",
+		"interactive 500 Notebooks",
+		"production 1000 Production training",
+		"taken 5 ",
+	}, c.workloadPriorityClasses(t)); len(diff) != 0 {
+		t.Errorf("Unexpected WorkloadPriorityClasses (-want,+got):\n%s", diff)
+	}
+	kueue, err := c.operatorClient.Kueues(namespace.GetNamespace()).Get(c.ctx, operatorclient.OperatorConfigName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]kueuev1alpha1.WorkloadPriorityClassStatus{
+		{Name: "batch", Value: 100, Source: kueuev1alpha1.WorkloadPriorityClassSourceCatalogue},
+		{Name: "interactive", Value: 500, Source: kueuev1alpha1.WorkloadPriorityClassSourcePriorityClass},
+		{Name: "production", Value: 1000, Source: kueuev1alpha1.WorkloadPriorityClassSourceCatalogue},
+		{Name: "taken", Value: 10, Source: kueuev1alpha1.WorkloadPriorityClassSourceCatalogue, Message: "WorkloadPriorityClass taken exists and is not managed by the operator"},
+	}, kueue.Status.WorkloadPriorityClasses); len(diff) != 0 {
+		t.Errorf("Unexpected status.workloadPriorityClasses (-want,+got):\n%s", diff)
+	}
+
+	// Changed values are applied and removed entries are pruned.
+	kueue.Spec.WorkloadPriorities = &kueuev1alpha1.WorkloadPriorities{
+		Classes: []kueuev1alpha1.WorkloadPriorityTier{{Name: "production", Value: 2000}},
+	}
+	if _, err := c.operatorClient.Kueues(namespace.GetNamespace()).Update(c.ctx, kueue, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.sync(); err != nil {
+		t.Fatalf("Unexpected sync error: %v", err)
+	}
+	if diff := cmp.Diff([]string{
+		"production 2000 ",
+		"taken 5 ",
+	}, c.workloadPriorityClasses(t)); len(diff) != 0 {
+		t.Errorf("Unexpected WorkloadPriorityClasses (-want,+got):\n%s", diff)
+	}
+}